@@ -28,6 +28,8 @@ var transferOnlyModeBlackList = []string{
 	issue.MintMsg{}.Type(),
 	order.NewOrderMsg{}.Type(),
 	order.CancelOrderMsg{}.Type(),
+	order.AmendOrderMsg{}.Type(),
+	order.CancelAllOrdersMsg{}.Type(),
 	timelock.TimeLockMsg{}.Type(),
 	timelock.TimeUnlockMsg{}.Type(),
 	timelock.TimeRelockMsg{}.Type(),
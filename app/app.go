@@ -335,6 +335,8 @@ func SetUpgradeConfig(upgradeConfig *config.UpgradeConfig) {
 	upgrade.Mgr.AddUpgradeHeight(upgrade.BEP128, upgradeConfig.BEP128Height)
 	upgrade.Mgr.AddUpgradeHeight(upgrade.BEP151, upgradeConfig.BEP151Height)
 	upgrade.Mgr.AddUpgradeHeight(upgrade.BEP153, upgradeConfig.BEP153Height)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.DexEnhancements, upgradeConfig.DexEnhancementsHeight)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.OrderSizeLimits, upgradeConfig.OrderSizeLimitsHeight)
 
 	// register store keys of upgrade
 	upgrade.Mgr.RegisterStoreKeys(upgrade.BEP9, common.TimeLockStoreKey.Name())
@@ -381,6 +383,10 @@ func SetUpgradeConfig(upgradeConfig *config.UpgradeConfig) {
 	)
 
 	upgrade.Mgr.RegisterMsgTypes(upgrade.BEP82, ownership.TransferOwnershipMsg{}.Type())
+	upgrade.Mgr.RegisterMsgTypes(upgrade.DexEnhancements,
+		order.AmendOrderMsg{}.Type(),
+		order.CancelAllOrdersMsg{}.Type(),
+	)
 }
 
 func getABCIQueryBlackList(queryConfig *config.QueryConfig) map[string]bool {
@@ -404,12 +410,29 @@ func (app *BinanceChain) initDex() {
 		app.RegisterCodespace(dex.DefaultCodespace), app.baseConfig.OrderKeeperConcurrency, app.Codec,
 		app.publicationConfig.ShouldPublishAny())
 	app.DexKeeper.SubscribeParamChange(app.ParamHub)
+	app.DexKeeper.SetPbsbServer(app.psServer)
 	app.DexKeeper.SetBUSDSymbol(app.dexConfig.BUSDSymbol)
+	for _, pair := range app.dexConfig.OrderBookOnBTreePairs {
+		if err := app.DexKeeper.SetOrderBookType(pair, dex.OrderBookTypeBTree); err != nil {
+			cmn.Exit(err.Error())
+		}
+	}
 
 	// do not proceed if we are in a unit test and `CheckState` is unset.
 	if app.CheckState == nil {
 		return
 	}
+	if app.dexConfig.KlineEnabled || app.publicationConfig.PublishKline {
+		// the klines are kept apart from the application state, so the db lives as long as the app
+		app.DexKeeper.SetKlineStore(baseapp.LoadDB("klines"))
+	}
+	if app.dexConfig.OrderHistoryEnabled && app.publicationConfig.ShouldPublishAny() {
+		// the order history is recorded from the data collected for publication
+		app.DexKeeper.SetOrderHistoryStore(baseapp.LoadDB("orderhistory"))
+	}
+	if app.dexConfig.RecentTradesEnabled {
+		app.DexKeeper.SetRecentTradesStore(func() dbm.DB { return baseapp.LoadDB("recenttrades") })
+	}
 	// count back to days in config.
 	blockDB := baseapp.LoadBlockDB()
 	defer blockDB.Close()
@@ -633,15 +656,19 @@ func (app *BinanceChain) initIbc() {
 func (app *BinanceChain) initGovHooks() {
 	listHooks := list.NewListHooks(app.DexKeeper, app.TokenMapper)
 	feeChangeHooks := paramHub.NewFeeChangeHooks(app.Codec)
+	dexParamsChangeHooks := list.NewDexParamsChangeHooks(app.Codec)
 	cscParamChangeHooks := paramHub.NewCSCParamsChangeHook(app.Codec)
 	scParamChangeHooks := paramHub.NewSCParamsChangeHook(app.Codec)
 	chanPermissionHooks := sidechain.NewChanPermissionSettingHook(app.Codec, &app.scKeeper)
 	delistHooks := list.NewDelistHooks(app.DexKeeper)
+	tradingStatusHooks := list.NewTradingStatusHooks(app.DexKeeper)
 	app.govKeeper.AddHooks(gov.ProposalTypeListTradingPair, listHooks)
 	app.govKeeper.AddHooks(gov.ProposalTypeFeeChange, feeChangeHooks)
+	app.govKeeper.AddHooks(gov.ProposalTypeFeeChange, dexParamsChangeHooks)
 	app.govKeeper.AddHooks(gov.ProposalTypeCSCParamsChange, cscParamChangeHooks)
 	app.govKeeper.AddHooks(gov.ProposalTypeSCParamsChange, scParamChangeHooks)
 	app.govKeeper.AddHooks(gov.ProposalTypeDelistTradingPair, delistHooks)
+	app.govKeeper.AddHooks(list.ProposalTypeTradingStatus, tradingStatusHooks)
 	app.govKeeper.AddHooks(gov.ProposalTypeManageChanPermission, chanPermissionHooks)
 }
 
@@ -830,6 +857,7 @@ func (app *BinanceChain) EndBlocker(ctx sdk.Context, req abci.RequestEndBlock) a
 			app.DexKeeper.MatchAndAllocateSymbols(ctx, nil, isBreatheBlock)
 		}
 	}
+	dex.EndBlock(ctx, app.DexKeeper)
 
 	if isBreatheBlock {
 		// breathe block
@@ -847,6 +875,10 @@ func (app *BinanceChain) EndBlocker(ctx sdk.Context, req abci.RequestEndBlock) a
 	app.DexKeeper.StoreTradePrices(ctx)
 
 	blockFee := distributeFee(ctx, app.AccountKeeper, app.ValAddrCache, app.publicationConfig.PublishBlockFee)
+	if app.publicationConfig.PublishBlockFee {
+		// the rebates paid to the makers are not distributed to the validators but reported along with the fee
+		blockFee.Fee = order.AppendRebatesForPub(blockFee.Fee, app.DexKeeper.GetRoundRebates())
+	}
 
 	passed, failed := gov.EndBlocker(ctx, app.govKeeper)
 	var proposals pub.Proposals
@@ -870,6 +902,10 @@ func (app *BinanceChain) EndBlocker(ctx sdk.Context, req abci.RequestEndBlock) a
 
 	if app.publicationConfig.ShouldPublishAny() &&
 		pub.IsLive {
+		if app.DexKeeper.OrderHistoryEnabled() {
+			// the closed orders have to be recorded before the publication removes their infos
+			app.DexKeeper.RecordClosedOrders(height, blockTime.UnixNano())
+		}
 		stakeUpdates := pub.CollectStakeUpdatesForPublish(completedUbd)
 		if height >= app.publicationConfig.FromHeightInclusive {
 			app.publish(tradesToPublish, &proposals, &sideProposals, &stakeUpdates, blockFee, ctx, height, blockTime.UnixNano())
@@ -1082,6 +1118,7 @@ func (app *BinanceChain) publish(tradesToPublish []*pub.Trade, proposalsToPublis
 	var transferToPublish *pub.Transfers
 	var blockToPublish *pub.Block
 	var latestPriceLevels order.ChangedPriceLevelsMap
+	var klinesToPublish []order.KlineUpdate
 
 	orderChanges := app.DexKeeper.GetAllOrderChanges()
 	orderInfoForPublish := app.DexKeeper.GetAllOrderInfosForPub()
@@ -1109,6 +1146,9 @@ func (app *BinanceChain) publish(tradesToPublish []*pub.Trade, proposalsToPublis
 		if app.publicationConfig.PublishOrderBook {
 			latestPriceLevels = app.DexKeeper.GetOrderBooks(pub.MaxOrderBookLevel)
 		}
+		if app.publicationConfig.PublishKline {
+			klinesToPublish = app.DexKeeper.GetKlineUpdates()
+		}
 	})
 
 	if app.metrics != nil {
@@ -1144,7 +1184,8 @@ func (app *BinanceChain) publish(tradesToPublish []*pub.Trade, proposalsToPublis
 		blockFee,
 		app.DexKeeper.RoundOrderFees, //only use DexKeeper RoundOrderFees
 		transferToPublish,
-		blockToPublish)
+		blockToPublish,
+		klinesToPublish)
 
 	// remove item from OrderInfoForPublish when we published removed order (cancel, iocnofill, fullyfilled, expired)
 	for o := range pub.ToRemoveOrderIdCh {
@@ -91,6 +91,10 @@ BEP128Height = {{ .UpgradeConfig.BEP128Height }}
 BEP151Height = {{ .UpgradeConfig.BEP151Height }}
 # Block height of BEP153 upgrade
 BEP153Height = {{ .UpgradeConfig.BEP153Height }}
+# Block height of DexEnhancements upgrade
+DexEnhancementsHeight = {{ .UpgradeConfig.DexEnhancementsHeight }}
+# Block height of OrderSizeLimits upgrade
+OrderSizeLimitsHeight = {{ .UpgradeConfig.OrderSizeLimitsHeight }}
 
 [query]
 # ABCI query interface black list, suggested value: ["custom/gov/proposals", "custom/timelock/timelocks", "custom/atomicSwap/swapcreator", "custom/atomicSwap/swaprecipient"]
@@ -178,6 +182,16 @@ publishBreatheBlock = {{ .PublicationConfig.PublishBreatheBlock }}
 breatheBlockTopic = "{{ .PublicationConfig.BreatheBlockTopic }}"
 breatheBlockKafka = "{{ .PublicationConfig.BreatheBlockKafka }}"
 
+# Whether we want publish market status changes
+publishMarketStatus = {{ .PublicationConfig.PublishMarketStatus }}
+marketStatusTopic = "{{ .PublicationConfig.MarketStatusTopic }}"
+marketStatusKafka = "{{ .PublicationConfig.MarketStatusKafka }}"
+
+# Whether we want publish klines, which requires klineEnabled under [dex] as well
+publishKline = {{ .PublicationConfig.PublishKline }}
+klineTopic = "{{ .PublicationConfig.KlineTopic }}"
+klineKafka = "{{ .PublicationConfig.KlineKafka }}"
+
 # Global setting
 publicationChannelSize = {{ .PublicationConfig.PublicationChannelSize }}
 publishKafka = {{ .PublicationConfig.PublishKafka }}
@@ -223,6 +237,16 @@ bscIbcChainId = {{ .CrossChainConfig.BscIbcChainId }}
 [dex]
 # The suffixed symbol of BUSD
 BUSDSymbol = "{{ .DexConfig.BUSDSymbol }}"
+# Trading pairs whose order books are kept in a B-tree instead of the default unrolled-linked list,
+# which is faster for very deep and sparse order books, such as ["ADA.B-B63_BNB"]
+orderBookOnBTreePairs = {{ .DexConfig.OrderBookOnBTreePairs }}
+# Whether to maintain the klines of the trading pairs, which are kept in a database out of the consensus state
+klineEnabled = {{ .DexConfig.KlineEnabled }}
+# Whether to keep the history of the closed orders of every address, which is kept in a database out of the consensus state.
+# The history is recorded from the data collected for publication, so it's only kept when any publication is on
+orderHistoryEnabled = {{ .DexConfig.OrderHistoryEnabled }}
+# Whether to keep the latest trades of the trading pairs, which are saved in a database out of the consensus state at breathe blocks
+recentTradesEnabled = {{ .DexConfig.RecentTradesEnabled }}
 `
 
 type BinanceChainContext struct {
@@ -339,6 +363,14 @@ type PublicationConfig struct {
 	BreatheBlockTopic   string `mapstructure:"breatheBlockTopic"`
 	BreatheBlockKafka   string `mapstructure:"breatheBlockKafka"`
 
+	PublishMarketStatus bool   `mapstructure:"publishMarketStatus"`
+	MarketStatusTopic   string `mapstructure:"marketStatusTopic"`
+	MarketStatusKafka   string `mapstructure:"marketStatusKafka"`
+
+	PublishKline bool   `mapstructure:"publishKline"`
+	KlineTopic   string `mapstructure:"klineTopic"`
+	KlineKafka   string `mapstructure:"klineKafka"`
+
 	PublicationChannelSize int `mapstructure:"publicationChannelSize"`
 
 	// DO NOT put this option in config file
@@ -418,6 +450,14 @@ func defaultPublicationConfig() *PublicationConfig {
 		BreatheBlockTopic:   "breatheBlock",
 		BreatheBlockKafka:   "127.0.0.1:9092",
 
+		PublishMarketStatus: false,
+		MarketStatusTopic:   "marketStatus",
+		MarketStatusKafka:   "127.0.0.1:9092",
+
+		PublishKline: false,
+		KlineTopic:   "klines",
+		KlineKafka:   "127.0.0.1:9092",
+
 		PublicationChannelSize: 10000,
 		FromHeightInclusive:    1,
 		PublishKafka:           false,
@@ -448,7 +488,9 @@ func (pubCfg PublicationConfig) ShouldPublishAny() bool {
 		pubCfg.PublishCrossTransfer ||
 		pubCfg.PublishMirror ||
 		pubCfg.PublishSideProposal ||
-		pubCfg.PublishBreatheBlock
+		pubCfg.PublishBreatheBlock ||
+		pubCfg.PublishMarketStatus ||
+		pubCfg.PublishKline
 }
 
 type CrossChainConfig struct {
@@ -532,6 +574,8 @@ type UpgradeConfig struct {
 	BEP128Height                                    int64 `mapstructure:"BEP128Height"`
 	BEP151Height                                    int64 `mapstructure:"BEP151Height"`
 	BEP153Height                                    int64 `mapstructure:"BEP153Height"`
+	DexEnhancementsHeight                           int64 `mapstructure:"DexEnhancementsHeight"`
+	OrderSizeLimitsHeight                           int64 `mapstructure:"OrderSizeLimitsHeight"`
 }
 
 func defaultUpgradeConfig() *UpgradeConfig {
@@ -559,6 +603,8 @@ func defaultUpgradeConfig() *UpgradeConfig {
 		BEP87Height:                math.MaxInt64,
 		FixFailAckPackageHeight:    math.MaxInt64,
 		EnableAccountScriptsForCrossChainTransferHeight: math.MaxInt64,
+		DexEnhancementsHeight:                           math.MaxInt64,
+		OrderSizeLimitsHeight:                           math.MaxInt64,
 	}
 }
 
@@ -573,12 +619,20 @@ func defaultQueryConfig() *QueryConfig {
 }
 
 type DexConfig struct {
-	BUSDSymbol string `mapstructure:"BUSDSymbol"`
+	BUSDSymbol            string   `mapstructure:"BUSDSymbol"`
+	OrderBookOnBTreePairs []string `mapstructure:"orderBookOnBTreePairs"`
+	KlineEnabled          bool     `mapstructure:"klineEnabled"`
+	OrderHistoryEnabled   bool     `mapstructure:"orderHistoryEnabled"`
+	RecentTradesEnabled   bool     `mapstructure:"recentTradesEnabled"`
 }
 
 func defaultGovConfig() *DexConfig {
 	return &DexConfig{
-		BUSDSymbol: "",
+		BUSDSymbol:            "",
+		OrderBookOnBTreePairs: nil,
+		KlineEnabled:          false,
+		OrderHistoryEnabled:   false,
+		RecentTradesEnabled:   false,
 	}
 }
 
@@ -150,6 +150,10 @@ func (app *BinanceChain) processErrAbciResponseForPub(txBytes []byte) {
 				// The error on deliver should be rare and only impact witness publisher's performance
 				// OrderInfo must has been in keeper.orderInfosForPub
 				app.DexKeeper.UpdateOrderChangeSync(order.OrderChange{Id: msg.RefId, Tpe: order.FailedBlocking, MsgForFailedTx: msg}, msg.Symbol)
+			case order.AmendOrderMsg:
+				app.Logger.Info("failed to process AmendOrderMsg", "oid", msg.RefId)
+				// OrderInfo must has been in keeper.orderInfosForPub
+				app.DexKeeper.UpdateOrderChangeSync(order.OrderChange{Id: msg.RefId, Tpe: order.FailedBlocking, MsgForFailedTx: msg}, msg.Symbol)
 			default:
 				// deliberately do nothing for message other than NewOrderMsg
 				// in future, we may publish fail status of send msg
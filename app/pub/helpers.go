@@ -113,6 +113,11 @@ func GetBlockPublished(pool *sdk.Pool, header abci.Header, blockHash []byte) *Bl
 		case orderPkg.CancelOrderMsg:
 			orderId = msg.RefId
 			txAsset = msg.Symbol
+		case orderPkg.AmendOrderMsg:
+			orderId = msg.RefId
+			txAsset = msg.Symbol
+		case orderPkg.CancelAllOrdersMsg:
+			txAsset = msg.Symbol
 		case bank.MsgSend:
 			// TODO for now there is no requirement to support multi send message, will support multi send in issue #680
 			txAsset = msg.Inputs[0].Coins[0].Denom
@@ -279,7 +284,14 @@ func MatchAndAllocateAllForPublish(dexKeeper *orderPkg.DexKeeper, ctx sdk.Contex
 	go updateExpireFeeForPublish(dexKeeper, &wg, iocExpireFeeHolderCh)
 	var postAlloTransHandler = func(tran orderPkg.Transfer) {
 		if tran.IsExpire() {
-			if tran.IsExpiredWithFee() {
+			if tran.IsFOKExpire() {
+				iocExpireFeeHolderCh <- orderPkg.ExpireHolder{
+					OrderId: tran.Oid,
+					Reason:  orderPkg.FokNoFill,
+					Fee:     tran.Fee.String(),
+					Symbol:  tran.Symbol,
+				}
+			} else if tran.IsExpiredWithFee() {
 				// we only got expire of Ioc here, gte orders expire is handled in breathe block
 				iocExpireFeeHolderCh <- orderPkg.ExpireHolder{
 					OrderId: tran.Oid,
@@ -314,16 +326,8 @@ func extractTradesToPublish(dexKeeper *orderPkg.DexKeeper, tradeHeight int64) (t
 	for symbol := range dexKeeper.GetEngines() {
 		matchEngTrades, _ := dexKeeper.GetLastTrades(tradeHeight, symbol)
 		for _, trade := range matchEngTrades {
-			var ssinglefee string
-			var bsinglefee string
-			// nilness check is for before Galileo upgrade the trade fee is nil
-			if trade.SellerFee != nil {
-				ssinglefee = trade.SellerFee.String()
-			}
-			// nilness check is for before Galileo upgrade the trade fee is nil
-			if trade.BuyerFee != nil {
-				bsinglefee = trade.BuyerFee.String()
-			}
+			ssinglefee := dexKeeper.SerializeTradeFeeForPub(&trade, false)
+			bsinglefee := dexKeeper.SerializeTradeFeeForPub(&trade, true)
 
 			t := &Trade{
 				Id:         fmt.Sprintf("%d-%d", tradeHeight, tradeIdx),
@@ -361,6 +365,21 @@ func ExpireOrdersForPublish(
 	wg.Wait()
 }
 
+func ExpireGoodTillOrdersForPublish(dexKeeper *orderPkg.DexKeeper, ctx sdk.Context) {
+	expireHolderCh := make(chan orderPkg.ExpireHolder, TransferCollectionChannelSize)
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go updateExpireFeeForPublish(dexKeeper, &wg, expireHolderCh)
+	var collectorForExpires = func(tran orderPkg.Transfer) {
+		if tran.IsExpire() {
+			expireHolderCh <- orderPkg.ExpireHolder{OrderId: tran.Oid, Reason: orderPkg.Expired, Fee: tran.Fee.String(), Symbol: tran.Symbol}
+		}
+	}
+	dexKeeper.ExpireGoodTillOrders(ctx, collectorForExpires)
+	close(expireHolderCh)
+	wg.Wait()
+}
+
 func DelistTradingPairForPublish(ctx sdk.Context, dexKeeper *orderPkg.DexKeeper, symbol string) {
 	expireHolderCh := make(chan orderPkg.ExpireHolder, TransferCollectionChannelSize)
 	wg := sync.WaitGroup{}
@@ -431,9 +450,11 @@ func filterChangedOrderBooksByOrders(
 	// map from symbol -> price -> qty diff in this block
 	var buyQtyDiff = make(map[string]map[int64]int64)
 	var sellQtyDiff = make(map[string]map[int64]int64)
+	// the levels touched by iceberg or amended orders are always kept, as the qty diff cannot tell their changes
+	var buyKeptLevels = make(map[string]map[int64]bool)
+	var sellKeptLevels = make(map[string]map[int64]bool)
 	var allSymbols = make(map[string]struct{})
 	for _, o := range ordersToPublish {
-		price := o.Price
 		symbol := o.Symbol
 
 		if _, ok := latestPriceLevels[symbol]; !ok {
@@ -444,37 +465,47 @@ func filterChangedOrderBooksByOrders(
 			res[symbol] = orderPkg.ChangedPriceLevelsPerSymbol{Buys: make(map[int64]int64), Sells: make(map[int64]int64)}
 			buyQtyDiff[symbol] = make(map[int64]int64)
 			sellQtyDiff[symbol] = make(map[int64]int64)
+			buyKeptLevels[symbol] = make(map[int64]bool)
+			sellKeptLevels[symbol] = make(map[int64]bool)
 		}
 
-		switch o.Side {
-		case orderPkg.Side.BUY:
-			if qty, ok := latestPriceLevels[symbol].Buys[price]; ok {
-				res[symbol].Buys[price] = qty
-			} else {
-				res[symbol].Buys[price] = 0
-			}
-			buyQtyDiff[symbol][price] += o.effectQtyToOrderBook()
-		case orderPkg.Side.SELL:
-			if qty, ok := latestPriceLevels[symbol].Sells[price]; ok {
-				res[symbol].Sells[price] = qty
-			} else {
-				res[symbol].Sells[price] = 0
+		touchLevel := func(price, qtyDiff int64, keep bool) {
+			switch o.Side {
+			case orderPkg.Side.BUY:
+				if qty, ok := latestPriceLevels[symbol].Buys[price]; ok {
+					res[symbol].Buys[price] = qty
+				} else {
+					res[symbol].Buys[price] = 0
+				}
+				buyQtyDiff[symbol][price] += qtyDiff
+				buyKeptLevels[symbol][price] = buyKeptLevels[symbol][price] || keep
+			case orderPkg.Side.SELL:
+				if qty, ok := latestPriceLevels[symbol].Sells[price]; ok {
+					res[symbol].Sells[price] = qty
+				} else {
+					res[symbol].Sells[price] = 0
+				}
+				sellQtyDiff[symbol][price] += qtyDiff
+				sellKeptLevels[symbol][price] = sellKeptLevels[symbol][price] || keep
 			}
-			sellQtyDiff[symbol][price] += o.effectQtyToOrderBook()
+		}
+		touchLevel(o.Price, o.effectQtyToOrderBook(), o.isIceberg() || o.Status == orderPkg.Amended)
+		if o.Status == orderPkg.Amended && o.amendedFromPrice != o.Price {
+			touchLevel(o.amendedFromPrice, 0, true)
 		}
 	}
 
 	// filter touched but qty actually not changed price levels
 	for symbol, priceToQty := range buyQtyDiff {
 		for price, qty := range priceToQty {
-			if qty == 0 {
+			if qty == 0 && !buyKeptLevels[symbol][price] {
 				delete(res[symbol].Buys, price)
 			}
 		}
 	}
 	for symbol, priceToQty := range sellQtyDiff {
 		for price, qty := range priceToQty {
-			if qty == 0 {
+			if qty == 0 && !sellKeptLevels[symbol][price] {
 				delete(res[symbol].Sells, price)
 			}
 		}
@@ -504,7 +535,7 @@ func tradeToOrder(t *Trade, o *orderPkg.OrderInfo, timestamp int64, feeHolder or
 		t.Id,
 		owner.String(),
 		o.Side,
-		orderPkg.OrderType.LIMIT,
+		o.OrderType,
 		o.Price,
 		o.Quantity,
 		t.Price,
@@ -517,6 +548,8 @@ func tradeToOrder(t *Trade, o *orderPkg.OrderInfo, timestamp int64, feeHolder or
 		orderPkg.NEW,
 		o.TxHash,
 		"",
+		o.DisplayQty,
+		0,
 	}
 	if o.Side == orderPkg.Side.BUY {
 		res.SingleFee = t.BSingleFee
@@ -617,10 +650,13 @@ func collectOrders(orderChanges orderPkg.OrderChanges, orderInfos orderPkg.Order
 			orderToPublish := Order{
 				orderInfo.Symbol, o.Tpe, o.Id,
 				"", orderInfo.Sender.String(), orderInfo.Side,
-				orderPkg.OrderType.LIMIT, orderInfo.Price, orderInfo.Quantity,
+				orderInfo.OrderType, orderInfo.Price, orderInfo.Quantity,
 				0, 0, orderInfo.CumQty, "",
 				orderInfo.CreatedTimestamp, timestamp, orderInfo.TimeInForce,
-				orderPkg.NEW, orderInfo.TxHash, o.SingleFee,
+				orderPkg.NEW, orderInfo.TxHash, o.SingleFee, orderInfo.DisplayQty, 0,
+			}
+			if origOrd, ok := o.MsgForFailedTx.(orderPkg.OrderInfo); ok && o.Tpe == orderPkg.Amended {
+				orderToPublish.amendedFromPrice = origOrd.Price
 			}
 
 			if o.Tpe.IsOpen() {
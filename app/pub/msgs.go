@@ -27,6 +27,8 @@ const (
 	mirrorTpe
 	sideProposalType
 	breatheBlockTpe
+	marketStatusTpe
+	klinesTpe
 )
 
 var (
@@ -64,6 +66,10 @@ func (this msgType) String() string {
 		return "SideProposal"
 	case breatheBlockTpe:
 		return "BreatheBlock"
+	case marketStatusTpe:
+		return "MarketStatus"
+	case klinesTpe:
+		return "Klines"
 	default:
 		return "Unknown"
 	}
@@ -87,6 +93,8 @@ var latestSchemaVersions = map[msgType]int{
 	mirrorTpe:          0,
 	sideProposalType:   0,
 	breatheBlockTpe:    0,
+	marketStatusTpe:    0,
+	klinesTpe:          0,
 }
 
 type AvroOrJsonMsg interface {
@@ -306,21 +314,33 @@ type Order struct {
 	CurrentExecutionType orderPkg.ExecutionType
 	TxHash               string
 	SingleFee            string // fee for this order update - ADDED Galileo
+	displayQty           int64  // visible slice of an iceberg order, not published
+	amendedFromPrice     int64  // price of an amended order before the amendment, not published
 }
 
 func (msg *Order) String() string {
 	return fmt.Sprintf("Order: %v", msg.toNativeMap())
 }
 
+func (msg *Order) isIceberg() bool {
+	return msg.displayQty > 0
+}
+
 func (msg *Order) effectQtyToOrderBook() int64 {
 	switch msg.Status {
-	case orderPkg.Ack:
+	case orderPkg.Ack, orderPkg.StopTriggered:
 		return msg.Qty
 	case orderPkg.FullyFill, orderPkg.PartialFill:
 		return -msg.LastExecutedQty
-	case orderPkg.Expired, orderPkg.IocExpire, orderPkg.IocNoFill, orderPkg.Canceled, orderPkg.FailedMatching:
+	case orderPkg.Expired, orderPkg.IocExpire, orderPkg.IocNoFill, orderPkg.Canceled, orderPkg.FailedMatching,
+		orderPkg.PostOnlyRejected, orderPkg.FokNoFill, orderPkg.SelfTradePrevented:
+		if msg.OrderType == orderPkg.OrderType.STOP_LIMIT {
+			// untriggered stop order never entered the order book
+			return 0
+		}
 		return msg.CumQty - msg.Qty // deliberated be negative value
-	case orderPkg.FailedBlocking:
+	case orderPkg.FailedBlocking, orderPkg.StopPlaced, orderPkg.Amended:
+		// the levels touched by an amendment are always published
 		return 0
 	default:
 		Logger.Error("does not supported order status", "order", msg.String())
@@ -357,7 +377,7 @@ func (msg Order) isChargedCancel() bool {
 }
 
 func (msg Order) isChargedExpire() bool {
-	return msg.CumQty == 0 && (msg.Status == orderPkg.IocNoFill || msg.Status == orderPkg.Expired)
+	return msg.CumQty == 0 && (msg.Status == orderPkg.IocNoFill || msg.Status == orderPkg.FokNoFill || msg.Status == orderPkg.Expired)
 }
 
 type Proposals struct {
@@ -0,0 +1,63 @@
+package pub
+
+import "fmt"
+
+type Kline struct {
+	Symbol      string
+	Interval    string
+	OpenTime    int64
+	CloseTime   int64
+	Open        int64
+	High        int64
+	Low         int64
+	Close       int64
+	Volume      int64
+	QuoteVolume int64
+	NumOfTrades int64
+}
+
+func (msg Kline) String() string {
+	return fmt.Sprintf("Kline: symbol: %s, interval: %s, openTime: %d, open: %d, high: %d, low: %d, close: %d, volume: %d",
+		msg.Symbol, msg.Interval, msg.OpenTime, msg.Open, msg.High, msg.Low, msg.Close, msg.Volume)
+}
+
+func (msg Kline) ToNativeMap() map[string]interface{} {
+	var native = make(map[string]interface{})
+	native["symbol"] = msg.Symbol
+	native["interval"] = msg.Interval
+	native["openTime"] = msg.OpenTime
+	native["closeTime"] = msg.CloseTime
+	native["open"] = msg.Open
+	native["high"] = msg.High
+	native["low"] = msg.Low
+	native["close"] = msg.Close
+	native["volume"] = msg.Volume
+	native["quoteVolume"] = msg.QuoteVolume
+	native["numOfTrades"] = msg.NumOfTrades
+	return native
+}
+
+// deliberated not implemented Ess
+type Klines struct {
+	Height    int64
+	Timestamp int64
+	NumOfMsgs int
+	Klines    []Kline
+}
+
+func (msg Klines) String() string {
+	return fmt.Sprintf("Klines in block %d, numOfMsgs: %d", msg.Height, msg.NumOfMsgs)
+}
+
+func (msg Klines) ToNativeMap() map[string]interface{} {
+	var native = make(map[string]interface{})
+	native["height"] = msg.Height
+	native["timestamp"] = msg.Timestamp
+	native["numOfMsgs"] = msg.NumOfMsgs
+	klines := make([]map[string]interface{}, len(msg.Klines))
+	for idx, k := range msg.Klines {
+		klines[idx] = k.ToNativeMap()
+	}
+	native["klines"] = klines
+	return native
+}
@@ -0,0 +1,56 @@
+package pub
+
+import "fmt"
+
+// the statuses of the matching of a pair changed by the circuit breaker,
+// besides the trading statuses changed by the proposals
+const (
+	MarketStatusSuspended = "suspended"
+	MarketStatusResumed   = "resumed"
+)
+
+type MarketStatus struct {
+	Symbol       string
+	Status       string
+	ProposalId   int64
+	ResumeHeight int64 // the height the matching is resumed at, only for the suspended status
+}
+
+func (msg MarketStatus) String() string {
+	return fmt.Sprintf("MarketStatus: symbol: %s, status: %s, proposalId: %d, resumeHeight: %d",
+		msg.Symbol, msg.Status, msg.ProposalId, msg.ResumeHeight)
+}
+
+func (msg MarketStatus) ToNativeMap() map[string]interface{} {
+	var native = make(map[string]interface{})
+	native["symbol"] = msg.Symbol
+	native["status"] = msg.Status
+	native["proposalId"] = msg.ProposalId
+	native["resumeHeight"] = msg.ResumeHeight
+	return native
+}
+
+// deliberated not implemented Ess
+type MarketStatuses struct {
+	Height    int64
+	Timestamp int64
+	NumOfMsgs int
+	Statuses  []MarketStatus
+}
+
+func (msg MarketStatuses) String() string {
+	return fmt.Sprintf("MarketStatuses in block %d, numOfMsgs: %d", msg.Height, msg.NumOfMsgs)
+}
+
+func (msg MarketStatuses) ToNativeMap() map[string]interface{} {
+	var native = make(map[string]interface{})
+	native["height"] = msg.Height
+	native["timestamp"] = msg.Timestamp
+	native["numOfMsgs"] = msg.NumOfMsgs
+	statuses := make([]map[string]interface{}, len(msg.Statuses))
+	for idx, s := range msg.Statuses {
+		statuses[idx] = s.ToNativeMap()
+	}
+	native["statuses"] = statuses
+	return native
+}
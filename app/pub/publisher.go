@@ -429,6 +429,35 @@ func PublishEvent(
 			}
 			publisher.publish(&breatheBlockMsg, breatheBlockTpe, toPublish.Height, toPublish.Timestamp.UnixNano())
 		}
+
+		if cfg.PublishMarketStatus && len(eventData.MarketStatusData)+len(eventData.CircuitBreakerData) > 0 {
+			statuses := make([]MarketStatus, 0, len(eventData.MarketStatusData)+len(eventData.CircuitBreakerData))
+			for _, event := range eventData.MarketStatusData {
+				statuses = append(statuses, MarketStatus{
+					Symbol:     event.Symbol,
+					Status:     event.Status.String(),
+					ProposalId: event.ProposalId,
+				})
+			}
+			for _, event := range eventData.CircuitBreakerData {
+				status := MarketStatusResumed
+				if event.Suspended {
+					status = MarketStatusSuspended
+				}
+				statuses = append(statuses, MarketStatus{
+					Symbol:       event.Symbol,
+					Status:       status,
+					ResumeHeight: event.ResumeHeight,
+				})
+			}
+			marketStatusMsg := MarketStatuses{
+				Height:    toPublish.Height,
+				Timestamp: toPublish.Timestamp.Unix(),
+				NumOfMsgs: len(statuses),
+				Statuses:  statuses,
+			}
+			publisher.publish(&marketStatusMsg, marketStatusTpe, toPublish.Height, toPublish.Timestamp.UnixNano())
+		}
 	}
 }
 
@@ -552,6 +581,12 @@ func Publish(
 				}
 			}
 
+			if cfg.PublishKline {
+				Timer(Logger, "publish klines", func() {
+					publishKlines(publisher, marketData.height, marketData.timestamp, marketData.klines)
+				})
+			}
+
 			if cfg.PublishSideProposal {
 				duration := Timer(Logger, "publish side chain proposal", func() {
 					publishSideProposals(publisher, marketData.height, marketData.timestamp, marketData.sideProposals)
@@ -682,6 +717,30 @@ func publishSideProposals(publisher MarketDataPublisher, height, timestamp int64
 	}
 }
 
+func publishKlines(publisher MarketDataPublisher, height, timestamp int64, updates []orderPkg.KlineUpdate) {
+	if len(updates) == 0 {
+		return
+	}
+	klines := make([]Kline, len(updates))
+	for i, u := range updates {
+		klines[i] = Kline{
+			Symbol:      u.Symbol,
+			Interval:    u.Interval,
+			OpenTime:    u.OpenTime,
+			CloseTime:   u.CloseTime,
+			Open:        u.Open.ToInt64(),
+			High:        u.High.ToInt64(),
+			Low:         u.Low.ToInt64(),
+			Close:       u.Close.ToInt64(),
+			Volume:      u.Volume.ToInt64(),
+			QuoteVolume: u.QuoteVolume.ToInt64(),
+			NumOfTrades: u.NumOfTrades,
+		}
+	}
+	msg := Klines{height, timestamp, len(klines), klines}
+	publisher.publish(&msg, klinesTpe, height, timestamp)
+}
+
 func publishBlock(publisher MarketDataPublisher, height, timestamp int64, block *Block) {
 	if block != nil {
 		publisher.publish(block, blockTpe, height, timestamp)
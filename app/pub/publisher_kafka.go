@@ -37,6 +37,8 @@ type KafkaMarketDataPublisher struct {
 	mirrorCodec           *goavro.Codec
 	sideProposalCodec     *goavro.Codec
 	breatheBlockCodec     *goavro.Codec
+	marketStatusCodec     *goavro.Codec
+	klinesCodec           *goavro.Codec
 
 	failFast         bool
 	essentialLogPath string                         // the path (default to db dir) we write essential file to make up data on kafka error
@@ -214,6 +216,26 @@ func (publisher *KafkaMarketDataPublisher) newProducers() (config *sarama.Config
 			return
 		}
 	}
+	if Cfg.PublishMarketStatus {
+		if _, ok := publisher.producers[Cfg.MarketStatusTopic]; !ok {
+			publisher.producers[Cfg.MarketStatusTopic], err =
+				publisher.connectWithRetry(strings.Split(Cfg.MarketStatusKafka, KafkaBrokerSep), config)
+		}
+		if err != nil {
+			Logger.Error("failed to create market status producer", "err", err)
+			return
+		}
+	}
+	if Cfg.PublishKline {
+		if _, ok := publisher.producers[Cfg.KlineTopic]; !ok {
+			publisher.producers[Cfg.KlineTopic], err =
+				publisher.connectWithRetry(strings.Split(Cfg.KlineKafka, KafkaBrokerSep), config)
+		}
+		if err != nil {
+			Logger.Error("failed to create klines producer", "err", err)
+			return
+		}
+	}
 	return
 }
 
@@ -311,6 +333,10 @@ func (publisher KafkaMarketDataPublisher) resolveTopic(tpe msgType) (topic strin
 		topic = Cfg.SideProposalTopic
 	case breatheBlockTpe:
 		topic = Cfg.BreatheBlockTopic
+	case marketStatusTpe:
+		topic = Cfg.MarketStatusTopic
+	case klinesTpe:
+		topic = Cfg.KlineTopic
 	}
 	return
 }
@@ -403,6 +429,10 @@ func (publisher *KafkaMarketDataPublisher) marshal(msg AvroOrJsonMsg, tpe msgTyp
 		codec = publisher.sideProposalCodec
 	case breatheBlockTpe:
 		codec = publisher.breatheBlockCodec
+	case marketStatusTpe:
+		codec = publisher.marketStatusCodec
+	case klinesTpe:
+		codec = publisher.klinesCodec
 	default:
 		return nil, fmt.Errorf("doesn't support marshal kafka msg tpe: %s", tpe.String())
 	}
@@ -440,6 +470,10 @@ func (publisher *KafkaMarketDataPublisher) initAvroCodecs() (err error) {
 		return err
 	} else if publisher.breatheBlockCodec, err = goavro.NewCodec(breatheBlockSchema); err != nil {
 		return err
+	} else if publisher.marketStatusCodec, err = goavro.NewCodec(marketStatusSchema); err != nil {
+		return err
+	} else if publisher.klinesCodec, err = goavro.NewCodec(klinesSchema); err != nil {
+		return err
 	}
 	return nil
 }
@@ -40,9 +40,9 @@ func TestExecutionResultsMarshaling(t *testing.T) {
 	orders := Orders{
 		NumOfMsgs: 3,
 		Orders: []*Order{
-			{"NNB_BNB", orderPkg.Ack, "b-1", "", "b", orderPkg.Side.BUY, orderPkg.OrderType.LIMIT, 100, 100, 0, 0, 0, "", 100, 100, orderPkg.TimeInForce.GTE, orderPkg.NEW, "", ""},
-			{"NNB_BNB", orderPkg.FullyFill, "b-1", "42-0", "b", orderPkg.Side.BUY, orderPkg.OrderType.LIMIT, 100, 100, 100, 100, 100, "BNB:10;BTC:1", 100, 100, orderPkg.TimeInForce.GTE, orderPkg.NEW, "", "BNB:10;BTC:1"},
-			{"NNB_BNB", orderPkg.FullyFill, "s-1", "42-0", "s", orderPkg.Side.SELL, orderPkg.OrderType.LIMIT, 100, 100, 100, 100, 100, "BNB:8;ETH:1", 99, 99, orderPkg.TimeInForce.GTE, orderPkg.NEW, "", "BNB:8;ETH:1"},
+			{"NNB_BNB", orderPkg.Ack, "b-1", "", "b", orderPkg.Side.BUY, orderPkg.OrderType.LIMIT, 100, 100, 0, 0, 0, "", 100, 100, orderPkg.TimeInForce.GTE, orderPkg.NEW, "", "", 0, 0},
+			{"NNB_BNB", orderPkg.FullyFill, "b-1", "42-0", "b", orderPkg.Side.BUY, orderPkg.OrderType.LIMIT, 100, 100, 100, 100, 100, "BNB:10;BTC:1", 100, 100, orderPkg.TimeInForce.GTE, orderPkg.NEW, "", "BNB:10;BTC:1", 0, 0},
+			{"NNB_BNB", orderPkg.FullyFill, "s-1", "42-0", "s", orderPkg.Side.SELL, orderPkg.OrderType.LIMIT, 100, 100, 100, 100, 100, "BNB:8;ETH:1", 99, 99, orderPkg.TimeInForce.GTE, orderPkg.NEW, "", "BNB:8;ETH:1", 0, 0},
 		},
 	}
 	proposals := Proposals{
@@ -181,6 +181,41 @@ func TestSideProposalMarsha(t *testing.T) {
 	}
 }
 
+func TestMarketStatusMarshal(t *testing.T) {
+	publisher := NewKafkaMarketDataPublisher(Logger, "", false)
+	msg := MarketStatuses{
+		Height:    10,
+		Timestamp: time.Now().Unix(),
+		NumOfMsgs: 3,
+		Statuses: []MarketStatus{
+			{Symbol: "XYZ-000_BNB", Status: "halted", ProposalId: 100},
+			{Symbol: "ABC-000_BNB", Status: "trading", ProposalId: 101},
+			{Symbol: "DEF-000_BNB", Status: MarketStatusSuspended, ResumeHeight: 20},
+		},
+	}
+	_, err := publisher.marshal(&msg, marketStatusTpe)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestKlinesMarshal(t *testing.T) {
+	publisher := NewKafkaMarketDataPublisher(Logger, "", false)
+	msg := Klines{
+		Height:    10,
+		Timestamp: time.Now().Unix(),
+		NumOfMsgs: 2,
+		Klines: []Kline{
+			{Symbol: "XYZ-000_BNB", Interval: "1m", OpenTime: 60, CloseTime: 119, Open: 1e8, High: 2e8, Low: 1e8, Close: 2e8, Volume: 3e8, QuoteVolume: 5e8, NumOfTrades: 2},
+			{Symbol: "XYZ-000_BNB", Interval: "5m", OpenTime: 0, CloseTime: 299, Open: 1e8, High: 2e8, Low: 1e8, Close: 2e8, Volume: 3e8, QuoteVolume: 5e8, NumOfTrades: 2},
+		},
+	}
+	_, err := publisher.marshal(&msg, klinesTpe)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestStakingMarshaling(t *testing.T) {
 	publisher := NewKafkaMarketDataPublisher(Logger, "", false)
 	valAddr, _ := sdk.ValAddressFromBech32("bva1e2y8w2rz957lahwy0y5h3w53sm8d78qexkn3rh")
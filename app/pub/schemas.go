@@ -967,4 +967,69 @@ const (
 			]
 		}
 	`
+
+	marketStatusSchema = `
+		{
+			"type": "record",
+			"name": "MarketStatus",
+			"namespace": "org.binance.dex.model.avro",
+			"fields": [
+				{ "name": "height", "type": "long" },
+				{ "name": "timestamp", "type": "long" },
+				{ "name": "numOfMsgs", "type": "int" },
+				{ "name": "statuses", "type": {
+					"type": "array",
+					"items":
+					{
+						"type": "record",
+						"name": "Status",
+						"namespace": "org.binance.dex.model.avro",
+						"fields": [
+							{ "name": "symbol", "type": "string" },
+							{ "name": "status", "type": "string" },
+							{ "name": "proposalId", "type": "long" },
+							{ "name": "resumeHeight", "type": "long" }
+						]
+					}
+				   }
+				}
+			]
+		}
+	`
+
+	klinesSchema = `
+		{
+			"type": "record",
+			"name": "Klines",
+			"namespace": "org.binance.dex.model.avro",
+			"fields": [
+				{ "name": "height", "type": "long" },
+				{ "name": "timestamp", "type": "long" },
+				{ "name": "numOfMsgs", "type": "int" },
+				{ "name": "klines", "type": {
+					"type": "array",
+					"items":
+					{
+						"type": "record",
+						"name": "Kline",
+						"namespace": "org.binance.dex.model.avro",
+						"fields": [
+							{ "name": "symbol", "type": "string" },
+							{ "name": "interval", "type": "string" },
+							{ "name": "openTime", "type": "long" },
+							{ "name": "closeTime", "type": "long" },
+							{ "name": "open", "type": "long" },
+							{ "name": "high", "type": "long" },
+							{ "name": "low", "type": "long" },
+							{ "name": "close", "type": "long" },
+							{ "name": "volume", "type": "long" },
+							{ "name": "quoteVolume", "type": "long" },
+							{ "name": "numOfTrades", "type": "long" }
+						]
+					}
+				   }
+				}
+			]
+		}
+	`
 )
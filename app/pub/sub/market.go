@@ -0,0 +1,23 @@
+package sub
+
+import (
+	"github.com/cosmos/cosmos-sdk/pubsub"
+
+	dextypes "github.com/bnb-chain/node/plugins/dex/types"
+)
+
+func SubscribeMarketStatusEvent(sub *pubsub.Subscriber) error {
+	err := sub.Subscribe(dextypes.MarketStatusTopic, func(event pubsub.Event) {
+		switch event := event.(type) {
+		case dextypes.MarketStatusEvent:
+			// the event is emitted in the breathe block rather than in a tx, so it is not staged
+			toPublish.EventData.MarketStatusData = append(toPublish.EventData.MarketStatusData, event)
+		case dextypes.CircuitBreakerEvent:
+			// the event is emitted in the matching of the end block
+			toPublish.EventData.CircuitBreakerData = append(toPublish.EventData.CircuitBreakerData, event)
+		default:
+			sub.Logger.Info("unknown event type")
+		}
+	})
+	return err
+}
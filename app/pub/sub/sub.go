@@ -5,6 +5,7 @@ import (
 
 	"github.com/bnb-chain/node/app/config"
 	"github.com/bnb-chain/node/plugins/bridge"
+	dextypes "github.com/bnb-chain/node/plugins/dex/types"
 
 	"github.com/cosmos/cosmos-sdk/pubsub"
 )
@@ -37,6 +38,12 @@ func SubscribeEvent(sub *pubsub.Subscriber, cfg *config.PublicationConfig) error
 		}
 	}
 
+	if cfg.PublishMarketStatus {
+		if err := SubscribeMarketStatusEvent(sub); err != nil {
+			return err
+		}
+	}
+
 	// commit events data from staging area to 'toPublish' when receiving `TxDeliverEvent`, represents the tx is successfully delivered.
 	if err := sub.Subscribe(TxDeliverTopic, func(event pubsub.Event) {
 		switch event.(type) {
@@ -79,6 +86,10 @@ type EventStore struct {
 	CrossTransferData []bridge.CrossTransferEvent
 	// store for mirror topic
 	MirrorData []bridge.MirrorEvent
+	// store for market status topic
+	MarketStatusData []dextypes.MarketStatusEvent
+	// store for the circuit breaker events of market status topic
+	CircuitBreakerData []dextypes.CircuitBreakerEvent
 }
 
 func newEventStore() *EventStore {
@@ -20,6 +20,8 @@ func MakeCodec() *wire.Codec {
 	types.RegisterWire(cdc)
 	cdc.RegisterConcrete(order.NewOrderMsg{}, "dex/NewOrder", nil)
 	cdc.RegisterConcrete(order.CancelOrderMsg{}, "dex/CancelOrder", nil)
+	cdc.RegisterConcrete(order.AmendOrderMsg{}, "dex/AmendOrder", nil)
+	cdc.RegisterConcrete(order.CancelAllOrdersMsg{}, "dex/CancelAllOrders", nil)
 
 	cdc.RegisterConcrete(order.OrderBookSnapshot{}, "dex/OrderBookSnapshot", nil)
 	cdc.RegisterConcrete(order.ActiveOrders{}, "dex/ActiveOrders", nil)
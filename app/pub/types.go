@@ -20,6 +20,7 @@ type BlockInfoToPublish struct {
 	feeHolder          orderPkg.FeeHolder
 	transfers          *Transfers
 	block              *Block
+	klines             []orderPkg.KlineUpdate
 }
 
 func NewBlockInfoToPublish(
@@ -34,7 +35,8 @@ func NewBlockInfoToPublish(
 	accounts map[string]Account,
 	latestPriceLevels orderPkg.ChangedPriceLevelsMap,
 	blockFee BlockFee,
-	feeHolder orderPkg.FeeHolder, transfers *Transfers, block *Block) BlockInfoToPublish {
+	feeHolder orderPkg.FeeHolder, transfers *Transfers, block *Block,
+	klines []orderPkg.KlineUpdate) BlockInfoToPublish {
 	return BlockInfoToPublish{
 		height,
 		timestamp,
@@ -50,5 +52,6 @@ func NewBlockInfoToPublish(
 		feeHolder,
 		transfers,
 		block,
+		klines,
 	}
 }
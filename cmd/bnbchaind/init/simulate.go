@@ -0,0 +1,336 @@
+package init
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	cfg "github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/libs/cli"
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/node"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/server"
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/app"
+	configPkg "github.com/bnb-chain/node/app/config"
+	"github.com/bnb-chain/node/common"
+	"github.com/bnb-chain/node/common/upgrade"
+	me "github.com/bnb-chain/node/plugins/dex/matcheng"
+	"github.com/bnb-chain/node/plugins/dex/order"
+	dexstore "github.com/bnb-chain/node/plugins/dex/store"
+	dexutils "github.com/bnb-chain/node/plugins/dex/utils"
+)
+
+const (
+	flagPair                = "pair"
+	flagSnapshot            = "snapshot"
+	flagEvents              = "events"
+	flagLotSize             = "lot-size"
+	flagPriceLimitPct       = "price-limit-pct"
+	flagSelfTradePrevention = "self-trade-prevention"
+	flagOrderBook           = "order-book"
+
+	simEventNew    = "new"
+	simEventCancel = "cancel"
+)
+
+// SimEvent is an order event replayed by the dex simulator.
+// The events file has one JSON object per line, and the events must be sorted by height.
+type SimEvent struct {
+	Height int64  `json:"height"`
+	Type   string `json:"type"` // "new" or "cancel"
+	Id     string `json:"id"`
+	Side   int8   `json:"side,omitempty"`   // 1 for buy and 2 for sell, only for new orders
+	Price  int64  `json:"price,omitempty"`  // only for new orders
+	Qty    int64  `json:"qty,omitempty"`    // only for new orders
+	Sender string `json:"sender,omitempty"` // only for new orders, the address part of the order id if not set
+}
+
+func DexCmd(ctx *server.Context, cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dex",
+		Short: "Dex tools which work without a running chain",
+	}
+	cmd.AddCommand(SimulateCmd(ctx, cdc))
+	return cmd
+}
+
+func SimulateCmd(ctx *server.Context, cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "simulate --events <file> (--snapshot <file> | --pair <pair> --height <breathe block height>)",
+		Short: "Replay order events on an order book snapshot with the match engine",
+		Long: `Replay order events on an order book snapshot with the match engine, and print the trades of every height and the final order book.
+The snapshot is either read from the application db of the node at a breathe block height, or from a json file of an order book snapshot.
+The events file has one json object per line, such as
+{"height":101,"type":"new","id":"ADDR-1","side":1,"price":100000000,"qty":100000000}
+{"height":102,"type":"cancel","id":"ADDR-1"}`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			appCtx := configPkg.NewDefaultContext()
+			err := appCtx.ParseAppConfigInPlace()
+			if err != nil {
+				return err
+			}
+			app.SetUpgradeConfig(appCtx.BinanceChainConfig.UpgradeConfig)
+
+			pair := viper.GetString(flagPair)
+			var ob order.OrderBookSnapshot
+			var lotSize int64
+			if file := viper.GetString(flagSnapshot); file != "" {
+				bz, err := ioutil.ReadFile(file)
+				if err != nil {
+					return err
+				}
+				if err = json.Unmarshal(bz, &ob); err != nil {
+					return err
+				}
+				lotSize = dexutils.CalcLotSize(ob.LastTradePrice)
+			} else {
+				config := ctx.Config
+				config.SetRoot(viper.GetString(cli.HomeFlag))
+				ob, lotSize, err = loadOrderBookSnapshot(config, cdc, pair, viper.GetInt64(flagHeight))
+				if err != nil {
+					return err
+				}
+			}
+			if viper.GetInt64(flagLotSize) > 0 {
+				lotSize = viper.GetInt64(flagLotSize)
+			}
+
+			events, err := readSimEvents(viper.GetString(flagEvents))
+			if err != nil {
+				return err
+			}
+			stp, err := me.ParseSelfTradePrevention(viper.GetString(flagSelfTradePrevention))
+			if err != nil {
+				return err
+			}
+
+			book, err := me.NewOrderBook(viper.GetString(flagOrderBook))
+			if err != nil {
+				return err
+			}
+
+			eng := me.NewMatchEng(pair, ob.LastTradePrice, lotSize, viper.GetFloat64(flagPriceLimitPct))
+			eng.SelfTradePrevention = stp
+			eng.Book = book
+			sim, err := newSimulator(eng, ob, os.Stdout)
+			if err != nil {
+				return err
+			}
+			return sim.run(events)
+		},
+	}
+
+	cmd.Flags().String(flagEvents, "", "file of the order events to replay")
+	cmd.Flags().String(flagSnapshot, "", "json file of the order book snapshot, instead of reading it from the application db")
+	cmd.Flags().String(flagPair, "", "the trading pair, such as ADA_BNB")
+	cmd.Flags().Int64(flagHeight, 0, "the breathe block height at which the order book snapshot is saved")
+	cmd.Flags().Int64(flagLotSize, 0, "lot size of the match engine, the lot size of the trading pair if not set")
+	cmd.Flags().Float64(flagPriceLimitPct, 0.05, "price limit percentage of the match engine")
+	cmd.Flags().String(flagSelfTradePrevention, "none", "self-trade prevention of the match engine")
+	cmd.Flags().String(flagOrderBook, me.OrderBookTypeULList, "order book implementation of the match engine, ullist or btree")
+	_ = cmd.MarkFlagRequired(flagEvents)
+
+	return cmd
+}
+
+// loadOrderBookSnapshot reads the order book snapshot and the lot size of the pair from the application db
+func loadOrderBookSnapshot(config *cfg.Config, cdc *codec.Codec, pair string, height int64) (order.OrderBookSnapshot, int64, error) {
+	var ob order.OrderBookSnapshot
+	appDB, err := node.DefaultDBProvider(&node.DBContext{ID: "application", Config: config})
+	if err != nil {
+		return ob, 0, err
+	}
+	defer appDB.Close()
+
+	cms := store.NewCommitMultiStore(appDB)
+	for _, name := range common.NonTransientStoreKeyNames {
+		cms.MountStoreWithDB(common.StoreKeyNameMap[name], sdk.StoreTypeIAVL, nil)
+	}
+	if err := cms.LoadLatestVersion(); err != nil {
+		return ob, 0, err
+	}
+	ctx := sdk.NewContext(cms, abci.Header{}, sdk.RunTxModeDeliver, log.NewNopLogger())
+
+	ob, err = order.ReadOrderBookSnapshot(ctx.KVStore(common.DexStoreKey), cdc, height, pair)
+	if err != nil {
+		return ob, 0, err
+	}
+	baseAsset, quoteAsset, err := dexutils.TradingPair2Assets(pair)
+	if err != nil {
+		return ob, 0, err
+	}
+	tradingPair, err := dexstore.NewTradingPairMapper(cdc, common.PairStoreKey).GetTradingPair(ctx, baseAsset, quoteAsset)
+	if err != nil {
+		return ob, 0, err
+	}
+	return ob, tradingPair.LotSize.ToInt64(), nil
+}
+
+func readSimEvents(file string) ([]SimEvent, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	events := make([]SimEvent, 0)
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var event SimEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return nil, fmt.Errorf("invalid event at line %d: %v", line, err)
+		}
+		events = append(events, event)
+	}
+	return events, scanner.Err()
+}
+
+type simOrder struct {
+	side   int8
+	price  int64
+	sender string
+}
+
+// orderIdSender returns the address part of an order id, which is in the form of "<sender hex address>-<sequence>"
+func orderIdSender(id string) string {
+	if i := strings.LastIndex(id, "-"); i > 0 {
+		return id[:i]
+	}
+	return id
+}
+
+// simulator replays order events on a match engine, and matches the order book at every height of the events
+type simulator struct {
+	eng    *me.MatchEng
+	orders map[string]simOrder
+	out    io.Writer
+}
+
+func newSimulator(eng *me.MatchEng, ob order.OrderBookSnapshot, out io.Writer) (*simulator, error) {
+	sim := &simulator{eng, make(map[string]simOrder), out}
+	eng.OrderSender = func(id string) string {
+		return sim.orders[id].sender
+	}
+	insertLevels := func(levels []me.PriceLevel, side int8) error {
+		for i := range levels {
+			if err := eng.Book.InsertPriceLevel(&levels[i], side); err != nil {
+				return err
+			}
+			for _, o := range levels[i].Orders {
+				sim.orders[o.Id] = simOrder{side, levels[i].Price, orderIdSender(o.Id)}
+			}
+		}
+		return nil
+	}
+	if err := insertLevels(ob.Buys, me.BUYSIDE); err != nil {
+		return nil, err
+	}
+	if err := insertLevels(ob.Sells, me.SELLSIDE); err != nil {
+		return nil, err
+	}
+	eng.LastMatchHeight = ob.LastMatchHeight
+	return sim, nil
+}
+
+func (sim *simulator) run(events []SimEvent) error {
+	for i := 0; i < len(events); {
+		height := events[i].Height
+		if height <= sim.eng.LastMatchHeight {
+			return fmt.Errorf("event of height %d is not after the last match height %d", height, sim.eng.LastMatchHeight)
+		}
+		for ; i < len(events) && events[i].Height == height; i++ {
+			if err := sim.apply(events[i]); err != nil {
+				return err
+			}
+		}
+		sim.match(height)
+	}
+	sim.printBook()
+	return nil
+}
+
+func (sim *simulator) apply(event SimEvent) error {
+	switch event.Type {
+	case simEventNew:
+		if _, ok := sim.orders[event.Id]; ok {
+			return fmt.Errorf("duplicated order id %s", event.Id)
+		}
+		if event.Side != me.BUYSIDE && event.Side != me.SELLSIDE {
+			return fmt.Errorf("invalid side %d of order %s", event.Side, event.Id)
+		}
+		if event.Price <= 0 || event.Qty <= 0 || event.Qty%sim.eng.LotSize != 0 {
+			return fmt.Errorf("invalid price %d or quantity %d of order %s, lot size is %d",
+				event.Price, event.Qty, event.Id, sim.eng.LotSize)
+		}
+		if _, err := sim.eng.Book.InsertOrder(event.Id, event.Side, event.Height, event.Price, event.Qty); err != nil {
+			return err
+		}
+		sender := event.Sender
+		if sender == "" {
+			sender = orderIdSender(event.Id)
+		}
+		sim.orders[event.Id] = simOrder{event.Side, event.Price, sender}
+	case simEventCancel:
+		ord, ok := sim.orders[event.Id]
+		if !ok {
+			return fmt.Errorf("order %s to cancel is not found", event.Id)
+		}
+		if _, err := sim.eng.Book.RemoveOrder(event.Id, ord.side, ord.price); err != nil {
+			return err
+		}
+		delete(sim.orders, event.Id)
+	default:
+		return fmt.Errorf("unknown event type %s", event.Type)
+	}
+	return nil
+}
+
+func (sim *simulator) match(height int64) {
+	upgrade.Mgr.SetHeight(height)
+	if !sim.eng.Match(height) {
+		fmt.Fprintf(sim.out, "height %d: match failed\n", height)
+		return
+	}
+	for _, ord := range sim.eng.SelfTradeCanceled {
+		delete(sim.orders, ord.Id)
+		fmt.Fprintf(sim.out, "height %d: order %s canceled by self-trade prevention\n", height, ord.Id)
+	}
+	if len(sim.eng.Trades) == 0 {
+		fmt.Fprintf(sim.out, "height %d: no trade\n", height)
+		return
+	}
+	fmt.Fprintf(sim.out, "height %d: concluded price %d\n", height, sim.eng.LastTradePrice)
+	for _, t := range sim.eng.Trades {
+		fmt.Fprintf(sim.out, "  trade buy %s sell %s price %d qty %d\n", t.Bid, t.Sid, t.LastPx, t.LastQty)
+	}
+	for _, id := range sim.eng.DropFilledOrder() {
+		delete(sim.orders, id)
+	}
+}
+
+func (sim *simulator) printBook() {
+	buys, sells := sim.eng.Book.GetAllLevels()
+	fmt.Fprintln(sim.out, "final order book:")
+	for i := len(sells) - 1; i >= 0; i-- {
+		fmt.Fprintf(sim.out, "  sell price %d qty %d orders %d\n", sells[i].Price, sells[i].TotalLeavesQty(), len(sells[i].Orders))
+	}
+	for _, l := range buys {
+		fmt.Fprintf(sim.out, "  buy  price %d qty %d orders %d\n", l.Price, l.TotalLeavesQty(), len(l.Orders))
+	}
+}
@@ -0,0 +1,78 @@
+package init
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/node/common/upgrade"
+	me "github.com/bnb-chain/node/plugins/dex/matcheng"
+	"github.com/bnb-chain/node/plugins/dex/order"
+)
+
+func TestReadSimEvents(t *testing.T) {
+	dir, err := os.MkdirTemp("", "dex-simulate")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "events.json")
+	content := `{"height":101,"type":"new","id":"ADDR-1","side":1,"price":100000000,"qty":100000000}
+
+{"height":102,"type":"cancel","id":"ADDR-1"}
+`
+	require.Nil(t, os.WriteFile(file, []byte(content), 0644))
+	events, err := readSimEvents(file)
+	require.Nil(t, err)
+	require.Equal(t, []SimEvent{
+		{Height: 101, Type: simEventNew, Id: "ADDR-1", Side: me.BUYSIDE, Price: 1e8, Qty: 1e8},
+		{Height: 102, Type: simEventCancel, Id: "ADDR-1"},
+	}, events)
+
+	require.Nil(t, os.WriteFile(file, []byte("{\"height\":101}\nnot json\n"), 0644))
+	_, err = readSimEvents(file)
+	require.EqualError(t, err, "invalid event at line 2: invalid character 'o' in literal null (expecting 'u')")
+}
+
+func TestSimulator_Run(t *testing.T) {
+	upgrade.Mgr.AddUpgradeHeight(upgrade.BEP19, -1)
+	defer func() {
+		upgrade.Mgr.Config.HeightMap = nil
+	}()
+
+	ob := order.OrderBookSnapshot{
+		Buys: []me.PriceLevel{
+			{Price: 99e6, Orders: []me.OrderPart{{Id: "A-1", Time: 90, Qty: 1e8}}},
+		},
+		Sells: []me.PriceLevel{
+			{Price: 101e6, Orders: []me.OrderPart{{Id: "B-1", Time: 90, Qty: 2e8}}},
+		},
+		LastTradePrice:  1e8,
+		LastMatchHeight: 100,
+	}
+	eng := me.NewMatchEng("XYZ_BNB", ob.LastTradePrice, 1e6, 0.05)
+	var out bytes.Buffer
+	sim, err := newSimulator(eng, ob, &out)
+	require.Nil(t, err)
+
+	err = sim.run([]SimEvent{
+		{Height: 101, Type: simEventNew, Id: "C-1", Side: me.BUYSIDE, Price: 102e6, Qty: 1e8},
+		{Height: 102, Type: simEventCancel, Id: "A-1"},
+	})
+	require.Nil(t, err)
+	require.Equal(t, `height 101: concluded price 101000000
+  trade buy C-1 sell B-1 price 101000000 qty 100000000
+height 102: no trade
+final order book:
+  sell price 101000000 qty 100000000 orders 1
+`, out.String())
+
+	err = sim.run([]SimEvent{{Height: 102, Type: simEventCancel, Id: "B-1"}})
+	require.EqualError(t, err, "event of height 102 is not after the last match height 102")
+	err = sim.run([]SimEvent{{Height: 103, Type: simEventCancel, Id: "A-1"}})
+	require.EqualError(t, err, "order A-1 to cancel is not found")
+	err = sim.run([]SimEvent{{Height: 103, Type: simEventNew, Id: "D-1", Side: me.SELLSIDE, Price: 1e8, Qty: 1}})
+	require.EqualError(t, err, "invalid price 100000000 or quantity 1 of order D-1, lot size is 1000000")
+}
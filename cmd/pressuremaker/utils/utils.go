@@ -159,7 +159,8 @@ func (mg MessageGenerator) Publish(height, timePub int64, tradesToPublish []*pub
 		pub.BlockFee{},
 		nil,
 		transfers,
-		block)
+		block,
+		nil)
 }
 
 func makeOrderInfo(sender sdk.AccAddress, side int8, height, price, qty, cumQty, timePub int64) orderPkg.OrderInfo {
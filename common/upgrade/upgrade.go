@@ -40,6 +40,9 @@ const (
 	BEP128 = sdk.BEP128 // https://github.com/bnb-chain/BEPs/pull/128 Staking reward distribution upgrade
 	BEP151 = "BEP151"   // https://github.com/bnb-chain/BEPs/pull/151 Decommission Decentralized Exchange
 	BEP153 = sdk.BEP153 // https://github.com/bnb-chain/BEPs/pull/153 Native Staking
+
+	DexEnhancements = "DexEnhancements" // new order types, time in force options and order management of the dex
+	OrderSizeLimits = "OrderSizeLimits" // minimum notional and maximum quantity of the orders of a trading pair
 )
 
 func UpgradeBEP10(before func(), after func()) {
@@ -108,6 +108,26 @@ func (s *server) handleDexDepthReq(cdc *wire.Codec, ctx context.CLIContext) http
 	return dexapi.DepthReqHandler(cdc, ctx)
 }
 
+func (s *server) handleDexOrderBookL3Req(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
+	return dexapi.OrderBookL3ReqHandler(cdc, ctx)
+}
+
+func (s *server) handleDexIndicativeReq(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
+	return dexapi.IndicativeReqHandler(cdc, ctx)
+}
+
+func (s *server) handleDexKlinesReq(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
+	return dexapi.KlinesReqHandler(cdc, ctx)
+}
+
+func (s *server) handleDexTickerReq(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
+	return dexapi.TickerReqHandler(cdc, ctx)
+}
+
+func (s *server) handleDexTradesReq(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
+	return dexapi.TradesReqHandler(cdc, ctx)
+}
+
 func (s *server) handleDexOrderReq(cdc *wire.Codec, ctx context.CLIContext, accStoreName string) http.HandlerFunc {
 	h := dexapi.PutOrderReqHandler(cdc, ctx, accStoreName)
 	return s.withUrlEncForm(s.limitReqSize(h))
@@ -117,6 +137,18 @@ func (s *server) handleDexOpenOrdersReq(cdc *wire.Codec, ctx context.CLIContext)
 	return dexapi.OpenOrdersReqHandler(cdc, ctx)
 }
 
+func (s *server) handleDexClosedOrdersReq(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
+	return dexapi.ClosedOrdersReqHandler(cdc, ctx)
+}
+
+func (s *server) handleDexFeeEstimateReq(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
+	return dexapi.FeeEstimateReqHandler(cdc, ctx)
+}
+
+func (s *server) handleDexOrderLimitsReq(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
+	return dexapi.OrderLimitsReqHandler(cdc, ctx)
+}
+
 func (s *server) handleTokenReq(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
 	return tksapi.GetTokenReqHandler(cdc, ctx, false)
 }
@@ -37,12 +37,37 @@ func (s *server) bindRoutes() *server {
 	r.HandleFunc(prefix+"/depth", s.handleDexDepthReq(s.cdc, s.ctx)).
 		Queries("symbol", "{symbol}").
 		Methods("GET")
+	r.HandleFunc(prefix+"/depth/orders", s.handleDexOrderBookL3Req(s.cdc, s.ctx)).
+		Queries("symbol", "{symbol}").
+		Methods("GET")
+	r.HandleFunc(prefix+"/indicative", s.handleDexIndicativeReq(s.cdc, s.ctx)).
+		Queries("symbol", "{symbol}").
+		Methods("GET")
+	r.HandleFunc(prefix+"/klines", s.handleDexKlinesReq(s.cdc, s.ctx)).
+		Queries("symbol", "{symbol}", "interval", "{interval}").
+		Methods("GET")
+	r.HandleFunc(prefix+"/ticker/24hr", s.handleDexTickerReq(s.cdc, s.ctx)).
+		Methods("GET")
+	r.HandleFunc(prefix+"/trades", s.handleDexTradesReq(s.cdc, s.ctx)).
+		Queries("symbol", "{symbol}").
+		Methods("GET")
 	r.HandleFunc(prefix+"/order", s.handleDexOrderReq(s.cdc, s.ctx, s.accStoreName)).
 		Methods("PUT", "POST")
 
 	r.HandleFunc(prefix+"/orders/open", s.handleDexOpenOrdersReq(s.cdc, s.ctx)).
 		Queries("address", "{address}", "symbol", "{symbol}").
 		Methods("GET")
+	r.HandleFunc(prefix+"/orders/open", s.handleDexOpenOrdersReq(s.cdc, s.ctx)).
+		Queries("address", "{address}").
+		Methods("GET")
+
+	r.HandleFunc(prefix+"/orders/closed", s.handleDexClosedOrdersReq(s.cdc, s.ctx)).
+		Queries("address", "{address}").
+		Methods("GET")
+
+	r.HandleFunc(prefix+"/orders/limits", s.handleDexOrderLimitsReq(s.cdc, s.ctx)).
+		Queries("address", "{address}").
+		Methods("GET")
 
 	r.HandleFunc(prefix+"/mini/markets", s.handleMiniPairsReq(s.cdc, s.ctx)).
 		Methods("GET")
@@ -66,6 +91,9 @@ func (s *server) bindRoutes() *server {
 	// fee params
 	r.HandleFunc(prefix+"/fees", s.handleFeesParamReq(s.cdc, s.ctx)).
 		Methods("GET")
+	r.HandleFunc(prefix+"/fees/estimate", s.handleDexFeeEstimateReq(s.cdc, s.ctx)).
+		Queries("address", "{address}", "symbol", "{symbol}", "side", "{side}", "price", "{price}", "quantity", "{quantity}").
+		Methods("GET")
 
 	// stake query
 	r.HandleFunc(prefix+"/stake/validators", s.handleValidatorsQueryReq(s.cdc, s.ctx)).
@@ -17,6 +17,10 @@ import (
 
 const MaxDepthLevels = 1000    // matches UI requirement
 const DefaultDepthLevels = 100 // matches UI requirement
+const MaxKlines = 1000
+const DefaultKlines = 100
+const DefaultTrades = 100
+const MaxClosedOrders = 1000
 
 func createAbciQueryHandler(keeper *DexKeeper, abciQueryPrefix string) app.AbciQueryHandler {
 	queryPrefix := abciQueryPrefix
@@ -131,6 +135,281 @@ func createAbciQueryHandler(keeper *DexKeeper, abciQueryPrefix string) app.AbciQ
 				Code:  uint32(sdk.ABCICodeOK),
 				Value: bz,
 			}
+		case "orderbookl3": // args: ["dex", "orderbookl3", <pair>, <offset>, <limit>]
+			if queryPrefix == DexMiniAbciQueryPrefix {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.ABCICodeOK),
+					Info: fmt.Sprintf(
+						"Unknown `%s` query path: %v",
+						queryPrefix, path),
+				}
+			}
+			if len(path) < 5 {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  "OrderBookL3 query requires the pair symbol, offset and limit of the levels",
+				}
+			}
+			offset, err := strconv.Atoi(path[3])
+			if err != nil || offset < 0 {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  "unable to parse offset",
+				}
+			}
+			limit, err := strconv.Atoi(path[4])
+			if err != nil || limit <= 0 || limit > MaxDepthLevels {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  "OrderBookL3 query requires valid limit (>0 && <1000)",
+				}
+			}
+			buys, sells := keeper.GetOrderBookL3(path[2], offset, limit)
+			book := store.OrderBookL3{
+				Height: app.GetContextForCheckState().BlockHeight(),
+				Buys:   buys,
+				Sells:  sells,
+			}
+			bz, err := app.GetCodec().MarshalBinaryLengthPrefixed(book)
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			return &abci.ResponseQuery{
+				Code:  uint32(sdk.ABCICodeOK),
+				Value: bz,
+			}
+		case "indicative": // args: ["dex", "indicative", <pair>]
+			if queryPrefix == DexMiniAbciQueryPrefix {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.ABCICodeOK),
+					Info: fmt.Sprintf(
+						"Unknown `%s` query path: %v",
+						queryPrefix, path),
+				}
+			}
+			if len(path) < 3 {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  "Indicative price query requires the pair symbol",
+				}
+			}
+			price, err := keeper.GetIndicativePrice(path[2])
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			price.Height = app.GetContextForCheckState().BlockHeight()
+			bz, err := app.GetCodec().MarshalBinaryLengthPrefixed(price)
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			return &abci.ResponseQuery{
+				Code:  uint32(sdk.ABCICodeOK),
+				Value: bz,
+			}
+		case "klines": // args: ["dex", "klines", <pair>, <interval>, <limit>(optional)]
+			if queryPrefix == DexMiniAbciQueryPrefix {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.ABCICodeOK),
+					Info: fmt.Sprintf(
+						"Unknown `%s` query path: %v",
+						queryPrefix, path),
+				}
+			}
+			if len(path) < 4 {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  "Klines query requires the pair symbol and the interval",
+				}
+			}
+			limit := DefaultKlines
+			if len(path) == 5 {
+				l, err := strconv.Atoi(path[4])
+				if err != nil || l <= 0 || l > MaxKlines {
+					return &abci.ResponseQuery{
+						Code: uint32(sdk.CodeUnknownRequest),
+						Log:  "Klines query requires valid limit (>0 && <=1000)",
+					}
+				}
+				limit = l
+			}
+			klines, err := keeper.GetKlines(path[2], path[3], limit)
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			bz, err := app.GetCodec().MarshalBinaryLengthPrefixed(klines)
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			return &abci.ResponseQuery{
+				Code:  uint32(sdk.ABCICodeOK),
+				Value: bz,
+			}
+		case "ticker": // args: ["dex", "ticker", <pair>(optional)]
+			if queryPrefix == DexMiniAbciQueryPrefix {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.ABCICodeOK),
+					Info: fmt.Sprintf(
+						"Unknown `%s` query path: %v",
+						queryPrefix, path),
+				}
+			}
+			var tickers []store.Ticker
+			if len(path) < 3 {
+				tickers = keeper.GetTickers()
+			} else {
+				ticker, err := keeper.GetTicker(path[2])
+				if err != nil {
+					return &abci.ResponseQuery{
+						Code: uint32(sdk.CodeInternal),
+						Log:  err.Error(),
+					}
+				}
+				tickers = []store.Ticker{ticker}
+			}
+			bz, err := app.GetCodec().MarshalBinaryLengthPrefixed(tickers)
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			return &abci.ResponseQuery{
+				Code:  uint32(sdk.ABCICodeOK),
+				Value: bz,
+			}
+		case "trades": // args: ["dex", "trades", <pair>, <limit>(optional)]
+			if queryPrefix == DexMiniAbciQueryPrefix {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.ABCICodeOK),
+					Info: fmt.Sprintf(
+						"Unknown `%s` query path: %v",
+						queryPrefix, path),
+				}
+			}
+			if len(path) < 3 {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  "Trades query requires the pair symbol",
+				}
+			}
+			limit := DefaultTrades
+			if len(path) == 4 {
+				l, err := strconv.Atoi(path[3])
+				if err != nil || l <= 0 || l > order.NumRecentTrades {
+					return &abci.ResponseQuery{
+						Code: uint32(sdk.CodeUnknownRequest),
+						Log:  fmt.Sprintf("Trades query requires valid limit (>0 && <=%d)", order.NumRecentTrades),
+					}
+				}
+				limit = l
+			}
+			trades, err := keeper.GetRecentTrades(path[2], limit)
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			bz, err := app.GetCodec().MarshalBinaryLengthPrefixed(trades)
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			return &abci.ResponseQuery{
+				Code:  uint32(sdk.ABCICodeOK),
+				Value: bz,
+			}
+		case "closedorders": // args: ["dex", "closedorders"], data: the json of store.ClosedOrdersQueryParams
+			if queryPrefix == DexMiniAbciQueryPrefix {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.ABCICodeOK),
+					Info: fmt.Sprintf(
+						"Unknown `%s` query path: %v",
+						queryPrefix, path),
+				}
+			}
+			var params store.ClosedOrdersQueryParams
+			if err := app.GetCodec().UnmarshalJSON(req.Data, &params); err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  fmt.Sprintf("incorrectly formatted request data: %s", err.Error()),
+				}
+			}
+			if params.Offset < 0 || params.Limit <= 0 || params.Limit > MaxClosedOrders {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  fmt.Sprintf("ClosedOrders query requires valid offset (>=0) and limit (>0 && <=%d)", MaxClosedOrders),
+				}
+			}
+			orders, err := keeper.GetClosedOrders(params)
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			bz, err := app.GetCodec().MarshalBinaryLengthPrefixed(orders)
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			return &abci.ResponseQuery{
+				Code:  uint32(sdk.ABCICodeOK),
+				Value: bz,
+			}
+		case "feeestimate": // args: ["dex", "feeestimate"], data: the json of store.FeeEstimateQueryParams
+			if queryPrefix == DexMiniAbciQueryPrefix {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.ABCICodeOK),
+					Info: fmt.Sprintf(
+						"Unknown `%s` query path: %v",
+						queryPrefix, path),
+				}
+			}
+			var params store.FeeEstimateQueryParams
+			if err := app.GetCodec().UnmarshalJSON(req.Data, &params); err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  fmt.Sprintf("incorrectly formatted request data: %s", err.Error()),
+				}
+			}
+			estimate, err := keeper.EstimateFees(app.GetContextForCheckState(), params)
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  err.Error(),
+				}
+			}
+			bz, err := app.GetCodec().MarshalBinaryLengthPrefixed(estimate)
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			return &abci.ResponseQuery{
+				Code:  uint32(sdk.ABCICodeOK),
+				Value: bz,
+			}
 		case "openorders": // args: ["dex", "openorders", <pair>, <bech32Str>]
 			if queryPrefix == DexMiniAbciQueryPrefix {
 				return &abci.ResponseQuery{
@@ -184,6 +463,74 @@ func createAbciQueryHandler(keeper *DexKeeper, abciQueryPrefix string) app.AbciQ
 				Code:  uint32(sdk.ABCICodeOK),
 				Value: bz,
 			}
+		case "orderlimits": // args: ["dex", "orderlimits", <bech32Str>]
+			if queryPrefix == DexMiniAbciQueryPrefix {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.ABCICodeOK),
+					Info: fmt.Sprintf(
+						"Unknown `%s` query path: %v",
+						queryPrefix, path),
+				}
+			}
+			if len(path) < 3 {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  "OrderLimits query requires the address",
+				}
+			}
+			addr, err := sdk.AccAddressFromBech32(path[2])
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  "address is not valid",
+				}
+			}
+			limits := keeper.GetOrderLimits(addr)
+			bz, err := app.GetCodec().MarshalBinaryLengthPrefixed(limits)
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			return &abci.ResponseQuery{
+				Code:  uint32(sdk.ABCICodeOK),
+				Value: bz,
+			}
+		case "allopenorders": // args: ["dex", "allopenorders", <bech32Str>]
+			if queryPrefix == DexMiniAbciQueryPrefix {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.ABCICodeOK),
+					Info: fmt.Sprintf(
+						"Unknown `%s` query path: %v",
+						queryPrefix, path),
+				}
+			}
+			if len(path) < 3 {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeUnknownRequest),
+					Log:  "AllOpenOrders query requires the address",
+				}
+			}
+			addr, err := sdk.AccAddressFromBech32(path[2])
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  "address is not valid",
+				}
+			}
+			openOrders := keeper.GetAllOpenOrders(addr)
+			bz, err := app.GetCodec().MarshalBinaryLengthPrefixed(openOrders)
+			if err != nil {
+				return &abci.ResponseQuery{
+					Code: uint32(sdk.CodeInternal),
+					Log:  err.Error(),
+				}
+			}
+			return &abci.ResponseQuery{
+				Code:  uint32(sdk.ABCICodeOK),
+				Value: bz,
+			}
 		default:
 			return &abci.ResponseQuery{
 				Code: uint32(sdk.ABCICodeOK),
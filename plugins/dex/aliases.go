@@ -1,6 +1,7 @@
 package dex
 
 import (
+	"github.com/bnb-chain/node/plugins/dex/matcheng"
 	"github.com/bnb-chain/node/plugins/dex/order"
 	"github.com/bnb-chain/node/plugins/dex/store"
 	"github.com/bnb-chain/node/plugins/dex/types"
@@ -15,4 +16,6 @@ type DexKeeper = order.DexKeeper
 var NewTradingPairMapper = store.NewTradingPairMapper
 var NewDexKeeper = order.NewDexKeeper
 
+const OrderBookTypeBTree = matcheng.OrderBookTypeBTree
+
 const DefaultCodespace = types.DefaultCodespace
@@ -29,11 +29,17 @@ const (
 	flagQty         = "qty"
 	flagSide        = "side"
 	flagTimeInForce = "tif"
+	flagStopPrice   = "stop-price"
+	flagOrderType   = "type"
+	flagDisplayQty  = "display-qty"
+
+	flagGoodTillHeight = "good-till-height"
+	flagGoodTillTime   = "good-till-time"
 )
 
 func newOrderCmd(cdc *wire.Codec) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "order -l <pair> -s <side> -p <price> -q <qty> -t <timeInForce>",
+		Use:   "order -l <pair> -s <side> -p <price> -q <qty> -t <timeInForce> [--type <orderType>] [--stop-price <stopPrice>] [--display-qty <displayQty>] [--good-till-height <height>] [--good-till-time <unixTime>]",
 		Short: "Submit a new order",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cliCtx, txBldr := client.PrepareCtx(cdc)
@@ -50,12 +56,20 @@ func newOrderCmd(cdc *wire.Codec) *cobra.Command {
 
 			symbol = strings.ToUpper(symbol)
 
-			priceStr := viper.GetString(flagPrice)
-			price, err := utils.ParsePrice(priceStr)
+			orderType, err := order.OrderTypeStringToOrderTypeCode(viper.GetString(flagOrderType))
 			if err != nil {
 				return err
 			}
 
+			var price int64
+			// the price of market orders is decided by the match engine
+			if orderType != order.OrderType.MARKET {
+				price, err = utils.ParsePrice(viper.GetString(flagPrice))
+				if err != nil {
+					return err
+				}
+			}
+
 			qtyStr := viper.GetString(flagQty)
 			qty, err := utils.ParsePrice(qtyStr)
 			if err != nil {
@@ -90,6 +104,23 @@ func newOrderCmd(cdc *wire.Codec) *cobra.Command {
 			}
 
 			msg.TimeInForce = tif
+			msg.OrderType = orderType
+			if stopPriceStr := viper.GetString(flagStopPrice); stopPriceStr != "" {
+				stopPrice, err := utils.ParsePrice(stopPriceStr)
+				if err != nil {
+					return err
+				}
+				msg.OrderType = order.OrderType.STOP_LIMIT
+				msg.StopPrice = stopPrice
+			}
+			if displayQtyStr := viper.GetString(flagDisplayQty); displayQtyStr != "" {
+				msg.DisplayQty, err = utils.ParsePrice(displayQtyStr)
+				if err != nil {
+					return err
+				}
+			}
+			msg.GoodTillHeight = viper.GetInt64(flagGoodTillHeight)
+			msg.GoodTillTime = viper.GetInt64(flagGoodTillTime)
 
 			err = client.SendOrPrintTx(cliCtx, txBldr, msg)
 			if err != nil {
@@ -104,7 +135,12 @@ func newOrderCmd(cdc *wire.Codec) *cobra.Command {
 	cmd.Flags().StringP(flagSide, "s", "", "side (buy as 1 or sell as 2) of the order")
 	cmd.Flags().StringP(flagPrice, "p", "", "price for the order")
 	cmd.Flags().StringP(flagQty, "q", "", "quantity for the order")
-	cmd.Flags().StringP(flagTimeInForce, "t", "gte", "TimeInForce for the order (gte or ioc)")
+	cmd.Flags().StringP(flagTimeInForce, "t", "gte", "TimeInForce for the order (gte, ioc, fok or post_only)")
+	cmd.Flags().String(flagStopPrice, "", "trigger price, places a stop-limit order if given")
+	cmd.Flags().String(flagOrderType, "limit", "type of the order (limit or market), market orders must be ioc or fok")
+	cmd.Flags().String(flagDisplayQty, "", "visible quantity of the order, places an iceberg order hiding the rest if given")
+	cmd.Flags().Int64(flagGoodTillHeight, 0, "the order expires at the end of this block height if given")
+	cmd.Flags().Int64(flagGoodTillTime, 0, "the order expires at the end of the first block at or after this unix time if given")
 	return cmd
 }
 
@@ -183,6 +219,95 @@ func cancelOrderCmd(cdc *wire.Codec) *cobra.Command {
 	return cmd
 }
 
+func amendOrderCmd(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "amend -l <trading pair> -f <ref order id> -p <price> -q <qty>",
+		Short: "Amend the price and/or quantity of an order",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txBldr := txbuilder.NewTxBuilderFromCLI().WithCodec(cdc)
+			cliCtx := context.NewCLIContext().WithCodec(cdc).WithAccountDecoder(types.GetAccountDecoder(cdc))
+			from, err := cliCtx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+			symbol := viper.GetString(flagSymbol)
+			err = validatePairSymbol(symbol)
+			if err != nil {
+				return err
+			}
+			refId := viper.GetString(flagRefId)
+			if refId == "" {
+				return errors.New("please input reference order id")
+			}
+			price, err := utils.ParsePrice(viper.GetString(flagPrice))
+			if err != nil {
+				return err
+			}
+			qty, err := utils.ParsePrice(viper.GetString(flagQty))
+			if err != nil {
+				return err
+			}
+			msg := order.NewAmendOrderMsg(from, symbol, refId, price, qty)
+			if cliCtx.GenerateOnly {
+				return txutils.PrintUnsignedStdTx(txBldr, cliCtx, []sdk.Msg{msg})
+			}
+
+			err = txutils.CompleteAndBroadcastTxCli(txBldr, cliCtx, []sdk.Msg{msg})
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Msg [%v] was sent.\n", msg)
+			return nil
+		},
+	}
+	cmd.Flags().StringP(flagSymbol, "l", "", "the listed trading pair, such as ADA_BNB")
+	cmd.Flags().StringP(flagRefId, "f", "", "id string of the order")
+	cmd.Flags().StringP(flagPrice, "p", "", "new price for the order")
+	cmd.Flags().StringP(flagQty, "q", "", "new total quantity for the order, including the filled quantity")
+	return cmd
+}
+
+func cancelAllOrdersCmd(cdc *wire.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cancel-all [-l <trading pair>] [-s <side>]",
+		Short: "Cancel all the open orders, optionally only the ones of a trading pair and/or a side",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txBldr := txbuilder.NewTxBuilderFromCLI().WithCodec(cdc)
+			cliCtx := context.NewCLIContext().WithCodec(cdc).WithAccountDecoder(types.GetAccountDecoder(cdc))
+			from, err := cliCtx.GetFromAddress()
+			if err != nil {
+				return err
+			}
+			symbol := viper.GetString(flagSymbol)
+			if symbol != "" {
+				err = validatePairSymbol(symbol)
+				if err != nil {
+					return err
+				}
+			}
+			side := int8(viper.GetInt(flagSide))
+			msg := order.NewCancelAllOrdersMsg(from, symbol, side)
+			err = msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+			if cliCtx.GenerateOnly {
+				return txutils.PrintUnsignedStdTx(txBldr, cliCtx, []sdk.Msg{msg})
+			}
+
+			err = txutils.CompleteAndBroadcastTxCli(txBldr, cliCtx, []sdk.Msg{msg})
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Msg [%v] was sent.\n", msg)
+			return nil
+		},
+	}
+	cmd.Flags().StringP(flagSymbol, "l", "", "the listed trading pair, such as ADA_BNB, all the trading pairs if not set")
+	cmd.Flags().StringP(flagSide, "s", "", "side (buy as 1 or sell as 2) of the orders, both sides if not set")
+	return cmd
+}
+
 func validatePairSymbol(symbol string) error {
 	return store.ValidatePairSymbol(symbol)
 }
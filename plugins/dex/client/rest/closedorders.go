@@ -0,0 +1,92 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+
+	"github.com/bnb-chain/node/plugins/dex/store"
+	"github.com/bnb-chain/node/wire"
+)
+
+const (
+	defaultClosedOrders = 100
+	maxClosedOrders     = 1000
+)
+
+// ClosedOrdersReqHandler creates an http request handler to show the closed orders of an address, the latest closed first.
+// The orders can be filtered by the optional symbol, status and height range, and paged by the optional offset and limit.
+func ClosedOrdersReqHandler(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
+	throw := func(w http.ResponseWriter, status int, err error) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(err.Error()))
+	}
+	parseInt := func(r *http.Request, name string, defaultValue int64) (int64, error) {
+		str := r.FormValue(name)
+		if str == "" {
+			return defaultValue, nil
+		}
+		value, err := strconv.ParseInt(str, 10, 64)
+		if err != nil || value < 0 {
+			return 0, fmt.Errorf("invalid %s, should be a non-negative integer", name)
+		}
+		return value, nil
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := store.ClosedOrdersQueryParams{
+			Address: r.FormValue("address"),
+			Symbol:  r.FormValue("symbol"),
+			Status:  r.FormValue("status"),
+		}
+		if _, err := types.AccAddressFromBech32(params.Address); err != nil {
+			throw(w, http.StatusExpectationFailed, fmt.Errorf("addr is not a valid Bech32 address"))
+			return
+		}
+		if params.Symbol != "" {
+			if err := store.ValidatePairSymbol(params.Symbol); err != nil {
+				throw(w, http.StatusNotFound, err)
+				return
+			}
+		}
+
+		var err error
+		if params.FromHeight, err = parseInt(r, "fromHeight", 0); err != nil {
+			throw(w, http.StatusExpectationFailed, err)
+			return
+		}
+		if params.ToHeight, err = parseInt(r, "toHeight", 0); err != nil {
+			throw(w, http.StatusExpectationFailed, err)
+			return
+		}
+		offset, err := parseInt(r, "offset", 0)
+		if err != nil {
+			throw(w, http.StatusExpectationFailed, err)
+			return
+		}
+		limit, err := parseInt(r, "limit", defaultClosedOrders)
+		if err != nil || limit == 0 || limit > maxClosedOrders {
+			throw(w, http.StatusExpectationFailed, fmt.Errorf("invalid limit, should be in (0, %d]", maxClosedOrders))
+			return
+		}
+		params.Offset, params.Limit = int(offset), int(limit)
+
+		orders, err := store.GetClosedOrders(cdc, ctx, params)
+		if err != nil {
+			throw(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		err = json.NewEncoder(w).Encode(orders)
+		if err != nil {
+			throw(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+}
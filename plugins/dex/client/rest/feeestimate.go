@@ -0,0 +1,68 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/common/utils"
+	"github.com/bnb-chain/node/plugins/dex/order"
+	"github.com/bnb-chain/node/plugins/dex/store"
+	"github.com/bnb-chain/node/wire"
+)
+
+// FeeEstimateReqHandler creates an http request handler to estimate the fees which would be charged for an order
+// of the address, at the current prices and balances
+func FeeEstimateReqHandler(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
+	throw := func(w http.ResponseWriter, status int, err error) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(err.Error()))
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := store.FeeEstimateQueryParams{
+			Address: r.FormValue("address"),
+			Symbol:  r.FormValue("symbol"),
+		}
+		if _, err := types.AccAddressFromBech32(params.Address); err != nil {
+			throw(w, http.StatusExpectationFailed, fmt.Errorf("addr is not a valid Bech32 address"))
+			return
+		}
+		if err := store.ValidatePairSymbol(params.Symbol); err != nil {
+			throw(w, http.StatusNotFound, err)
+			return
+		}
+		params.Symbol = strings.ToUpper(params.Symbol)
+
+		var err error
+		if params.Side, err = order.SideStringToSideCode(r.FormValue("side")); err != nil {
+			throw(w, http.StatusExpectationFailed, err)
+			return
+		}
+		if params.Price, err = utils.ParsePrice(r.FormValue("price")); err != nil {
+			throw(w, http.StatusExpectationFailed, fmt.Errorf("invalid price: %s", err.Error()))
+			return
+		}
+		if params.Quantity, err = utils.ParsePrice(r.FormValue("quantity")); err != nil {
+			throw(w, http.StatusExpectationFailed, fmt.Errorf("invalid quantity: %s", err.Error()))
+			return
+		}
+
+		estimate, err := store.GetFeeEstimate(cdc, ctx, params)
+		if err != nil {
+			throw(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		err = json.NewEncoder(w).Encode(estimate)
+		if err != nil {
+			throw(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+}
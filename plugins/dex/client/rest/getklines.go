@@ -0,0 +1,55 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+
+	"github.com/bnb-chain/node/plugins/dex/store"
+	"github.com/bnb-chain/node/wire"
+)
+
+const defaultKlines = 100
+
+// KlinesReqHandler creates an http request handler to show the latest klines of a pair in an interval.
+// The number of the klines is specified by the optional limit.
+func KlinesReqHandler(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
+	throw := func(w http.ResponseWriter, status int, err error) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(err.Error()))
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		symbol := r.FormValue("symbol")
+		err := store.ValidatePairSymbol(symbol)
+		if err != nil {
+			throw(w, http.StatusNotFound, err)
+			return
+		}
+
+		interval := r.FormValue("interval")
+		limit := defaultKlines
+		if limitStr := r.FormValue("limit"); limitStr != "" {
+			if limit, err = strconv.Atoi(limitStr); err != nil || limit <= 0 || limit > 1000 {
+				throw(w, http.StatusExpectationFailed, errors.New("invalid limit, should be in (0, 1000]"))
+				return
+			}
+		}
+
+		klines, err := store.GetKlines(cdc, ctx, symbol, interval, limit)
+		if err != nil {
+			throw(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		err = json.NewEncoder(w).Encode(klines)
+		if err != nil {
+			throw(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+
+	"github.com/bnb-chain/node/plugins/dex/store"
+	"github.com/bnb-chain/node/wire"
+)
+
+const defaultL3Levels = 20
+
+// OrderBookL3ReqHandler creates an http request handler to show the resting orders of the price levels.
+// The levels are paginated by the optional offset and limit.
+func OrderBookL3ReqHandler(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
+	throw := func(w http.ResponseWriter, status int, err error) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(err.Error()))
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		symbol := r.FormValue("symbol")
+		err := store.ValidatePairSymbol(symbol)
+		if err != nil {
+			throw(w, http.StatusNotFound, err)
+			return
+		}
+
+		offset, limit := 0, defaultL3Levels
+		if offsetStr := r.FormValue("offset"); offsetStr != "" {
+			if offset, err = strconv.Atoi(offsetStr); err != nil || offset < 0 {
+				throw(w, http.StatusExpectationFailed, errors.New("invalid offset"))
+				return
+			}
+		}
+		if limitStr := r.FormValue("limit"); limitStr != "" {
+			if limit, err = strconv.Atoi(limitStr); err != nil || limit <= 0 || limit > 1000 {
+				throw(w, http.StatusExpectationFailed, errors.New("invalid limit, should be in (0, 1000]"))
+				return
+			}
+		}
+
+		book, err := store.GetOrderBookL3(cdc, ctx, symbol, offset, limit)
+		if err != nil {
+			throw(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		err = json.NewEncoder(w).Encode(book)
+		if err != nil {
+			throw(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+}
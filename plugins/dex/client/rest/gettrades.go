@@ -0,0 +1,55 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+
+	"github.com/bnb-chain/node/plugins/dex/order"
+	"github.com/bnb-chain/node/plugins/dex/store"
+	"github.com/bnb-chain/node/wire"
+)
+
+const defaultTrades = 100
+
+// TradesReqHandler creates an http request handler to show the latest trades of a pair.
+// The number of the trades is specified by the optional limit.
+func TradesReqHandler(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
+	throw := func(w http.ResponseWriter, status int, err error) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(err.Error()))
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		symbol := r.FormValue("symbol")
+		err := store.ValidatePairSymbol(symbol)
+		if err != nil {
+			throw(w, http.StatusNotFound, err)
+			return
+		}
+
+		limit := defaultTrades
+		if limitStr := r.FormValue("limit"); limitStr != "" {
+			if limit, err = strconv.Atoi(limitStr); err != nil || limit <= 0 || limit > order.NumRecentTrades {
+				throw(w, http.StatusExpectationFailed, fmt.Errorf("invalid limit, should be in (0, %d]", order.NumRecentTrades))
+				return
+			}
+		}
+
+		trades, err := store.GetRecentTrades(cdc, ctx, symbol, limit)
+		if err != nil {
+			throw(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		err = json.NewEncoder(w).Encode(trades)
+		if err != nil {
+			throw(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+}
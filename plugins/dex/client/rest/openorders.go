@@ -23,10 +23,13 @@ func OpenOrdersReqHandler(cdc *wire.Codec, ctx context.CLIContext) http.HandlerF
 		symbol := r.FormValue("symbol")
 		addr := r.FormValue("address")
 
-		err := store.ValidatePairSymbol(symbol)
-		if err != nil {
-			throw(w, http.StatusInternalServerError, err)
-			return
+		// the open orders in all the pairs are shown if the symbol is not specified
+		if symbol != "" {
+			err := store.ValidatePairSymbol(symbol)
+			if err != nil {
+				throw(w, http.StatusInternalServerError, err)
+				return
+			}
 		}
 
 		// we only verify the addr is legal bech32 address rather than query it from account store
@@ -39,16 +42,21 @@ func OpenOrdersReqHandler(cdc *wire.Codec, ctx context.CLIContext) http.HandlerF
 			throw(w, http.StatusInternalServerError, fmt.Errorf("addr is not a valid Bech32 address"))
 			return
 		}
-		if openOrders, err := store.GetOpenOrders(cdc, ctx, symbol, addr); err != nil {
+		var openOrders []store.OpenOrder
+		var err error
+		if symbol == "" {
+			openOrders, err = store.GetAllOpenOrders(cdc, ctx, addr)
+		} else {
+			openOrders, err = store.GetOpenOrders(cdc, ctx, symbol, addr)
+		}
+		if err != nil {
+			throw(w, http.StatusInternalServerError, err)
+			return
+		}
+		err = json.NewEncoder(w).Encode(openOrders)
+		if err != nil {
 			throw(w, http.StatusInternalServerError, err)
 			return
-		} else {
-			err = json.NewEncoder(w).Encode(openOrders)
-			if err != nil {
-				throw(w, http.StatusInternalServerError, err)
-				return
-			}
 		}
-
 	}
 }
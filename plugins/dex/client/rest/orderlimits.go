@@ -0,0 +1,41 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/plugins/dex/store"
+	"github.com/bnb-chain/node/wire"
+)
+
+// OrderLimitsReqHandler creates an http request handler to show the order limits of an address along with its open orders
+func OrderLimitsReqHandler(cdc *wire.Codec, ctx context.CLIContext) http.HandlerFunc {
+	throw := func(w http.ResponseWriter, status int, err error) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(err.Error()))
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		addr := r.FormValue("address")
+		// we only verify the addr is legal bech32 address rather than query it from account store
+		if _, err := types.AccAddressFromBech32(addr); err != nil {
+			throw(w, http.StatusExpectationFailed, fmt.Errorf("addr is not a valid Bech32 address"))
+			return
+		}
+
+		limits, err := store.GetOrderLimits(cdc, ctx, addr)
+		if err != nil {
+			throw(w, http.StatusInternalServerError, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(limits); err != nil {
+			throw(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/cosmos/cosmos-sdk/client/context"
@@ -22,12 +23,16 @@ import (
 // PutOrderReqHandler creates an http request handler to create a new order transaction and return its binary tx
 func PutOrderReqHandler(cdc *wire.Codec, ctx context.CLIContext, accStoreName string) http.HandlerFunc {
 	type formParams struct {
-		address string
-		pair    string
-		side    string
-		price   string
-		qty     string
-		tif     string
+		address        string
+		pair           string
+		side           string
+		price          string
+		qty            string
+		tif            string
+		stopPrice      string
+		displayQty     string
+		goodTillHeight string
+		goodTillTime   string
 	}
 
 	type response struct {
@@ -66,12 +71,16 @@ func PutOrderReqHandler(cdc *wire.Codec, ctx context.CLIContext, accStoreName st
 	return func(w http.ResponseWriter, r *http.Request) {
 		// parse application/x-www-form-urlencoded or multipart/form-data form params
 		params := formParams{
-			address: r.FormValue("address"),
-			pair:    r.FormValue("pair"),
-			side:    r.FormValue("side"),
-			price:   r.FormValue("price"),
-			qty:     r.FormValue("qty"),
-			tif:     r.FormValue("tif"),
+			address:        r.FormValue("address"),
+			pair:           r.FormValue("pair"),
+			side:           r.FormValue("side"),
+			price:          r.FormValue("price"),
+			qty:            r.FormValue("qty"),
+			tif:            r.FormValue("tif"),
+			stopPrice:      r.FormValue("stop_price"),
+			displayQty:     r.FormValue("display_qty"),
+			goodTillHeight: r.FormValue("good_till_height"),
+			goodTillTime:   r.FormValue("good_till_time"),
 		}
 
 		if !validateFormParams(params) {
@@ -141,6 +150,36 @@ func PutOrderReqHandler(cdc *wire.Codec, ctx context.CLIContext, accStoreName st
 		if tif > -1 {
 			msg.TimeInForce = tif
 		}
+		if strings.TrimSpace(params.stopPrice) != "" {
+			stopPrice, err := utils.ParsePrice(params.stopPrice)
+			if err != nil {
+				throw(w, http.StatusExpectationFailed, err)
+				return
+			}
+			msg.OrderType = order.OrderType.STOP_LIMIT
+			msg.StopPrice = stopPrice
+		}
+		if strings.TrimSpace(params.displayQty) != "" {
+			msg.DisplayQty, err = utils.ParsePrice(params.displayQty)
+			if err != nil {
+				throw(w, http.StatusExpectationFailed, err)
+				return
+			}
+		}
+		if strings.TrimSpace(params.goodTillHeight) != "" {
+			msg.GoodTillHeight, err = strconv.ParseInt(params.goodTillHeight, 10, 64)
+			if err != nil {
+				throw(w, http.StatusExpectationFailed, err)
+				return
+			}
+		}
+		if strings.TrimSpace(params.goodTillTime) != "" {
+			msg.GoodTillTime, err = strconv.ParseInt(params.goodTillTime, 10, 64)
+			if err != nil {
+				throw(w, http.StatusExpectationFailed, err)
+				return
+			}
+		}
 		msgs := []sdk.Msg{msg}
 
 		// build the tx
@@ -7,12 +7,36 @@ import (
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/gov"
+	param "github.com/cosmos/cosmos-sdk/x/paramHub/types"
 
 	"github.com/bnb-chain/node/common/upgrade"
 	"github.com/bnb-chain/node/plugins/dex/order"
+	"github.com/bnb-chain/node/plugins/dex/types"
 	"github.com/bnb-chain/node/plugins/tokens"
+	"github.com/bnb-chain/node/wire"
 )
 
+// ProposalTypeTradingStatus is the kind of the proposals changing the trading status or the order size limits of a pair.
+// The trading status proposals are the parameter change proposals whose description is the TradingStatusParams
+// of the TradingStatusParamsType, the others are left to their own hooks.
+// TODO: use a dedicated kind like the list and delist proposals once gov has one, MsgSubmitProposal rejects
+// the kinds gov doesn't define and ProposalKind can't be encoded to json without a name in gov.
+const ProposalTypeTradingStatus = gov.ProposalTypeParameterChange
+
+// IsTradingStatusProposal returns whether the description of the proposal is the TradingStatusParams
+func IsTradingStatusProposal(proposal gov.Proposal) bool {
+	if proposal.GetProposalType() != ProposalTypeTradingStatus {
+		return false
+	}
+	var params struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal([]byte(proposal.GetDescription()), &params); err != nil {
+		return false
+	}
+	return params.Type == types.TradingStatusParamsType
+}
+
 type ListHooks struct {
 	orderKeeper *order.DexKeeper
 	tokenMapper tokens.Mapper
@@ -126,3 +150,118 @@ func (hooks DelistHooks) OnProposalSubmitted(ctx sdk.Context, proposal gov.Propo
 
 	return nil
 }
+
+type TradingStatusHooks struct {
+	orderKeeper *order.DexKeeper
+}
+
+func NewTradingStatusHooks(orderKeeper *order.DexKeeper) TradingStatusHooks {
+	return TradingStatusHooks{
+		orderKeeper: orderKeeper,
+	}
+}
+
+var _ gov.GovHooks = TradingStatusHooks{}
+
+func (hooks TradingStatusHooks) OnProposalSubmitted(ctx sdk.Context, proposal gov.Proposal) error {
+	if proposal.GetProposalType() != ProposalTypeTradingStatus {
+		panic(fmt.Sprintf("received wrong type of proposal %x", proposal.GetProposalType()))
+	}
+
+	if !IsTradingStatusProposal(proposal) {
+		return nil
+	}
+
+	if !sdk.IsUpgrade(upgrade.DexEnhancements) {
+		return errors.New("trading status proposals are not supported yet")
+	}
+
+	statusParams := types.TradingStatusParams{}
+	err := json.Unmarshal([]byte(proposal.GetDescription()), &statusParams)
+	if err != nil {
+		return fmt.Errorf("unmarshal trading status params error, err=%s", err.Error())
+	}
+
+	if statusParams.BaseAssetSymbol == "" {
+		return errors.New("base asset symbol should not be empty")
+	}
+
+	if statusParams.QuoteAssetSymbol == "" {
+		return errors.New("quote asset symbol should not be empty")
+	}
+
+	if statusParams.BaseAssetSymbol == statusParams.QuoteAssetSymbol {
+		return errors.New("base asset symbol and quote asset symbol should not be the same")
+	}
+
+	if (statusParams.MinNotional != nil || statusParams.MaxQuantity != nil) && !sdk.IsUpgrade(upgrade.OrderSizeLimits) {
+		return errors.New("order size limits are not supported yet")
+	}
+
+	if statusParams.Status == "" && statusParams.MinNotional == nil && statusParams.MaxQuantity == nil {
+		return errors.New("trading status or order size limits should be changed")
+	}
+
+	if statusParams.Status != "" {
+		if _, err := types.ParseTradingStatus(statusParams.Status); err != nil {
+			return err
+		}
+	}
+
+	if statusParams.MinNotional != nil && *statusParams.MinNotional < 0 {
+		return errors.New("min notional should not be less than 0")
+	}
+
+	if statusParams.MaxQuantity != nil && *statusParams.MaxQuantity < 0 {
+		return errors.New("max quantity should not be less than 0")
+	}
+
+	if statusParams.Justification == "" {
+		return errors.New("justification should not be empty")
+	}
+
+	if statusParams.IsExecuted {
+		return errors.New("is_executed should be false")
+	}
+
+	if !hooks.orderKeeper.PairMapper.Exists(ctx, statusParams.BaseAssetSymbol, statusParams.QuoteAssetSymbol) {
+		return fmt.Errorf("trading pair %s_%s does not exist", statusParams.BaseAssetSymbol, statusParams.QuoteAssetSymbol)
+	}
+
+	return nil
+}
+
+// DexParamsChangeHooks rejects the fee change proposals with the dex params before they are supported.
+// The other fee params of the proposals are checked by the fee change hooks of the param hub.
+type DexParamsChangeHooks struct {
+	cdc *wire.Codec
+}
+
+func NewDexParamsChangeHooks(cdc *wire.Codec) DexParamsChangeHooks {
+	return DexParamsChangeHooks{cdc}
+}
+
+var _ gov.GovHooks = DexParamsChangeHooks{}
+
+func (hooks DexParamsChangeHooks) OnProposalSubmitted(ctx sdk.Context, proposal gov.Proposal) error {
+	if proposal.GetProposalType() != gov.ProposalTypeFeeChange {
+		panic(fmt.Sprintf("received wrong type of proposal %x", proposal.GetProposalType()))
+	}
+
+	if sdk.IsUpgrade(upgrade.DexEnhancements) {
+		return nil
+	}
+
+	feeParams := param.FeeChangeParams{}
+	err := hooks.cdc.UnmarshalJSON([]byte(proposal.GetDescription()), &feeParams)
+	if err != nil {
+		return fmt.Errorf("unmarshal feeParam error, err=%s", err.Error())
+	}
+
+	for _, p := range feeParams.FeeParams {
+		if order.IsDexParam(p) {
+			return fmt.Errorf("%s param is not supported yet", p.GetParamType())
+		}
+	}
+	return nil
+}
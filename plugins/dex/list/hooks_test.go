@@ -10,11 +10,15 @@ import (
 	abci "github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/libs/log"
 
+	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/gov"
+	"github.com/cosmos/cosmos-sdk/x/paramHub"
+	param "github.com/cosmos/cosmos-sdk/x/paramHub/types"
 
 	"github.com/bnb-chain/node/common/types"
 	"github.com/bnb-chain/node/common/upgrade"
+	"github.com/bnb-chain/node/plugins/dex/order"
 	dexTypes "github.com/bnb-chain/node/plugins/dex/types"
 )
 
@@ -665,3 +669,200 @@ func TestDelistProperTradingPair(t *testing.T) {
 	err = hooks.OnProposalSubmitted(ctx, &proposal)
 	require.Nil(t, err, "err should not be nil")
 }
+
+func TestTradingStatusWrongTypeOfProposal(t *testing.T) {
+	hooks := NewTradingStatusHooks(nil)
+	proposal := gov.TextProposal{
+		ProposalType: gov.ProposalTypeDelistTradingPair,
+		Description:  "nonsense",
+	}
+
+	require.Panics(t, func() {
+		hooks.OnProposalSubmitted(sdk.Context{}, &proposal)
+	}, "should panic here")
+}
+
+func TestTradingStatusBeforeUpgrade(t *testing.T) {
+	hooks := NewTradingStatusHooks(nil)
+	sdk.UpgradeMgr.AddUpgradeHeight(upgrade.DexEnhancements, 2)
+	sdk.UpgradeMgr.SetHeight(1)
+
+	statusParamsBz, err := json.Marshal(dexTypes.TradingStatusParams{
+		Type:             dexTypes.TradingStatusParamsType,
+		BaseAssetSymbol:  "BTC-2BD",
+		QuoteAssetSymbol: "BNB",
+		Status:           "halted",
+		Justification:    "Halt",
+	})
+	require.Nil(t, err, "marshal trading status params error")
+	proposal := gov.TextProposal{
+		ProposalType: ProposalTypeTradingStatus,
+		Description:  string(statusParamsBz),
+	}
+	require.True(t, IsTradingStatusProposal(&proposal))
+	err = hooks.OnProposalSubmitted(sdk.Context{}, &proposal)
+	require.NotNil(t, err, "err should not be nil")
+	require.Contains(t, err.Error(), "trading status proposals are not supported yet")
+
+	// the other parameter change proposals are not checked by the hooks
+	for _, desc := range []string{"nonsense", `{"base_asset_symbol":"BTC-2BD","quote_asset_symbol":"BNB","status":"halted"}`} {
+		proposal = gov.TextProposal{
+			ProposalType: ProposalTypeTradingStatus,
+			Description:  desc,
+		}
+		require.False(t, IsTradingStatusProposal(&proposal))
+		require.Nil(t, hooks.OnProposalSubmitted(sdk.Context{}, &proposal), "err should be nil")
+	}
+}
+
+func TestTradingStatusInvalidParams(t *testing.T) {
+	hooks := NewTradingStatusHooks(nil)
+	sdk.UpgradeMgr.AddUpgradeHeight(upgrade.DexEnhancements, 1)
+	sdk.UpgradeMgr.SetHeight(2)
+
+	for _, c := range []struct {
+		params dexTypes.TradingStatusParams
+		errMsg string
+	}{
+		{dexTypes.TradingStatusParams{QuoteAssetSymbol: "BNB"}, "base asset symbol should not be empty"},
+		{dexTypes.TradingStatusParams{BaseAssetSymbol: "BTC-2BD"}, "quote asset symbol should not be empty"},
+		{dexTypes.TradingStatusParams{BaseAssetSymbol: "BNB", QuoteAssetSymbol: "BNB"}, "base asset symbol and quote asset symbol should not be the same"},
+		{dexTypes.TradingStatusParams{BaseAssetSymbol: "BTC-2BD", QuoteAssetSymbol: "BNB", Status: "paused"}, "invalid trading status paused"},
+		{dexTypes.TradingStatusParams{BaseAssetSymbol: "BTC-2BD", QuoteAssetSymbol: "BNB", Status: "halted"}, "justification should not be empty"},
+		{dexTypes.TradingStatusParams{BaseAssetSymbol: "BTC-2BD", QuoteAssetSymbol: "BNB", Status: "halted", Justification: "Halt", IsExecuted: true}, "is_executed should be false"},
+	} {
+		c.params.Type = dexTypes.TradingStatusParamsType
+		paramsBz, err := json.Marshal(c.params)
+		require.Nil(t, err, "marshal trading status params error")
+		proposal := gov.TextProposal{
+			ProposalType: ProposalTypeTradingStatus,
+			Description:  string(paramsBz),
+		}
+
+		err = hooks.OnProposalSubmitted(sdk.Context{}, &proposal)
+		require.NotNil(t, err, "err should not be nil")
+		require.Contains(t, err.Error(), c.errMsg)
+	}
+
+	proposal := gov.TextProposal{
+		ProposalType: ProposalTypeTradingStatus,
+		Description:  `{"type":"dex/TradingStatus","base_asset_symbol":"BTC-2BD","quote_asset_symbol":"BNB","min_notional":"dust"}`,
+	}
+	err := hooks.OnProposalSubmitted(sdk.Context{}, &proposal)
+	require.NotNil(t, err, "err should not be nil")
+	require.Contains(t, err.Error(), "unmarshal trading status params error")
+}
+
+func TestTradingStatusProperTradingPair(t *testing.T) {
+	sdk.UpgradeMgr.AddUpgradeHeight(upgrade.DexEnhancements, 1)
+	sdk.UpgradeMgr.SetHeight(2)
+	statusParams := dexTypes.TradingStatusParams{
+		Type:             dexTypes.TradingStatusParamsType,
+		BaseAssetSymbol:  "BTC-2BD",
+		QuoteAssetSymbol: "BNB",
+		Status:           "cancel_only",
+		Justification:    "Cancel only",
+	}
+
+	statusParamsBz, err := json.Marshal(statusParams)
+	require.Nil(t, err, "marshal trading status params error")
+	proposal := gov.TextProposal{
+		ProposalType: ProposalTypeTradingStatus,
+		Description:  string(statusParamsBz),
+	}
+
+	cdc := MakeCodec()
+	ms, orderKeeper, _, _ := MakeKeepers(cdc)
+	hooks := NewTradingStatusHooks(orderKeeper)
+
+	ctx := sdk.NewContext(ms, abci.Header{}, sdk.RunTxModeDeliver, log.NewNopLogger())
+
+	err = hooks.OnProposalSubmitted(ctx, &proposal)
+	require.NotNil(t, err, "err should not be nil")
+	require.Contains(t, err.Error(), "trading pair BTC-2BD_BNB does not exist")
+
+	err = orderKeeper.PairMapper.AddTradingPair(ctx, dexTypes.NewTradingPair("BTC-2BD", "BNB", 1e8))
+	require.Nil(t, err, "add trading pair error")
+
+	err = hooks.OnProposalSubmitted(ctx, &proposal)
+	require.Nil(t, err, "err should be nil")
+}
+
+func TestTradingStatusOrderSizeLimits(t *testing.T) {
+	cdc := MakeCodec()
+	ms, orderKeeper, _, _ := MakeKeepers(cdc)
+	hooks := NewTradingStatusHooks(orderKeeper)
+
+	ctx := sdk.NewContext(ms, abci.Header{}, sdk.RunTxModeDeliver, log.NewNopLogger())
+	err := orderKeeper.PairMapper.AddTradingPair(ctx, dexTypes.NewTradingPair("BTC-2BD", "BNB", 1e8))
+	require.Nil(t, err, "add trading pair error")
+
+	submit := func(params dexTypes.TradingStatusParams) error {
+		params.Type = dexTypes.TradingStatusParamsType
+		paramsBz, err := json.Marshal(params)
+		require.Nil(t, err, "marshal trading status params error")
+		proposal := gov.TextProposal{
+			ProposalType: ProposalTypeTradingStatus,
+			Description:  string(paramsBz),
+		}
+		return hooks.OnProposalSubmitted(ctx, &proposal)
+	}
+	minNotional, maxQuantity, negative := int64(1e6), int64(1e16), int64(-1)
+
+	sdk.UpgradeMgr.AddUpgradeHeight(upgrade.DexEnhancements, 1)
+	sdk.UpgradeMgr.AddUpgradeHeight(upgrade.OrderSizeLimits, 2)
+	sdk.UpgradeMgr.SetHeight(1)
+	err = submit(dexTypes.TradingStatusParams{BaseAssetSymbol: "BTC-2BD", QuoteAssetSymbol: "BNB", MinNotional: &minNotional, Justification: "Dust"})
+	require.NotNil(t, err, "err should not be nil")
+	require.Contains(t, err.Error(), "order size limits are not supported yet")
+
+	sdk.UpgradeMgr.SetHeight(2)
+	for _, c := range []struct {
+		params dexTypes.TradingStatusParams
+		errMsg string
+	}{
+		{dexTypes.TradingStatusParams{BaseAssetSymbol: "BTC-2BD", QuoteAssetSymbol: "BNB", Justification: "Nothing"}, "trading status or order size limits should be changed"},
+		{dexTypes.TradingStatusParams{BaseAssetSymbol: "BTC-2BD", QuoteAssetSymbol: "BNB", MinNotional: &negative, Justification: "Dust"}, "min notional should not be less than 0"},
+		{dexTypes.TradingStatusParams{BaseAssetSymbol: "BTC-2BD", QuoteAssetSymbol: "BNB", MaxQuantity: &negative, Justification: "Whale"}, "max quantity should not be less than 0"},
+	} {
+		err = submit(c.params)
+		require.NotNil(t, err, "err should not be nil")
+		require.Contains(t, err.Error(), c.errMsg)
+	}
+
+	err = submit(dexTypes.TradingStatusParams{BaseAssetSymbol: "BTC-2BD", QuoteAssetSymbol: "BNB", MinNotional: &minNotional, MaxQuantity: &maxQuantity, Justification: "Dust"})
+	require.Nil(t, err, "err should be nil")
+}
+
+func TestDexParamsChangeBeforeUpgrade(t *testing.T) {
+	cdc := codec.New()
+	paramHub.RegisterWire(cdc)
+	cdc.RegisterConcrete(&order.SelfTradePreventionParam{}, "dex/SelfTradePreventionParam", nil)
+	hooks := NewDexParamsChangeHooks(cdc)
+
+	submit := func(feeParams ...param.FeeParam) error {
+		descBz, err := cdc.MarshalJSON(param.FeeChangeParams{FeeParams: feeParams, Description: "fee change"})
+		require.Nil(t, err, "marshal fee change params error")
+		proposal := gov.TextProposal{
+			ProposalType: gov.ProposalTypeFeeChange,
+			Description:  string(descBz),
+		}
+		return hooks.OnProposalSubmitted(sdk.Context{}, &proposal)
+	}
+	dexFee := &param.DexFeeParam{DexFeeFields: []param.DexFeeField{{FeeName: "ExpireFee", FeeValue: 1000}}}
+	stp := &order.SelfTradePreventionParam{Policy: "cancel_newest"}
+
+	sdk.UpgradeMgr.AddUpgradeHeight(upgrade.DexEnhancements, 2)
+	sdk.UpgradeMgr.SetHeight(1)
+	require.Nil(t, submit(dexFee), "err should be nil")
+	err := submit(dexFee, stp)
+	require.NotNil(t, err, "err should not be nil")
+	require.Contains(t, err.Error(), "SelfTradePrevention param is not supported yet")
+
+	sdk.UpgradeMgr.SetHeight(2)
+	require.Nil(t, submit(dexFee, stp), "err should be nil")
+
+	require.Panics(t, func() {
+		hooks.OnProposalSubmitted(sdk.Context{}, &gov.TextProposal{ProposalType: gov.ProposalTypeDelistTradingPair})
+	}, "should panic here")
+}
@@ -26,7 +26,17 @@ type MatchEng struct {
 	leastSurplus    SurplusIndex
 	Trades          []Trade
 	LastTradePrice  int64
-	logger          tmlog.Logger
+	// fill-or-kill orders of the coming match, and the ones killed by the last match
+	fokOrders       map[string]struct{}
+	KilledFOKOrders []OrderPart
+	// self-trade prevention policy, and the orders canceled or decremented by it in the last match.
+	// The decremented orders are recorded with the quantity after each decrement.
+	SelfTradePrevention  SelfTradePrevention
+	SelfTradeCanceled    []OrderPart
+	SelfTradeDecremented []OrderPart
+	// OrderSender returns the sender address of the order, the self-trade prevention is disabled without it
+	OrderSender OrderSenderFunc
+	logger      tmlog.Logger
 }
 
 // NewMatchEng constructs a new MatchEng.
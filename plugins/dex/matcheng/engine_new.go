@@ -2,6 +2,8 @@ package matcheng
 
 import (
 	"fmt"
+	"math"
+	"math/big"
 	"sort"
 
 	"github.com/pkg/errors"
@@ -13,31 +15,196 @@ import (
 )
 
 func (me *MatchEng) Match(height int64) bool {
+	me.KilledFOKOrders = me.KilledFOKOrders[:0]
+	me.SelfTradeCanceled = me.SelfTradeCanceled[:0]
+	me.SelfTradeDecremented = me.SelfTradeDecremented[:0]
 	success := me.runMatch(height)
 	if sdk.IsUpgrade(upgrade.BEP19) {
 		me.LastMatchHeight = height
 	}
+	// fill-or-kill orders only live for one match round
+	me.fokOrders = nil
 	return success
 }
-func (me *MatchEng) runMatch(height int64) bool {
+
+// AddFOKOrder marks an order in the book as fill-or-kill for the coming match.
+// The order is removed before the quantity allocation if it cannot be fully filled,
+// and can be found in KilledFOKOrders after the match.
+func (me *MatchEng) AddFOKOrder(id string) {
+	if me.fokOrders == nil {
+		me.fokOrders = make(map[string]struct{})
+	}
+	me.fokOrders[id] = struct{}{}
+}
+
+// RequeueOrder moves an order to the back of its price level with the given time and keeps its
+// filled quantity, so that the order loses its time priority among the orders of the same price.
+func (me *MatchEng) RequeueOrder(id string, side int8, price int64, time int64) error {
+	pl := me.Book.GetPriceLevel(price, side)
+	if pl == nil {
+		return fmt.Errorf("order price %d doesn't exist at side %d", price, side)
+	}
+	ord, _, err := pl.removeOrder(id)
+	if err != nil {
+		return err
+	}
+	ord.Time = time
+	pl.Orders = append(pl.Orders, ord)
+	return nil
+}
+
+// AmendOrder changes the price and quantity of an order in the book and keeps its filled quantity.
+// Reducing the quantity at the same price keeps the time priority of the order, otherwise the order
+// is moved to the back of the price level of the new price with the given time.
+func (me *MatchEng) AmendOrder(id string, side int8, price, newPrice, newQty, time int64) error {
+	if newPrice == price {
+		if pl := me.Book.GetPriceLevel(price, side); pl != nil {
+			for i := range pl.Orders {
+				if pl.Orders[i].Id == id && newQty < pl.Orders[i].Qty {
+					pl.Orders[i].Qty = newQty
+					return nil
+				}
+			}
+		}
+	}
+	ord, err := me.Book.RemoveOrder(id, side, price)
+	if err != nil {
+		return err
+	}
+	pl, err := me.Book.InsertOrder(id, side, time, newPrice, newQty)
+	if err != nil {
+		return err
+	}
+	if pl = me.Book.GetPriceLevel(newPrice, side); pl != nil {
+		pl.Orders[len(pl.Orders)-1].CumQty = ord.CumQty
+	}
+	return nil
+}
+
+// MarketOrderPrice returns the worst price a market order of the side is allowed to be executed at,
+// which is the price limit around the last trade price rounded to tickSize within the limit. Market orders
+// enter the book at this price, so that they take part in every overlapped price level and fill at the
+// concluded price. The price is calculated in integer math, with the price limit in 1e8 precision.
+func (me *MatchEng) MarketOrderPrice(side int8, tickSize int64) (int64, error) {
+	if me.LastTradePrice <= 0 {
+		return 0, errors.New("no reference price for market orders")
+	}
+	if tickSize <= 0 {
+		return 0, fmt.Errorf("invalid tick size: %d", tickSize)
+	}
+	var bi big.Int
+	limitPct := int64(math.Round(me.PriceLimitPct * 1e8))
+	priceRange := bi.Div(bi.Mul(big.NewInt(me.LastTradePrice), big.NewInt(limitPct)), big.NewInt(1e8)).Int64()
+	if side == BUYSIDE {
+		price := me.LastTradePrice + priceRange
+		if price < me.LastTradePrice {
+			// overflow
+			price = math.MaxInt64
+		}
+		return price - price%tickSize, nil
+	}
+	price := me.LastTradePrice - priceRange
+	if remainder := price % tickSize; remainder != 0 {
+		price += tickSize - remainder
+	}
+	if price <= 0 {
+		return tickSize, nil
+	}
+	return price, nil
+}
+
+// TakerOrders returns the orders which would be executed as takers if the order book was matched now.
+// It goes through the same trade price calculation and maker/taker split as Match, but fills nothing.
+func (me *MatchEng) TakerOrders() []OrderPart {
 	if !sdk.IsUpgrade(upgrade.BEP19) {
-		return me.MatchBeforeGalileo(height)
+		return nil
 	}
-	me.logger.Debug("match starts...", "height", height)
-	me.Trades = me.Trades[:0]
 	r := me.Book.GetOverlappedRange(&me.overLappedLevel, &me.buyBuf, &me.sellBuf)
 	if r <= 0 {
-		return true
+		return nil
 	}
 	prepareMatch(&me.overLappedLevel)
-	tradePrice, index := getTradePrice(&me.overLappedLevel, &me.maxExec, &me.leastSurplus, me.LastTradePrice, me.PriceLimitPct)
+	_, index := getTradePrice(&me.overLappedLevel, &me.maxExec, &me.leastSurplus, me.LastTradePrice, me.PriceLimitPct)
 	if index < 0 {
-		return false
+		return nil
+	}
+	takerSide, err := me.determineTakerSide(index)
+	if err != nil {
+		me.logger.Error("determineTakerSide failed", "error", err)
+		return nil
+	}
+	var takers []OrderPart
+	if takerSide == BUYSIDE {
+		for i := 0; i <= index; i++ {
+			l := &me.overLappedLevel[i]
+			takers = append(takers, l.BuyOrders[l.BuyTakerStartIdx:]...)
+		}
+	} else {
+		for i := len(me.overLappedLevel) - 1; i >= index; i-- {
+			l := &me.overLappedLevel[i]
+			takers = append(takers, l.SellOrders[l.SellTakerStartIdx:]...)
+		}
 	}
+	return takers
+}
 
-	if err := me.dropRedundantQty(index); err != nil {
-		me.logger.Error("dropRedundantQty failed", "error", err)
-		return false
+// IndicativeMatch calculates where the auction would conclude if the order book was matched now, in the same way
+// as Match calculates the trade price. It works on a copy of the overlapped price levels, so neither the book
+// nor the buffers of the engine are touched. The FOK orders and the self-trade prevention are not considered.
+// It returns false if the book is not crossed.
+func (me *MatchEng) IndicativeMatch() (IndicativeMatch, bool) {
+	var overlapped []OverLappedLevel
+	var buyBuf, sellBuf []PriceLevel
+	if me.Book.GetOverlappedRange(&overlapped, &buyBuf, &sellBuf) <= 0 {
+		return IndicativeMatch{}, false
+	}
+	// the orders of the levels are shared with the book, while prepareMatch writes to them
+	for i := range overlapped {
+		l := &overlapped[i]
+		l.BuyOrders = append([]OrderPart(nil), l.BuyOrders...)
+		l.SellOrders = append([]OrderPart(nil), l.SellOrders...)
+	}
+	prepareMatch(&overlapped)
+	var maxExec LevelIndex
+	var leastSurplus SurplusIndex
+	tradePrice, index := getTradePrice(&overlapped, &maxExec, &leastSurplus, me.LastTradePrice, me.PriceLimitPct)
+	if index < 0 {
+		return IndicativeMatch{}, false
+	}
+	return IndicativeMatch{
+		Price:   tradePrice,
+		ExecQty: overlapped[index].AccumulatedExecutions,
+		Surplus: overlapped[index].BuySellSurplus,
+	}, true
+}
+
+func (me *MatchEng) runMatch(height int64) bool {
+	if !sdk.IsUpgrade(upgrade.BEP19) {
+		return me.MatchBeforeGalileo(height)
+	}
+	me.logger.Debug("match starts...", "height", height)
+	me.Trades = me.Trades[:0]
+	var tradePrice int64
+	var index int
+	for {
+		r := me.Book.GetOverlappedRange(&me.overLappedLevel, &me.buyBuf, &me.sellBuf)
+		if r <= 0 {
+			return true
+		}
+		prepareMatch(&me.overLappedLevel)
+		tradePrice, index = getTradePrice(&me.overLappedLevel, &me.maxExec, &me.leastSurplus, me.LastTradePrice, me.PriceLimitPct)
+		if index < 0 {
+			return false
+		}
+
+		if err := me.dropRedundantQty(index); err != nil {
+			me.logger.Error("dropRedundantQty failed", "error", err)
+			return false
+		}
+		// killing orders changes the overlapped levels, so the trade price has to be calculated again
+		if !me.killUnfilledFOKOrders(index) && !me.preventSelfTrades(index) {
+			break
+		}
 	}
 	//If order height > the last Match height, then it's maker.
 	takerSide, err := me.determineTakerSide(index)
@@ -52,6 +219,161 @@ func (me *MatchEng) runMatch(height int64) bool {
 	return true
 }
 
+// killUnfilledFOKOrders removes the fill-or-kill orders which cannot be fully filled at the trade price
+// from the order book, so that they are excluded before the quantity is allocated to the takers.
+// It returns true if any order is killed.
+func (me *MatchEng) killUnfilledFOKOrders(tradePriceIdx int) bool {
+	if len(me.fokOrders) == 0 {
+		return false
+	}
+	type toKill struct {
+		id    string
+		side  int8
+		price int64
+	}
+	var kills []toKill
+	collect := func(orders []OrderPart, side int8, price int64, executable bool) {
+		for i := range orders {
+			o := &orders[i]
+			if _, ok := me.fokOrders[o.Id]; !ok {
+				continue
+			}
+			if executable && o.nxtTrade == o.LeavesQty() {
+				continue
+			}
+			kills = append(kills, toKill{o.Id, side, price})
+		}
+	}
+	for i := range me.overLappedLevel {
+		l := &me.overLappedLevel[i]
+		collect(l.BuyOrders, BUYSIDE, l.Price, i <= tradePriceIdx)
+		collect(l.SellOrders, SELLSIDE, l.Price, i >= tradePriceIdx)
+	}
+
+	killed := false
+	for _, k := range kills {
+		delete(me.fokOrders, k.id)
+		ord, err := me.Book.RemoveOrder(k.id, k.side, k.price)
+		if err != nil {
+			me.logger.Error("Failed to remove FOK order", "orderID", k.id, "error", err)
+			continue
+		}
+		me.KilledFOKOrders = append(me.KilledFOKOrders, ord)
+		killed = true
+	}
+	return killed
+}
+
+// selfTradeOrder locates an order of the order book which would take part in a self-trade
+type selfTradeOrder struct {
+	ord   OrderPart
+	side  int8
+	price int64
+}
+
+func (o *selfTradeOrder) isNewerThan(other *selfTradeOrder) bool {
+	if o.ord.Time != other.ord.Time {
+		return o.ord.Time > other.ord.Time
+	}
+	return o.ord.sequence() > other.ord.sequence()
+}
+
+// preventSelfTrades applies the self-trade prevention policy to the first sender found with orders
+// executed on both sides at the trade price. All the executed orders of the two sides are pooled together
+// in the same match, so any such pair of orders would be a self-trade. It returns true if any order is changed,
+// and should be called repeatedly until no more self-trade is found.
+func (me *MatchEng) preventSelfTrades(tradePriceIdx int) bool {
+	if me.SelfTradePrevention == STPNone || me.OrderSender == nil {
+		return false
+	}
+	// the first executed buy order of every sender, starting from the best price
+	buys := make(map[string]selfTradeOrder)
+	for i := 0; i <= tradePriceIdx; i++ {
+		l := &me.overLappedLevel[i]
+		for j := range l.BuyOrders {
+			o := &l.BuyOrders[j]
+			if o.nxtTrade <= 0 {
+				continue
+			}
+			sender := me.OrderSender(o.Id)
+			if _, ok := buys[sender]; !ok && sender != "" {
+				buys[sender] = selfTradeOrder{*o, BUYSIDE, l.Price}
+			}
+		}
+	}
+	if len(buys) == 0 {
+		return false
+	}
+	// the sell orders are scanned in a fixed sequence, so the result doesn't depend on the map iteration
+	for i := len(me.overLappedLevel) - 1; i >= tradePriceIdx; i-- {
+		l := &me.overLappedLevel[i]
+		for j := range l.SellOrders {
+			o := &l.SellOrders[j]
+			if o.nxtTrade <= 0 {
+				continue
+			}
+			if buy, ok := buys[me.OrderSender(o.Id)]; ok {
+				sell := selfTradeOrder{*o, SELLSIDE, l.Price}
+				me.resolveSelfTrade(&buy, &sell)
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (me *MatchEng) resolveSelfTrade(buy, sell *selfTradeOrder) {
+	newer, older := buy, sell
+	if sell.isNewerThan(buy) {
+		newer, older = sell, buy
+	}
+	switch me.SelfTradePrevention {
+	case STPCancelNewest:
+		me.cancelSelfTradeOrder(newer)
+	case STPCancelOldest:
+		me.cancelSelfTradeOrder(older)
+	case STPCancelBoth:
+		me.cancelSelfTradeOrder(buy)
+		me.cancelSelfTradeOrder(sell)
+	case STPDecrement:
+		qty := utils.MinInt(buy.ord.LeavesQty(), sell.ord.LeavesQty())
+		me.decrementSelfTradeOrder(buy, qty)
+		me.decrementSelfTradeOrder(sell, qty)
+	}
+}
+
+func (me *MatchEng) cancelSelfTradeOrder(o *selfTradeOrder) {
+	delete(me.fokOrders, o.ord.Id)
+	ord, err := me.Book.RemoveOrder(o.ord.Id, o.side, o.price)
+	if err != nil {
+		me.logger.Error("Failed to remove self-trade order", "orderID", o.ord.Id, "error", err)
+		return
+	}
+	me.SelfTradeCanceled = append(me.SelfTradeCanceled, ord)
+}
+
+// decrementSelfTradeOrder reduces the quantity of the order by qty, and cancels the order if nothing is left
+func (me *MatchEng) decrementSelfTradeOrder(o *selfTradeOrder, qty int64) {
+	if o.ord.LeavesQty() <= qty {
+		me.cancelSelfTradeOrder(o)
+		return
+	}
+	pl := me.Book.GetPriceLevel(o.price, o.side)
+	if pl == nil {
+		me.logger.Error("Failed to find self-trade order", "orderID", o.ord.Id)
+		return
+	}
+	for i := range pl.Orders {
+		if pl.Orders[i].Id == o.ord.Id {
+			pl.Orders[i].Qty -= qty
+			me.SelfTradeDecremented = append(me.SelfTradeDecremented, OrderPart{
+				Id: o.ord.Id, Time: pl.Orders[i].Time, Qty: pl.Orders[i].Qty, CumQty: pl.Orders[i].CumQty})
+			return
+		}
+	}
+	me.logger.Error("Failed to find self-trade order", "orderID", o.ord.Id)
+}
+
 func (me *MatchEng) dropRedundantQty(tradePriceLevelIdx int) error {
 	tradePriceLevel := me.overLappedLevel[tradePriceLevelIdx]
 	totalExec := tradePriceLevel.AccumulatedExecutions
@@ -1015,3 +1015,221 @@ func TestMatchEng_Match(t *testing.T) {
 		},
 	}}, sells)
 }
+
+func TestMatchEng_MarketOrderPrice(t *testing.T) {
+	assert := assert.New(t)
+	me := NewMatchEng(DefaultPairSymbol, 100, 5, 0.05)
+	checkPrice := func(expected int64, side int8, tickSize int64) {
+		price, err := me.MarketOrderPrice(side, tickSize)
+		assert.NoError(err)
+		assert.Equal(expected, price)
+	}
+	checkPrice(105, BUYSIDE, 1)
+	checkPrice(95, SELLSIDE, 1)
+	// rounded to tickSize within the price limit
+	checkPrice(104, BUYSIDE, 4)
+	checkPrice(96, SELLSIDE, 4)
+	me.LastTradePrice = 1
+	checkPrice(1, BUYSIDE, 1)
+	checkPrice(1, SELLSIDE, 1)
+	me.LastTradePrice = 1e8
+	checkPrice(1.05e8, BUYSIDE, 1e3)
+	checkPrice(0.95e8, SELLSIDE, 1e3)
+	me.LastTradePrice = 1e8 + 1
+	checkPrice(1.05e8, BUYSIDE, 1e3)
+	checkPrice(0.95001e8, SELLSIDE, 1e3)
+
+	me.LastTradePrice = 0
+	_, err := me.MarketOrderPrice(BUYSIDE, 1)
+	assert.EqualError(err, "no reference price for market orders")
+}
+
+func TestMatchEng_MatchMarketOrder(t *testing.T) {
+	upgrade.Mgr.AddUpgradeHeight(upgrade.BEP19, 1)
+
+	assert := assert.New(t)
+	me := NewMatchEng(DefaultPairSymbol, 100, 5, 0.05)
+	me.Book = NewOrderBookOnULList(4, 2)
+	me.Book.InsertOrder("1", SELLSIDE, 90, 100, 10)
+	me.Book.InsertOrder("3", SELLSIDE, 90, 104, 10)
+	me.Book.InsertOrder("5", SELLSIDE, 90, 106, 10)
+	// market buy order enters the book at the price limit
+	marketPrice, _ := me.MarketOrderPrice(BUYSIDE, 1)
+	me.Book.InsertOrder("2", BUYSIDE, 100, marketPrice, 30)
+
+	upgrade.Mgr.SetHeight(100)
+	me.LastMatchHeight = 99
+	assert.True(me.Match(100))
+	assert.Equal(int64(105), me.LastTradePrice)
+	assert.Equal([]Trade{
+		{"1", 100, 10, 10, 10, "2", BuyTaker, nil, nil},
+		{"3", 104, 10, 20, 10, "2", BuyTaker, nil, nil},
+	}, me.Trades)
+	me.DropFilledOrder()
+	buys, sells := me.Book.GetAllLevels()
+	assert.Equal([]PriceLevel{{
+		Price: 105,
+		Orders: []OrderPart{
+			{"2", 100, 30, 20, 10},
+		},
+	}}, buys)
+	assert.Equal([]PriceLevel{{
+		Price: 106,
+		Orders: []OrderPart{
+			{"5", 90, 10, 0, 0},
+		},
+	}}, sells)
+}
+
+func TestMatchEng_TakerOrders(t *testing.T) {
+	upgrade.Mgr.AddUpgradeHeight(upgrade.BEP19, 1)
+	upgrade.Mgr.SetHeight(100)
+
+	assert := assert.New(t)
+	me := NewMatchEng(DefaultPairSymbol, 100, 5, 0.05)
+	me.Book = NewOrderBookOnULList(4, 2)
+	me.LastMatchHeight = 99
+	assert.Nil(me.TakerOrders())
+
+	me.Book.InsertOrder("1", SELLSIDE, 90, 100, 10)
+	me.Book.InsertOrder("2", BUYSIDE, 100, 99, 10)
+	assert.Nil(me.TakerOrders())
+
+	me.Book.InsertOrder("3", BUYSIDE, 100, 101, 5)
+	me.Book.InsertOrder("4", SELLSIDE, 100, 101, 5)
+	takers := me.TakerOrders()
+	assert.Len(takers, 1)
+	assert.Equal("3", takers[0].Id)
+
+	// the book is untouched
+	buys, sells := me.Book.GetAllLevels()
+	assert.Len(buys, 2)
+	assert.Len(sells, 2)
+}
+
+func TestMatchEng_IndicativeMatch(t *testing.T) {
+	upgrade.Mgr.AddUpgradeHeight(upgrade.BEP19, 1)
+	upgrade.Mgr.SetHeight(100)
+
+	assert := assert.New(t)
+	me := NewMatchEng(DefaultPairSymbol, 100, 5, 0.05)
+	me.Book = NewOrderBookOnULList(4, 2)
+	me.LastMatchHeight = 99
+	_, ok := me.IndicativeMatch()
+	assert.False(ok)
+
+	me.Book.InsertOrder("1", SELLSIDE, 90, 100, 10)
+	me.Book.InsertOrder("2", BUYSIDE, 100, 101, 20)
+	match, ok := me.IndicativeMatch()
+	assert.True(ok)
+	assert.Equal(IndicativeMatch{Price: 101, ExecQty: 10, Surplus: 10}, match)
+
+	// neither the book nor the buffers of the engine are touched
+	buys, sells := me.Book.GetAllLevels()
+	assert.Equal([]PriceLevel{{101, []OrderPart{{"2", 100, 20, 0, 0}}}}, buys)
+	assert.Equal([]PriceLevel{{100, []OrderPart{{"1", 90, 10, 0, 0}}}}, sells)
+	assert.Empty(me.overLappedLevel)
+	assert.True(me.Match(100))
+	assert.Equal(match.Price, me.LastTradePrice)
+}
+
+func TestMatchEng_FOKOrder(t *testing.T) {
+	upgrade.Mgr.AddUpgradeHeight(upgrade.BEP19, 1)
+	upgrade.Mgr.SetHeight(100)
+
+	assert := assert.New(t)
+	me := NewMatchEng(DefaultPairSymbol, 100, 5, 0.05)
+	me.Book = NewOrderBookOnULList(4, 2)
+	me.LastMatchHeight = 99
+	me.Book.InsertOrder("1", SELLSIDE, 90, 100, 10)
+	me.Book.InsertOrder("2", BUYSIDE, 100, 101, 20)
+	me.AddFOKOrder("2")
+	me.Book.InsertOrder("3", BUYSIDE, 100, 100, 5)
+
+	// the FOK order cannot be fully filled, so it is killed before the allocation
+	assert.True(me.Match(100))
+	assert.Len(me.KilledFOKOrders, 1)
+	assert.Equal("2", me.KilledFOKOrders[0].Id)
+	assert.Equal(int64(0), me.KilledFOKOrders[0].CumQty)
+	assert.Equal([]Trade{
+		{"1", 100, 5, 5, 5, "3", BuyTaker, nil, nil},
+	}, me.Trades)
+	me.DropFilledOrder()
+	buys, _ := me.Book.GetAllLevels()
+	assert.Len(buys, 0)
+
+	// the FOK order is fully filled by the rest of the sell order
+	me.Book.InsertOrder("4", BUYSIDE, 101, 101, 5)
+	me.AddFOKOrder("4")
+	assert.True(me.Match(101))
+	assert.Len(me.KilledFOKOrders, 0)
+	assert.Equal([]Trade{
+		{"1", 100, 5, 5, 10, "4", BuyTaker, nil, nil},
+	}, me.Trades)
+}
+
+func TestMatchEng_SelfTradePrevention(t *testing.T) {
+	upgrade.Mgr.AddUpgradeHeight(upgrade.BEP19, 1)
+	upgrade.Mgr.SetHeight(100)
+
+	tests := []struct {
+		stp         SelfTradePrevention
+		canceled    []string
+		decremented []OrderPart
+		trades      []Trade
+	}{
+		{STPCancelNewest, []string{"A-2"}, nil, []Trade{{"A-1", 100, 10, 10, 10, "B-1", BuyTaker, nil, nil}}},
+		{STPCancelOldest, []string{"A-1"}, nil, []Trade{}},
+		{STPCancelBoth, []string{"A-2", "A-1"}, nil, []Trade{}},
+		{STPDecrement, []string{"A-2"}, []OrderPart{{"A-1", 90, 5, 0, 0}}, []Trade{{"A-1", 100, 5, 5, 5, "B-1", BuyTaker, nil, nil}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.stp.String(), func(t *testing.T) {
+			assert := assert.New(t)
+			me := NewMatchEng(DefaultPairSymbol, 100, 5, 0.05)
+			me.Book = NewOrderBookOnULList(4, 2)
+			me.LastMatchHeight = 99
+			me.SelfTradePrevention = tt.stp
+			senders := map[string]string{"A-1": "A", "A-2": "A", "B-1": "B"}
+			me.OrderSender = func(id string) string { return senders[id] }
+			me.Book.InsertOrder("A-1", SELLSIDE, 90, 100, 10)
+			me.Book.InsertOrder("A-2", BUYSIDE, 100, 100, 5)
+			me.Book.InsertOrder("B-1", BUYSIDE, 100, 100, 10)
+
+			assert.True(me.Match(100))
+			var canceled []string
+			for _, ord := range me.SelfTradeCanceled {
+				canceled = append(canceled, ord.Id)
+			}
+			assert.Equal(tt.canceled, canceled)
+			assert.Equal(tt.decremented, me.SelfTradeDecremented)
+			assert.Equal(tt.trades, me.Trades)
+		})
+	}
+}
+
+func TestMatchEng_SelfTradePreventionWithoutSender(t *testing.T) {
+	upgrade.Mgr.AddUpgradeHeight(upgrade.BEP19, 1)
+	upgrade.Mgr.SetHeight(100)
+
+	assert := assert.New(t)
+	me := NewMatchEng(DefaultPairSymbol, 100, 5, 0.05)
+	me.Book = NewOrderBookOnULList(4, 2)
+	me.LastMatchHeight = 99
+	me.SelfTradePrevention = STPCancelBoth
+	me.Book.InsertOrder("A-1", SELLSIDE, 90, 100, 10)
+	me.Book.InsertOrder("A-2", BUYSIDE, 100, 100, 10)
+
+	// the orders are never treated as self-trades without knowing their senders
+	assert.True(me.Match(100))
+	assert.Len(me.SelfTradeCanceled, 0)
+	assert.Equal([]Trade{{"A-1", 100, 10, 10, 10, "A-2", BuyTaker, nil, nil}}, me.Trades)
+}
+
+func TestOrderPart_sequence(t *testing.T) {
+	assert := assert.New(t)
+	ord := OrderPart{Id: "D3FA781529604EABDA035664316B944A38BD6700-12"}
+	assert.Equal(int64(12), ord.sequence())
+	ord = OrderPart{Id: "123456"}
+	assert.Equal(int64(-1), ord.sequence())
+}
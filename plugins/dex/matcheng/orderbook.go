@@ -40,6 +40,26 @@ type OrderBookOnULList struct {
 }
 
 var _ OrderBookInterface = (*OrderBookOnULList)(nil)
+var _ OrderBookInterface = (*OrderBookOnBTree)(nil)
+
+// the order book implementations which can be chosen for a trading pair
+const (
+	OrderBookTypeULList = "ullist"
+	OrderBookTypeBTree  = "btree"
+)
+
+// NewOrderBook creates an empty order book of the given implementation,
+// the unrolled-linked list is used if the type is empty.
+func NewOrderBook(bookType string) (OrderBookInterface, error) {
+	switch bookType {
+	case "", OrderBookTypeULList:
+		return NewOrderBookOnULList(10000, 16), nil
+	case OrderBookTypeBTree:
+		return NewOrderBookOnBTree(8), nil
+	default:
+		return nil, fmt.Errorf("unknown order book type %s", bookType)
+	}
+}
 
 func NewOrderBookOnULList(capacity int, bucketSize int) *OrderBookOnULList {
 	//TODO: find out the best degree
@@ -301,6 +321,115 @@ func (ob *OrderBookOnBTree) RemoveOrder(id string, side int8, price int64) (Orde
 	}
 }
 
+func (ob *OrderBookOnBTree) InsertPriceLevel(pl *PriceLevel, side int8) error {
+	q := ob.getSideQueue(side)
+	if q.Has(newPriceLevelKey(pl.Price, side)) {
+		return fmt.Errorf("Failed to insert price level at price %d", pl.Price)
+	}
+	q.ReplaceOrInsert(newPriceLevelBySide(pl.Price, pl.Orders, side))
+	return nil
+}
+
+func (ob *OrderBookOnBTree) RemoveOrders(beforeTime int64, side int8, cb func(OrderPart)) error {
+	ob.UpdateForEachPriceLevel(side, func(pl *PriceLevel, levelIndex int) {
+		pl.removeOrders(beforeTime, cb)
+	})
+	return nil
+}
+
+// order beyond priceLevelsToReserve will be expired if it's placed before expireTime. All orders will be expired if they are placed before forceExpireTime
+func (ob *OrderBookOnBTree) RemoveOrdersBasedOnPriceLevel(expireTime int64, forceExpireTime int64, priceLevelsToReserve int, side int8, removeCallback func(ord OrderPart)) error {
+	ob.UpdateForEachPriceLevel(side, func(pl *PriceLevel, levelIndex int) {
+		if levelIndex < priceLevelsToReserve {
+			pl.removeOrders(forceExpireTime, removeCallback)
+		} else {
+			pl.removeOrders(expireTime, removeCallback)
+		}
+	})
+	return nil
+}
+
+// UpdateForEachPriceLevel iterates the price levels from the best price, and removes the levels without orders
+// after the update. The tree cannot be modified during the iteration, so the empty levels are deleted afterwards.
+func (ob *OrderBookOnBTree) UpdateForEachPriceLevel(side int8, updater LevelIter) {
+	q := ob.getSideQueue(side)
+	var emptyLevels []bt.Item
+	levelIndex := 0
+	q.Ascend(func(i bt.Item) bool {
+		pl := toPriceLevel(i.(PriceLevelInterface), side)
+		updater(pl, levelIndex)
+		levelIndex++
+		if len(pl.Orders) == 0 {
+			emptyLevels = append(emptyLevels, i)
+		}
+		return true
+	})
+	for _, i := range emptyLevels {
+		q.Delete(i)
+	}
+}
+
+func (ob *OrderBookOnBTree) GetOrder(id string, side int8, price int64) (OrderPart, error) {
+	var pl *PriceLevel
+	if pl = ob.GetPriceLevel(price, side); pl == nil {
+		return OrderPart{}, fmt.Errorf("order price %d doesn't exist at side %d.", price, side)
+	}
+	return pl.getOrder(id)
+}
+
+func (ob *OrderBookOnBTree) GetPriceLevel(price int64, side int8) *PriceLevel {
+	q := ob.getSideQueue(side)
+	if pl := q.Get(newPriceLevelKey(price, side)); pl != nil {
+		return toPriceLevel(pl.(PriceLevelInterface), side)
+	}
+	return nil
+}
+
+func (ob *OrderBookOnBTree) RemovePriceLevel(price int64, side int8) int {
+	q := ob.getSideQueue(side)
+	if q.Delete(newPriceLevelKey(price, side)) != nil {
+		return 1
+	}
+	return 0
+}
+
+func (ob *OrderBookOnBTree) ShowDepth(maxLevels int, iterBuy LevelIter, iterSell LevelIter) {
+	iterateBTree(ob.buyQueue, BUYSIDE, maxLevels, iterBuy)
+	iterateBTree(ob.sellQueue, SELLSIDE, maxLevels, iterSell)
+}
+
+func (ob *OrderBookOnBTree) GetAllLevels() ([]PriceLevel, []PriceLevel) {
+	buys := make([]PriceLevel, 0, ob.buyQueue.Len())
+	sells := make([]PriceLevel, 0, ob.sellQueue.Len())
+	iterateBTree(ob.buyQueue, BUYSIDE, ob.buyQueue.Len(),
+		func(p *PriceLevel, levelIndex int) {
+			buys = append(buys, *p)
+		})
+	iterateBTree(ob.sellQueue, SELLSIDE, ob.sellQueue.Len(),
+		func(p *PriceLevel, levelIndex int) {
+			sells = append(sells, *p)
+		})
+	return buys, sells
+}
+
+func (ob *OrderBookOnBTree) Clear() {
+	ob.buyQueue.Clear(false)
+	ob.sellQueue.Clear(false)
+}
+
+// iterateBTree iterates at most levelNum price levels from the best price, the tree must not be modified by iter
+func iterateBTree(q *bt.BTree, side int8, levelNum int, iter LevelIter) {
+	var curLevel int
+	q.Ascend(func(i bt.Item) bool {
+		if curLevel >= levelNum {
+			return false
+		}
+		iter(toPriceLevel(i.(PriceLevelInterface), side), curLevel)
+		curLevel++
+		return true
+	})
+}
+
 func toPriceLevel(pi PriceLevelInterface, side int8) *PriceLevel {
 	switch side {
 	case BUYSIDE:
@@ -347,6 +476,6 @@ func newPriceLevelKey(price int64, side int8) PriceLevelInterface {
 
 func NewOrderBookOnBTree(d int) *OrderBookOnBTree {
 	//TODO: find out the best degree
-	// 16 is my magic number, hopefully the real overlapped levels are less
-	return &OrderBookOnBTree{bt.New(8), bt.New(8)}
+	// 8 is my magic number, hopefully the real overlapped levels are less
+	return &OrderBookOnBTree{bt.New(d), bt.New(d)}
 }
@@ -2,6 +2,7 @@ package matcheng
 
 import (
 	"fmt"
+	"math/rand"
 	"reflect"
 	"strings"
 	"testing"
@@ -9,6 +10,8 @@ import (
 	bt "github.com/google/btree"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/node/common/upgrade"
 )
 
 func Test_compareBuy(t *testing.T) {
@@ -822,3 +825,285 @@ func TestOrderBookOnBTree_GetOverlappedRange(t *testing.T) {
 	assert.Equal(int64(9855), overlap[j+1].Price)
 	assert.Equal(int64(9750), overlap[j+2].Price)
 }
+
+var orderBookImpls = []struct {
+	name    string
+	newBook func() OrderBookInterface
+}{
+	{"ULList", func() OrderBookInterface { return NewOrderBookOnULList(16, 4) }},
+	{"BTree", func() OrderBookInterface { return NewOrderBookOnBTree(4) }},
+}
+
+func TestNewOrderBook(t *testing.T) {
+	book, err := NewOrderBook("")
+	require.NoError(t, err)
+	require.IsType(t, &OrderBookOnULList{}, book)
+	book, err = NewOrderBook(OrderBookTypeULList)
+	require.NoError(t, err)
+	require.IsType(t, &OrderBookOnULList{}, book)
+	book, err = NewOrderBook(OrderBookTypeBTree)
+	require.NoError(t, err)
+	require.IsType(t, &OrderBookOnBTree{}, book)
+	_, err = NewOrderBook("skiplist")
+	require.EqualError(t, err, "unknown order book type skiplist")
+}
+
+// TestOrderBook_Conformance runs the same cases on all the implementations of OrderBookInterface
+func TestOrderBook_Conformance(t *testing.T) {
+	for _, impl := range orderBookImpls {
+		t.Run(impl.name, func(t *testing.T) {
+			book := impl.newBook()
+			book.InsertOrder("b1", BUYSIDE, 100, 1000, 10)
+			book.InsertOrder("b2", BUYSIDE, 101, 1000, 20)
+			book.InsertOrder("b3", BUYSIDE, 101, 990, 30)
+			book.InsertOrder("s1", SELLSIDE, 100, 1010, 10)
+			book.InsertOrder("s2", SELLSIDE, 102, 1020, 20)
+			_, err := book.InsertOrder("b1", BUYSIDE, 103, 1000, 10)
+			require.Error(t, err, "duplicated order")
+			require.Error(t, book.InsertPriceLevel(&PriceLevel{990, []OrderPart{{Id: "b4"}}}, BUYSIDE))
+			require.NoError(t, book.InsertPriceLevel(&PriceLevel{980, []OrderPart{{"b4", 99, 40, 0, 0}}}, BUYSIDE))
+
+			buys, sells := book.GetAllLevels()
+			require.Equal(t, []PriceLevel{
+				{1000, []OrderPart{{"b1", 100, 10, 0, 0}, {"b2", 101, 20, 0, 0}}},
+				{990, []OrderPart{{"b3", 101, 30, 0, 0}}},
+				{980, []OrderPart{{"b4", 99, 40, 0, 0}}},
+			}, buys)
+			require.Equal(t, []PriceLevel{
+				{1010, []OrderPart{{"s1", 100, 10, 0, 0}}},
+				{1020, []OrderPart{{"s2", 102, 20, 0, 0}}},
+			}, sells)
+
+			ord, err := book.GetOrder("b2", BUYSIDE, 1000)
+			require.NoError(t, err)
+			require.Equal(t, OrderPart{"b2", 101, 20, 0, 0}, ord)
+			_, err = book.GetOrder("b2", BUYSIDE, 990)
+			require.Error(t, err)
+			_, err = book.GetOrder("b2", SELLSIDE, 1000)
+			require.Error(t, err)
+			require.Equal(t, int64(30), book.GetPriceLevel(1000, BUYSIDE).TotalLeavesQty())
+			require.Nil(t, book.GetPriceLevel(1000, SELLSIDE))
+
+			var depth []int64
+			book.ShowDepth(2, func(p *PriceLevel, levelIndex int) {
+				depth = append(depth, p.Price)
+			}, func(p *PriceLevel, levelIndex int) {
+				depth = append(depth, -p.Price)
+			})
+			require.Equal(t, []int64{1000, 990, -1010, -1020}, depth)
+
+			ord, err = book.RemoveOrder("s1", SELLSIDE, 1010)
+			require.NoError(t, err)
+			require.Equal(t, "s1", ord.Id)
+			require.Nil(t, book.GetPriceLevel(1010, SELLSIDE), "empty price level is removed")
+			_, err = book.RemoveOrder("s1", SELLSIDE, 1020)
+			require.Error(t, err)
+			require.Equal(t, 1, book.RemovePriceLevel(1020, SELLSIDE))
+			require.Equal(t, 0, book.RemovePriceLevel(1020, SELLSIDE))
+
+			var levelIndexes []int
+			book.UpdateForEachPriceLevel(BUYSIDE, func(p *PriceLevel, levelIndex int) {
+				levelIndexes = append(levelIndexes, levelIndex)
+				if p.Price == 990 {
+					p.Orders = p.Orders[:0]
+				}
+			})
+			require.Equal(t, []int{0, 1, 2}, levelIndexes)
+			require.Nil(t, book.GetPriceLevel(990, BUYSIDE), "emptied price level is removed")
+
+			var removed []string
+			require.NoError(t, book.RemoveOrdersBasedOnPriceLevel(101, 100, 1, BUYSIDE, func(ord OrderPart) {
+				removed = append(removed, ord.Id)
+			}))
+			require.Equal(t, []string{"b4"}, removed, "only the orders beyond the reserved levels are expired")
+			require.NoError(t, book.RemoveOrders(102, BUYSIDE, func(ord OrderPart) {
+				removed = append(removed, ord.Id)
+			}))
+			require.Equal(t, []string{"b4", "b1", "b2"}, removed)
+			buys, sells = book.GetAllLevels()
+			require.Len(t, buys, 0)
+			require.Len(t, sells, 0)
+
+			book.InsertOrder("b5", BUYSIDE, 103, 1000, 10)
+			book.Clear()
+			buys, sells = book.GetAllLevels()
+			require.Len(t, buys, 0)
+			require.Len(t, sells, 0)
+		})
+	}
+}
+
+// TestOrderBook_RandomOperations applies the same random operations on all the implementations
+// and requires identical results
+func TestOrderBook_RandomOperations(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	books := make([]OrderBookInterface, len(orderBookImpls))
+	for i, impl := range orderBookImpls {
+		books[i] = impl.newBook()
+	}
+	type placed struct {
+		side  int8
+		price int64
+	}
+	orders := make(map[string]placed)
+	var ids []string
+	for n := 0; n < 5000; n++ {
+		side := int8(r.Intn(2) + 1)
+		price := int64(r.Intn(200)+900) * 10
+		results := make([]string, len(books))
+		switch op := r.Intn(10); {
+		case op < 5:
+			id, qty := fmt.Sprintf("%d", n), int64(r.Intn(10)+1)
+			for i, book := range books {
+				_, err := book.InsertOrder(id, side, int64(n/50), price, qty)
+				results[i] = fmt.Sprint(err)
+			}
+			orders[id] = placed{side, price}
+			ids = append(ids, id)
+		case op < 8 && len(ids) > 0:
+			id := ids[r.Intn(len(ids))]
+			ord := orders[id]
+			for i, book := range books {
+				removed, err := book.RemoveOrder(id, ord.side, ord.price)
+				results[i] = fmt.Sprint(removed, err)
+			}
+		case op < 9:
+			for i, book := range books {
+				var removed []string
+				err := book.RemoveOrdersBasedOnPriceLevel(int64(n/50-10), int64(n/50-40), 5, side, func(ord OrderPart) {
+					removed = append(removed, ord.Id)
+				})
+				results[i] = fmt.Sprint(removed, err)
+			}
+		default:
+			for i, book := range books {
+				results[i] = fmt.Sprint(book.RemovePriceLevel(price, side))
+			}
+		}
+		for i := 1; i < len(books); i++ {
+			require.Equal(t, results[0], results[i], "operation %d", n)
+		}
+	}
+	buys, sells := books[0].GetAllLevels()
+	require.NotEmpty(t, buys)
+	require.NotEmpty(t, sells)
+	for i := 1; i < len(books); i++ {
+		b, s := books[i].GetAllLevels()
+		require.Equal(t, buys, b)
+		require.Equal(t, sells, s)
+	}
+}
+
+type randomOrder struct {
+	id    string
+	side  int8
+	time  int64
+	price int64
+	qty   int64
+}
+
+// newRandomOrders generates the orders of a deep and sparse order book around the price of 1e8
+func newRandomOrders(r *rand.Rand, n int, height int64) []randomOrder {
+	orders := make([]randomOrder, n)
+	for i := range orders {
+		orders[i] = randomOrder{
+			id:    fmt.Sprintf("%d-%d", height, i),
+			side:  int8(r.Intn(2) + 1),
+			time:  height,
+			price: 1e8 + int64(r.Intn(200000)-100000)*1e3,
+			qty:   int64(r.Intn(100)+1) * 1e6,
+		}
+	}
+	return orders
+}
+
+func insertRandomOrders(book OrderBookInterface, orders []randomOrder) {
+	for _, o := range orders {
+		book.InsertOrder(o.id, o.side, o.time, o.price, o.qty)
+	}
+}
+
+func TestMatchEng_OrderBookImplementations(t *testing.T) {
+	upgrade.Mgr.AddUpgradeHeight(upgrade.BEP19, 1)
+	upgrade.Mgr.SetHeight(100)
+
+	engines := make([]*MatchEng, len(orderBookImpls))
+	for i, impl := range orderBookImpls {
+		engines[i] = NewMatchEng(DefaultPairSymbol, 1e8, 1e6, 0.05)
+		engines[i].Book = impl.newBook()
+	}
+	r := rand.New(rand.NewSource(11))
+	for height := int64(100); height < 120; height++ {
+		orders := newRandomOrders(r, 500, height)
+		for _, eng := range engines {
+			insertRandomOrders(eng.Book, orders)
+		}
+		results := make([]string, len(engines))
+		for i, eng := range engines {
+			ok := eng.Match(height)
+			results[i] = fmt.Sprint(ok, eng.LastTradePrice, eng.Trades, eng.DropFilledOrder())
+		}
+		require.NotEmpty(t, engines[0].Trades)
+		for i := 1; i < len(engines); i++ {
+			require.Equal(t, results[0], results[i], "match at height %d", height)
+		}
+	}
+	buys, sells := engines[0].Book.GetAllLevels()
+	for i := 1; i < len(engines); i++ {
+		b, s := engines[i].Book.GetAllLevels()
+		require.Equal(t, buys, b)
+		require.Equal(t, sells, s)
+	}
+}
+
+func BenchmarkOrderBook_InsertOrder(b *testing.B) {
+	for _, impl := range orderBookImpls {
+		b.Run(impl.name, func(b *testing.B) {
+			orders := newRandomOrders(rand.New(rand.NewSource(1)), 20000, 1)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				book := impl.newBook()
+				insertRandomOrders(book, orders)
+			}
+		})
+	}
+}
+
+func BenchmarkOrderBook_RemoveOrder(b *testing.B) {
+	for _, impl := range orderBookImpls {
+		b.Run(impl.name, func(b *testing.B) {
+			orders := newRandomOrders(rand.New(rand.NewSource(1)), 20000, 1)
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				book := impl.newBook()
+				insertRandomOrders(book, orders)
+				b.StartTimer()
+				for _, o := range orders {
+					book.RemoveOrder(o.id, o.side, o.price)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkMatchEng_Match(b *testing.B) {
+	upgrade.Mgr.AddUpgradeHeight(upgrade.BEP19, 1)
+	upgrade.Mgr.SetHeight(100)
+	for _, impl := range orderBookImpls {
+		b.Run(impl.name, func(b *testing.B) {
+			r := rand.New(rand.NewSource(1))
+			eng := NewMatchEng(DefaultPairSymbol, 1e8, 1e6, 0.05)
+			eng.Book = impl.newBook()
+			insertRandomOrders(eng.Book, newRandomOrders(r, 20000, 1))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				height := int64(100 + i)
+				insertRandomOrders(eng.Book, newRandomOrders(r, 100, height))
+				b.StartTimer()
+				eng.Match(height)
+				eng.DropFilledOrder()
+			}
+		})
+	}
+}
@@ -3,6 +3,8 @@ package matcheng
 import (
 	"fmt"
 	"sort"
+	"strconv"
+	"strings"
 
 	bt "github.com/google/btree"
 
@@ -61,6 +63,67 @@ func (o *OrderPart) LeavesQty() int64 {
 	}
 }
 
+// sequence returns the account sequence encoded in the order id, or -1 if the id is not in the format.
+func (o *OrderPart) sequence() int64 {
+	idx := strings.LastIndex(o.Id, "-")
+	if idx <= 0 {
+		return -1
+	}
+	seq, err := strconv.ParseInt(o.Id[idx+1:], 10, 64)
+	if err != nil {
+		return -1
+	}
+	return seq
+}
+
+// OrderSenderFunc returns the sender address of the order of the id, or an empty string if the order is unknown
+type OrderSenderFunc func(id string) string
+
+// SelfTradePrevention decides what happens to the orders of the same sender which would be executed against each other
+type SelfTradePrevention int8
+
+const (
+	STPNone         SelfTradePrevention = iota // orders of the same sender can trade with each other
+	STPCancelNewest                            // the newer order is canceled
+	STPCancelOldest                            // the older order is canceled
+	STPCancelBoth                              // both orders are canceled
+	STPDecrement                               // both orders are reduced by the smaller quantity, which cancels the smaller one
+)
+
+var selfTradePreventionNames = map[string]SelfTradePrevention{
+	"":              STPNone,
+	"none":          STPNone,
+	"cancel_newest": STPCancelNewest,
+	"cancel_oldest": STPCancelOldest,
+	"cancel_both":   STPCancelBoth,
+	"decrement":     STPDecrement,
+}
+
+// ParseSelfTradePrevention converts a name like "cancel_newest" to its SelfTradePrevention
+func ParseSelfTradePrevention(name string) (SelfTradePrevention, error) {
+	if stp, ok := selfTradePreventionNames[strings.ToLower(name)]; ok {
+		return stp, nil
+	}
+	return STPNone, fmt.Errorf("self-trade prevention `%s` not found or supported", name)
+}
+
+func (stp SelfTradePrevention) String() string {
+	switch stp {
+	case STPNone:
+		return "none"
+	case STPCancelNewest:
+		return "cancel_newest"
+	case STPCancelOldest:
+		return "cancel_oldest"
+	case STPCancelBoth:
+		return "cancel_both"
+	case STPDecrement:
+		return "decrement"
+	default:
+		return "unknown"
+	}
+}
+
 type PriceLevelInterface interface {
 	addOrder(id string, time int64, qty int64) (int, error)
 	removeOrder(id string) (OrderPart, int, error)
@@ -174,6 +237,13 @@ type OverLappedLevel struct {
 	SellMakerTotal    int64
 }
 
+// IndicativeMatch is where the auction would conclude if the order book was matched now.
+type IndicativeMatch struct {
+	Price   int64
+	ExecQty int64
+	Surplus int64 // the quantity left at the price, of the buy side if positive, or of the sell side if negative
+}
+
 func (overlapped *OverLappedLevel) HasBuyMaker() bool {
 	return overlapped.BuyTakerStartIdx > 0 && overlapped.BuyMakerTotal > 0
 }
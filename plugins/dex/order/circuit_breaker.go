@@ -0,0 +1,259 @@
+package order
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/common/upgrade"
+	dexTypes "github.com/bnb-chain/node/plugins/dex/types"
+)
+
+const circuitBreakerTripKeyPrefix = "circuitbreaker_"
+
+// CircuitBreakerConfig is the thresholds of the volatility circuit breaker, which are governed by the
+// CircuitBreakerParam. The circuit breaker is disabled if any of them is not set.
+type CircuitBreakerConfig struct {
+	Band       int64 // the max move of the concluded price against the recent prices, in the precision of the fee rate
+	Window     int64 // the number of the latest recent prices, stored every pricesStoreEvery blocks, to check against
+	HaltBlocks int64 // the number of blocks the matching of the pair is suspended for
+}
+
+func (config CircuitBreakerConfig) enabled() bool {
+	return config.Band > 0 && config.Window > 0 && config.HaltBlocks > 0
+}
+
+// CircuitBreakerTrip is a suspension of the matching of a pair. The matching is suspended in the blocks
+// after TripHeight, and is resumed by a call auction of the accumulated orders at ResumeHeight.
+// The trips are saved in the store, so that the suspensions are replayed when the order book is recovered.
+type CircuitBreakerTrip struct {
+	Symbol       string `json:"symbol"`
+	TripHeight   int64  `json:"trip_height"`
+	ResumeHeight int64  `json:"resume_height"`
+	TripPrice    int64  `json:"trip_price"`
+	ResumePrice  int64  `json:"resume_price"` // the last trade price after the call auction, 0 before the resume
+}
+
+func genCircuitBreakerTripKey(symbol string, tripHeight int64) string {
+	return fmt.Sprintf("%s%s_%020d", circuitBreakerTripKeyPrefix, symbol, tripHeight)
+}
+
+func (kp *DexKeeper) SetCircuitBreakerConfig(config CircuitBreakerConfig) {
+	kp.circuitBreaker = config
+}
+
+func (kp *DexKeeper) GetCircuitBreakerConfig() CircuitBreakerConfig {
+	return kp.circuitBreaker
+}
+
+// GetCircuitBreakerTrip returns the latest trip of the pair before the height, nil if there is none
+func (kp *DexKeeper) GetCircuitBreakerTrip(symbol string, height int64) *CircuitBreakerTrip {
+	trips := kp.circuitBreakerTrips[strings.ToUpper(symbol)]
+	for i := len(trips) - 1; i >= 0; i-- {
+		if trips[i].TripHeight < height {
+			return trips[i]
+		}
+	}
+	return nil
+}
+
+// IsMatchSuspended returns whether the matching of the pair is suspended by the circuit breaker at the height
+func (kp *DexKeeper) IsMatchSuspended(symbol string, height int64) bool {
+	trip := kp.GetCircuitBreakerTrip(symbol, height)
+	return trip != nil && height < trip.ResumeHeight
+}
+
+func (kp *DexKeeper) isMatchResumed(symbol string, height int64) bool {
+	trip := kp.GetCircuitBreakerTrip(symbol, height)
+	return trip != nil && height == trip.ResumeHeight
+}
+
+// tripSymbols returns the pairs with circuit breaker trips in order, so that they are processed in the same order on all the nodes
+func (kp *DexKeeper) tripSymbols() []string {
+	symbols := make([]string, 0, len(kp.circuitBreakerTrips))
+	for symbol := range kp.circuitBreakerTrips {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+// selectResumedSymbols appends the pairs resumed at the height, as the accumulated orders
+// are matched in a call auction even if no order comes in this block.
+func (kp *DexKeeper) selectResumedSymbols(symbolsToMatch []string, height int64) []string {
+	for _, symbol := range kp.tripSymbols() {
+		if !kp.isMatchResumed(symbol, height) {
+			continue
+		}
+		selected := false
+		for _, s := range symbolsToMatch {
+			if s == symbol {
+				selected = true
+				break
+			}
+		}
+		if !selected {
+			symbolsToMatch = append(symbolsToMatch, symbol)
+		}
+	}
+	return symbolsToMatch
+}
+
+// checkCircuitBreakers runs after the match of the block. It records the prices of the pairs resumed in this block,
+// and suspends the pairs whose concluded prices move out of the band against the recent prices.
+func (kp *DexKeeper) checkCircuitBreakers(ctx sdk.Context, matchedSymbols []string) {
+	if !sdk.IsUpgrade(upgrade.DexEnhancements) {
+		return
+	}
+	height := ctx.BlockHeader().Height
+	for _, symbol := range kp.tripSymbols() {
+		trips := kp.circuitBreakerTrips[symbol]
+		trip := trips[len(trips)-1]
+		if trip.ResumeHeight != height {
+			continue
+		}
+		trip.ResumePrice = kp.engines[symbol].LastTradePrice
+		kp.saveCircuitBreakerTrip(ctx, trip)
+		kp.logger.Info("Resumed matching of trading pair", "symbol", symbol, "price", trip.ResumePrice)
+		if kp.PbsbServer != nil {
+			kp.PbsbServer.Publish(dexTypes.CircuitBreakerEvent{Symbol: symbol})
+		}
+	}
+
+	if !kp.circuitBreaker.enabled() {
+		return
+	}
+	for _, symbol := range matchedSymbols {
+		// the call auction resuming the matching is not limited by the band
+		if kp.IsMatchSuspended(symbol, height) || kp.isMatchResumed(symbol, height) {
+			continue
+		}
+		engine := kp.engines[symbol]
+		if len(engine.Trades) == 0 {
+			continue
+		}
+		if !kp.exceedsCircuitBreakerBand(symbol, engine.LastTradePrice, height) {
+			continue
+		}
+		trip := &CircuitBreakerTrip{
+			Symbol:       symbol,
+			TripHeight:   height,
+			ResumeHeight: height + kp.circuitBreaker.HaltBlocks + 1,
+			TripPrice:    engine.LastTradePrice,
+		}
+		kp.circuitBreakerTrips[symbol] = append(kp.circuitBreakerTrips[symbol], trip)
+		kp.saveCircuitBreakerTrip(ctx, trip)
+		kp.logger.Info("Suspended matching of trading pair", "symbol", symbol, "price", trip.TripPrice,
+			"resumeHeight", trip.ResumeHeight)
+		if kp.PbsbServer != nil {
+			kp.PbsbServer.Publish(dexTypes.CircuitBreakerEvent{
+				Symbol:       symbol,
+				Suspended:    true,
+				ResumeHeight: trip.ResumeHeight,
+			})
+		}
+	}
+}
+
+// exceedsCircuitBreakerBand checks the price against the latest "Window" recent prices of the pair.
+// The recent prices stored before the last resume are replaced by the price concluded in the call auction,
+// otherwise the pair would be suspended again by the move it has already been suspended for.
+func (kp *DexKeeper) exceedsCircuitBreakerBand(symbol string, price int64, height int64) bool {
+	window := kp.circuitBreaker.Window
+	references := make([]int64, 0, window+1)
+	if trip := kp.GetCircuitBreakerTrip(symbol, height); trip != nil {
+		// the recent prices are stored after the match of the block
+		storedSinceResume := (height-1)/pricesStoreEvery - (trip.ResumeHeight-1)/pricesStoreEvery
+		if storedSinceResume < window {
+			window = storedSinceResume
+			references = append(references, trip.ResumePrice)
+		}
+	}
+	if prices, ok := kp.recentPrices[symbol]; ok {
+		elements := prices.Elements()
+		if int64(len(elements)) < window {
+			window = int64(len(elements))
+		}
+		for _, p := range elements[int64(len(elements))-window:] {
+			references = append(references, p.(int64))
+		}
+	}
+
+	var diff, limit big.Int
+	for _, reference := range references {
+		if reference <= 0 {
+			continue
+		}
+		diff.Mul(big.NewInt(price-reference), FeeRateMultiplier)
+		diff.Abs(&diff)
+		limit.Mul(big.NewInt(reference), big.NewInt(kp.circuitBreaker.Band))
+		if diff.Cmp(&limit) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (kp *DexKeeper) saveCircuitBreakerTrip(ctx sdk.Context, trip *CircuitBreakerTrip) {
+	store := ctx.KVStore(kp.storeKey)
+	bz := kp.cdc.MustMarshalBinaryBare(*trip)
+	store.Set([]byte(genCircuitBreakerTripKey(trip.Symbol, trip.TripHeight)), bz)
+}
+
+func (kp *DexKeeper) deleteCircuitBreakerTrips(ctx sdk.Context, symbol string) {
+	store := ctx.KVStore(kp.storeKey)
+	for _, trip := range kp.circuitBreakerTrips[symbol] {
+		store.Delete([]byte(genCircuitBreakerTripKey(symbol, trip.TripHeight)))
+	}
+	delete(kp.circuitBreakerTrips, symbol)
+}
+
+// copyCircuitBreakerTrips returns a deep copy of the trips, for a keeper replaying the blocks apart from the live one
+func copyCircuitBreakerTrips(trips map[string][]*CircuitBreakerTrip) map[string][]*CircuitBreakerTrip {
+	copied := make(map[string][]*CircuitBreakerTrip, len(trips))
+	for symbol, symbolTrips := range trips {
+		copiedTrips := make([]*CircuitBreakerTrip, len(symbolTrips))
+		for i, trip := range symbolTrips {
+			copiedTrip := *trip
+			copiedTrips[i] = &copiedTrip
+		}
+		copied[symbol] = copiedTrips
+	}
+	return copied
+}
+
+// initCircuitBreakerTrips loads the trips, which must be done before the order book is recovered
+func (kp *DexKeeper) initCircuitBreakerTrips(ctx sdk.Context) {
+	kp.circuitBreakerTrips = make(map[string][]*CircuitBreakerTrip)
+	iter := sdk.KVStorePrefixIterator(ctx.KVStore(kp.storeKey), []byte(circuitBreakerTripKeyPrefix))
+	defer iter.Close()
+	// the keys of a pair are ordered by the trip height
+	for ; iter.Valid(); iter.Next() {
+		var trip CircuitBreakerTrip
+		kp.cdc.MustUnmarshalBinaryBare(iter.Value(), &trip)
+		kp.circuitBreakerTrips[trip.Symbol] = append(kp.circuitBreakerTrips[trip.Symbol], &trip)
+	}
+}
+
+// PruneCircuitBreakerTrips deletes the trips no longer needed by the order book recovery from the breathe block,
+// nor by the band check, that is all the trips but the last one of a pair, which is deleted as well
+// if the pair has resumed and stored enough recent prices since then.
+func (kp *DexKeeper) PruneCircuitBreakerTrips(ctx sdk.Context, height int64) {
+	store := ctx.KVStore(kp.storeKey)
+	for symbol, trips := range kp.circuitBreakerTrips {
+		last := trips[len(trips)-1]
+		for _, trip := range trips[:len(trips)-1] {
+			store.Delete([]byte(genCircuitBreakerTripKey(symbol, trip.TripHeight)))
+		}
+		storedSinceResume := height/pricesStoreEvery - (last.ResumeHeight-1)/pricesStoreEvery
+		if last.ResumeHeight <= height && storedSinceResume >= kp.circuitBreaker.Window {
+			store.Delete([]byte(genCircuitBreakerTripKey(symbol, last.TripHeight)))
+			delete(kp.circuitBreakerTrips, symbol)
+		} else {
+			kp.circuitBreakerTrips[symbol] = []*CircuitBreakerTrip{last}
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package order
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	param "github.com/cosmos/cosmos-sdk/x/paramHub/types"
+
+	me "github.com/bnb-chain/node/plugins/dex/matcheng"
+)
+
+const (
+	SelfTradePreventionParamType = "SelfTradePrevention"
+	CircuitBreakerParamType      = "CircuitBreaker"
+	OrderLimitsParamType         = "OrderLimits"
+
+	dexParamKeyPrefix = "dexparam_"
+)
+
+// SelfTradePreventionParam is the self-trade prevention policy of all the match engines
+type SelfTradePreventionParam struct {
+	Policy string `json:"policy"`
+}
+
+var _ param.FeeParam = (*SelfTradePreventionParam)(nil)
+
+func (p *SelfTradePreventionParam) GetParamType() string {
+	return SelfTradePreventionParamType
+}
+
+func (p *SelfTradePreventionParam) Check() error {
+	_, err := me.ParseSelfTradePrevention(p.Policy)
+	return err
+}
+
+// CircuitBreakerParam is the thresholds of the volatility circuit breaker, see CircuitBreakerConfig
+type CircuitBreakerParam struct {
+	Band       int64 `json:"band"`
+	Window     int64 `json:"window"`
+	HaltBlocks int64 `json:"halt_blocks"`
+}
+
+var _ param.FeeParam = (*CircuitBreakerParam)(nil)
+
+func (p *CircuitBreakerParam) GetParamType() string {
+	return CircuitBreakerParamType
+}
+
+func (p *CircuitBreakerParam) Check() error {
+	if p.Band < 0 || p.Window < 0 || p.HaltBlocks < 0 {
+		return fmt.Errorf("circuit breaker band, window and halt blocks should not be less than 0")
+	}
+	if p.Window > numPricesStored {
+		return fmt.Errorf("circuit breaker window should not be larger than %d", numPricesStored)
+	}
+	return nil
+}
+
+// OrderLimitsParam is the limits of the orders an account can place, see OrderLimitsConfig
+type OrderLimitsParam struct {
+	MaxOpenOrdersPerPair int64 `json:"max_open_orders_per_pair"`
+	MaxOpenOrders        int64 `json:"max_open_orders"`
+	MaxNewOrdersPerBlock int64 `json:"max_new_orders_per_block"`
+}
+
+var _ param.FeeParam = (*OrderLimitsParam)(nil)
+
+func (p *OrderLimitsParam) GetParamType() string {
+	return OrderLimitsParamType
+}
+
+func (p *OrderLimitsParam) Check() error {
+	if p.MaxOpenOrdersPerPair < 0 || p.MaxOpenOrders < 0 || p.MaxNewOrdersPerBlock < 0 {
+		return fmt.Errorf("order limits should not be less than 0")
+	}
+	return nil
+}
+
+// newDexParams returns an empty param of each type governed by the dex. The dex params are proposed along
+// with the fee params, but the param hub only keeps the msg fees and the dex fee of an update, so the dex
+// keeps the latest value of each of them in its own store.
+func newDexParams() []param.FeeParam {
+	return []param.FeeParam{
+		&SelfTradePreventionParam{},
+		&CircuitBreakerParam{},
+		&OrderLimitsParam{},
+	}
+}
+
+// IsDexParam returns whether the param is governed by the dex rather than the param hub
+func IsDexParam(p param.FeeParam) bool {
+	for _, dexParam := range newDexParams() {
+		if p.GetParamType() == dexParam.GetParamType() {
+			return true
+		}
+	}
+	return false
+}
+
+func genDexParamKey(paramType string) []byte {
+	return []byte(dexParamKeyPrefix + paramType)
+}
+
+// updateDexParams applies the dex params in feeParams and saves them in the store
+func (kp *DexKeeper) updateDexParams(ctx sdk.Context, feeParams []param.FeeParam) {
+	store := ctx.KVStore(kp.storeKey)
+	for _, p := range feeParams {
+		if !IsDexParam(p) {
+			continue
+		}
+		if err := kp.applyDexParam(p); err != nil {
+			kp.logger.Error("failed to apply the dex param", "param", p, "err", err)
+			continue
+		}
+		store.Set(genDexParamKey(p.GetParamType()), kp.cdc.MustMarshalBinaryBare(p))
+	}
+}
+
+// loadDexParams applies the dex params saved in the store
+func (kp *DexKeeper) loadDexParams(ctx sdk.Context) {
+	store := ctx.KVStore(kp.storeKey)
+	for _, p := range newDexParams() {
+		bz := store.Get(genDexParamKey(p.GetParamType()))
+		if bz == nil {
+			continue
+		}
+		kp.cdc.MustUnmarshalBinaryBare(bz, p)
+		if err := kp.applyDexParam(p); err != nil {
+			panic(fmt.Errorf("failed to load the dex param %s: %v", p.GetParamType(), err))
+		}
+	}
+}
+
+func (kp *DexKeeper) applyDexParam(p param.FeeParam) error {
+	switch p := p.(type) {
+	case *SelfTradePreventionParam:
+		stp, err := me.ParseSelfTradePrevention(p.Policy)
+		if err != nil {
+			return err
+		}
+		kp.SetSelfTradePrevention(stp)
+	case *CircuitBreakerParam:
+		if err := p.Check(); err != nil {
+			return err
+		}
+		kp.SetCircuitBreakerConfig(CircuitBreakerConfig{Band: p.Band, Window: p.Window, HaltBlocks: p.HaltBlocks})
+	case *OrderLimitsParam:
+		if err := p.Check(); err != nil {
+			return err
+		}
+		kp.SetOrderLimitsConfig(OrderLimitsConfig{
+			MaxOpenOrdersPerPair: p.MaxOpenOrdersPerPair,
+			MaxOpenOrders:        p.MaxOpenOrders,
+			MaxNewOrdersPerBlock: p.MaxNewOrdersPerBlock,
+		})
+	default:
+		return fmt.Errorf("unknown dex param type %s", p.GetParamType())
+	}
+	return nil
+}
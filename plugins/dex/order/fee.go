@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"strconv"
+	"strings"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	param "github.com/cosmos/cosmos-sdk/x/paramHub/types"
@@ -36,6 +38,20 @@ const (
 	FeeRateNativeField   = "FeeRateNative"
 	IOCExpireFee         = "IOCExpireFee"
 	IOCExpireFeeNative   = "IOCExpireFeeNative"
+	AmendFeeField        = "AmendFee"
+	AmendFeeNativeField  = "AmendFeeNative"
+	MakerRebateRateField = "MakerRebateRate"
+
+	// the fields of a fee tier are named as the prefix, the index of the tier and one of the suffixes, e.g. FeeTier1Volume
+	FeeTierFieldPrefix           = "FeeTier"
+	FeeTierVolumeSuffix          = "Volume"
+	FeeTierMakerRateSuffix       = "MakerRate"
+	FeeTierMakerRateNativeSuffix = "MakerRateNative"
+	FeeTierTakerRateSuffix       = "TakerRate"
+	FeeTierTakerRateNativeSuffix = "TakerRateNative"
+
+	feeTierCharacter = "#Tier"
+	rebateCharacter  = "#Rebate"
 )
 
 var (
@@ -69,6 +85,62 @@ func (m *FeeManager) GetConfig() FeeConfig {
 	return m.FeeConfig
 }
 
+// HasFeeTiers returns true if the fee tiers are configured and the DexEnhancements upgrade is active
+func (m *FeeManager) HasFeeTiers() bool {
+	return len(m.FeeConfig.FeeTiers) > 0 && sdk.IsUpgrade(upgrade.DexEnhancements)
+}
+
+// FeeTier returns the index of the highest fee tier reached by the trailing volume of an account
+func (m *FeeManager) FeeTier(volume int64) int {
+	tier := 0
+	for i, t := range m.FeeConfig.FeeTiers {
+		if volume >= t.MinVolume {
+			tier = i
+		}
+	}
+	return tier
+}
+
+// SerializeTradeFeeForPub serializes the fee of a trade for publication, along with the fee tier applied
+// if the fee tiers are configured and the rebate paid to the maker, e.g. "BNB:100;#Tier:1;#Rebate:BNB:50"
+func (m *FeeManager) SerializeTradeFeeForPub(fee sdk.Fee, tier int, rebate sdk.Coins) string {
+	res := fee.String()
+	if res != "" && m.HasFeeTiers() {
+		res += fmt.Sprintf(";%s:%d", feeTierCharacter, tier)
+	}
+	return AppendRebatesForPub(res, rebate)
+}
+
+// AppendRebatesForPub appends the rebates paid to the makers to the serialized fee, e.g. "BNB:100;#Rebate:BNB:50"
+func AppendRebatesForPub(fee string, rebates sdk.Coins) string {
+	var buffer strings.Builder
+	buffer.WriteString(fee)
+	for _, coin := range rebates {
+		if buffer.Len() > 0 {
+			buffer.WriteString(";")
+		}
+		buffer.WriteString(fmt.Sprintf("%s:%s:%d", rebateCharacter, coin.Denom, coin.Amount))
+	}
+	return buffer.String()
+}
+
+// HasMakerRebate returns true if the maker rebate rate is set after the DexEnhancements upgrade
+func (m *FeeManager) HasMakerRebate() bool {
+	return m.FeeConfig.MakerRebateRate > 0 && sdk.IsUpgrade(upgrade.DexEnhancements)
+}
+
+// CalcMakerRebate returns the share of the taker fee of a trade paid to the maker, which never exceeds the taker fee
+func (m *FeeManager) CalcMakerRebate(takerFee sdk.Fee) sdk.Coins {
+	var rebate sdk.Coins
+	for _, coin := range takerFee.Tokens {
+		amount := tradeFeeByRate(big.NewInt(coin.Amount), m.FeeConfig.MakerRebateRate).Int64()
+		if amount > 0 {
+			rebate = append(rebate, sdk.NewCoin(coin.Denom, amount))
+		}
+	}
+	return rebate
+}
+
 func (m *FeeManager) CalcTradesFee(balances sdk.Coins, tradeTransfers TradeTransfers, engines map[string]*matcheng.MatchEng) sdk.Fee {
 	var fees sdk.Fee
 	if tradeTransfers == nil {
@@ -89,14 +161,16 @@ func (m *FeeManager) CalcTradesFee(balances sdk.Coins, tradeTransfers TradeTrans
 	return fees
 }
 
-func (m *FeeManager) CalcExpiresFee(balances sdk.Coins, expireType transferEventType, expireTransfers ExpireTransfers, engines map[string]*matcheng.MatchEng, expireTransferHandler func(tran Transfer)) sdk.Fee {
+// CalcExpiresFee calculates the fee of each expired order by the event type of its own transfer,
+// since the expiries of different time in force can be allocated together.
+func (m *FeeManager) CalcExpiresFee(balances sdk.Coins, expireTransfers ExpireTransfers, engines map[string]*matcheng.MatchEng, expireTransferHandler func(tran Transfer)) sdk.Fee {
 	var fees sdk.Fee
 	if expireTransfers == nil {
 		return fees
 	}
 	expireTransfers.Sort()
 	for _, tran := range expireTransfers {
-		fee := m.CalcFixedFee(balances, expireType, tran.inAsset, engines)
+		fee := m.CalcFixedFee(balances, tran.eventType, tran.inAsset, engines)
 		tran.Fee = fee
 		if expireTransferHandler != nil {
 			expireTransferHandler(*tran)
@@ -107,10 +181,24 @@ func (m *FeeManager) CalcExpiresFee(balances sdk.Coins, expireType transferEvent
 	return fees
 }
 
+// tradeFeeRates returns the fee rates by the native token and by the trade token of the transfer. If the fee tiers
+// are configured, the rates depend on the fee tier of the account and whether the order is the maker of the trade.
+func (m *FeeManager) tradeFeeRates(tran *Transfer) (rateNative, rate int64) {
+	if !m.HasFeeTiers() {
+		return m.FeeConfig.FeeRateNative, m.FeeConfig.FeeRate
+	}
+	tier := m.FeeConfig.FeeTiers[tran.feeTier]
+	if tran.isMaker() {
+		return tier.MakerRateNative, tier.MakerRate
+	}
+	return tier.TakerRateNative, tier.TakerRate
+}
+
 func (m *FeeManager) calcTradeFeeFromTransfer(balances sdk.Coins, tran *Transfer, engines map[string]*matcheng.MatchEng) sdk.Fee {
 	var feeToken sdk.Coin
 
-	nativeFee, isOverflow := m.calcNativeFee(tran, engines)
+	rateNative, rate := m.tradeFeeRates(tran)
+	nativeFee, isOverflow := m.calcNativeFee(tran, engines, rateNative)
 	if tran.IsNativeIn() {
 		// special case, in this case, we always have
 		// 1. the fee is paid by native token
@@ -122,7 +210,7 @@ func (m *FeeManager) calcTradeFeeFromTransfer(balances sdk.Coins, tran *Transfer
 	if isOverflow || nativeFee == 0 || nativeFee > balances.AmountOf(types.NativeTokenSymbol) {
 		// 1. if the fee is too low and round to 0, we charge by inAsset
 		// 2. no enough NativeToken, use the received tokens as fee
-		feeToken = sdk.NewCoin(tran.inAsset, m.TradeFee(big.NewInt(tran.in), FeeByTradeToken).Int64())
+		feeToken = sdk.NewCoin(tran.inAsset, tradeFeeByRate(big.NewInt(tran.in), rate).Int64())
 		m.logger.Debug("No enough native token to pay trade fee", "feeToken", feeToken)
 	} else {
 		// have sufficient native token to pay the fees
@@ -131,12 +219,20 @@ func (m *FeeManager) calcTradeFeeFromTransfer(balances sdk.Coins, tran *Transfer
 	return dexFeeWrap(feeToken)
 }
 
-func (m *FeeManager) calcNativeFee(tran *Transfer, engines map[string]*matcheng.MatchEng) (fee int64, isOverflow bool) {
-	var nativeFee *big.Int
+func (m *FeeManager) calcNativeFee(tran *Transfer, engines map[string]*matcheng.MatchEng, rateNative int64) (fee int64, isOverflow bool) {
+	nativeFee := tradeFeeByRate(m.calcNativeNotional(tran, engines), rateNative)
+	if nativeFee.IsInt64() {
+		return nativeFee.Int64(), false
+	}
+	return 0, true
+}
+
+// calcNativeNotional returns the notional of the transfer in the native token, which is 0 if it cannot be priced
+func (m *FeeManager) calcNativeNotional(tran *Transfer, engines map[string]*matcheng.MatchEng) *big.Int {
 	if tran.IsNativeIn() {
-		nativeFee = m.TradeFee(big.NewInt(tran.in), FeeByNativeToken)
+		return big.NewInt(tran.in)
 	} else if tran.IsNativeOut() {
-		nativeFee = m.TradeFee(big.NewInt(tran.out), FeeByNativeToken)
+		return big.NewInt(tran.out)
 	} else {
 		// pair pattern: ABC_XYZ/XYZ_ABC, inAsset: ABC
 		// must exist ABC/BNB. or ABC/BUSD after upgrade
@@ -159,12 +255,24 @@ func (m *FeeManager) calcNativeFee(tran *Transfer, engines map[string]*matcheng.
 				}
 			}
 		}
-		nativeFee = m.TradeFee(notional, FeeByNativeToken)
+		if notional == nil {
+			return big.NewInt(0)
+		}
+		return notional
 	}
-	if nativeFee.IsInt64() {
-		return nativeFee.Int64(), false
+}
+
+// CalcTradesVolume returns the notional of the trades in the native token, which is added to the trailing volume
+// of the account
+func (m *FeeManager) CalcTradesVolume(tradeTransfers TradeTransfers, engines map[string]*matcheng.MatchEng) int64 {
+	var volume big.Int
+	for _, tran := range tradeTransfers {
+		volume.Add(&volume, m.calcNativeNotional(tran, engines))
 	}
-	return 0, true
+	if !volume.IsInt64() {
+		return math.MaxInt64
+	}
+	return volume.Int64()
 }
 
 func (m *FeeManager) calcNotional(asset string, qty int64, quoteAsset string, engines map[string]*matcheng.MatchEng) (notional *big.Int, engineFound bool) {
@@ -241,8 +349,12 @@ func (m *FeeManager) CalcFixedFee(balances sdk.Coins, eventType transferEventTyp
 		feeAmountNative, feeAmount = m.ExpireFees()
 	} else if eventType == eventIOCFullyExpire {
 		feeAmountNative, feeAmount = m.IOCExpireFees()
+	} else if eventType == eventFOKExpire {
+		feeAmountNative, feeAmount = m.FOKExpireFees()
 	} else if eventType == eventFullyCancel {
 		feeAmountNative, feeAmount = m.CancelFees()
+	} else if eventType == eventAmend {
+		feeAmountNative, feeAmount = m.AmendFees()
 	} else {
 		// should not be here
 		m.logger.Error("Invalid expire eventType", "eventType", eventType)
@@ -304,10 +416,20 @@ func (m *FeeManager) IOCExpireFees() (int64, int64) {
 	return m.FeeConfig.IOCExpireFeeNative, m.FeeConfig.IOCExpireFee
 }
 
+// FOKExpireFees returns the fees of a killed FOK order, which are the same as an IOC order expiring without any fill
+func (m *FeeManager) FOKExpireFees() (int64, int64) {
+	return m.IOCExpireFees()
+}
+
 func (m *FeeManager) CancelFees() (int64, int64) {
 	return m.FeeConfig.CancelFeeNative, m.FeeConfig.CancelFee
 }
 
+// AmendFees returns the fees of amending an order, which replaces a cancel and a new order
+func (m *FeeManager) AmendFees() (int64, int64) {
+	return m.FeeConfig.AmendFeeNative, m.FeeConfig.AmendFee
+}
+
 func (m *FeeManager) TradeFee(amount *big.Int, feeType FeeType) *big.Int {
 	var feeRate int64
 	if feeType == FeeByNativeToken {
@@ -316,6 +438,10 @@ func (m *FeeManager) TradeFee(amount *big.Int, feeType FeeType) *big.Int {
 		feeRate = m.FeeConfig.FeeRate
 	}
 
+	return tradeFeeByRate(amount, feeRate)
+}
+
+func tradeFeeByRate(amount *big.Int, feeRate int64) *big.Int {
 	// TODO: (Perf) find a more efficient way to replace the big.Int solution.
 	var fee big.Int
 	return fee.Div(fee.Mul(amount, big.NewInt(feeRate)), FeeRateMultiplier)
@@ -360,6 +486,23 @@ type FeeConfig struct {
 	CancelFeeNative    int64 `json:"cancel_fee_native"`
 	FeeRate            int64 `json:"fee_rate"`
 	FeeRateNative      int64 `json:"fee_rate_native"`
+	// amend fees are optional and free unless set by a fee change proposal
+	AmendFee       int64 `json:"amend_fee"`
+	AmendFeeNative int64 `json:"amend_fee_native"`
+	// the trade fees are charged by FeeRate and FeeRateNative unless the fee tiers are set by a fee change proposal
+	FeeTiers []FeeTier `json:"fee_tiers"`
+	// the share of the taker fee of a trade paid to the maker, in the same decimals as the fee rates and at most 100%
+	MakerRebateRate int64 `json:"maker_rebate_rate"`
+}
+
+// FeeTier is the trade fee rates of the accounts whose trailing notional volume in the native token reaches MinVolume.
+// The first tier, whose MinVolume is 0, applies to all the accounts.
+type FeeTier struct {
+	MinVolume       int64 `json:"min_volume"`
+	MakerRate       int64 `json:"maker_rate"`
+	MakerRateNative int64 `json:"maker_rate_native"`
+	TakerRate       int64 `json:"taker_rate"`
+	TakerRateNative int64 `json:"taker_rate_native"`
 }
 
 func NewFeeConfig() FeeConfig {
@@ -383,13 +526,99 @@ func (config FeeConfig) anyEmpty() bool {
 		config.CancelFee < 0 ||
 		config.CancelFeeNative < 0 ||
 		config.FeeRate < 0 ||
-		config.FeeRateNative < 0 {
+		config.FeeRateNative < 0 ||
+		config.AmendFee < 0 ||
+		config.AmendFeeNative < 0 ||
+		config.MakerRebateRate < 0 ||
+		config.MakerRebateRate > FeeRateMultiplier.Int64() {
 		return true
 	}
+	for i, tier := range config.FeeTiers {
+		if tier.MakerRate < 0 || tier.MakerRateNative < 0 || tier.TakerRate < 0 || tier.TakerRateNative < 0 {
+			return true
+		}
+		// the volumes of the tiers must be strictly increasing from 0
+		if (i == 0 && tier.MinVolume != 0) || (i > 0 && tier.MinVolume <= config.FeeTiers[i-1].MinVolume) {
+			return true
+		}
+	}
 
 	return false
 }
 
+// parseFeeTierField splits the name of a fee tier field into the index of the tier and the suffix
+func parseFeeTierField(name string) (int, string, bool) {
+	if !strings.HasPrefix(name, FeeTierFieldPrefix) {
+		return 0, "", false
+	}
+	rest := name[len(FeeTierFieldPrefix):]
+	i := 0
+	for i < len(rest) && rest[i] >= '0' && rest[i] <= '9' {
+		i++
+	}
+	index, err := strconv.Atoi(rest[:i])
+	if err != nil {
+		return 0, "", false
+	}
+	return index, rest[i:], true
+}
+
+// paramToFeeTiers builds the fee tiers from the fee tier fields. The tiers must be numbered from 0 without a gap,
+// and a rate not set is inherited from the tier below, or from FeeRate and FeeRateNative for the first tier.
+func paramToFeeTiers(fields []param.DexFeeField, feeRate, feeRateNative int64) []FeeTier {
+	tiers := make(map[int]*FeeTier)
+	for _, d := range fields {
+		index, suffix, ok := parseFeeTierField(d.FeeName)
+		if !ok {
+			continue
+		}
+		tier, ok := tiers[index]
+		if !ok {
+			tier = &FeeTier{MakerRate: nilFeeValue, MakerRateNative: nilFeeValue, TakerRate: nilFeeValue,
+				TakerRateNative: nilFeeValue}
+			tiers[index] = tier
+		}
+		switch suffix {
+		case FeeTierVolumeSuffix:
+			tier.MinVolume = d.FeeValue
+		case FeeTierMakerRateSuffix:
+			tier.MakerRate = d.FeeValue
+		case FeeTierMakerRateNativeSuffix:
+			tier.MakerRateNative = d.FeeValue
+		case FeeTierTakerRateSuffix:
+			tier.TakerRate = d.FeeValue
+		case FeeTierTakerRateNativeSuffix:
+			tier.TakerRateNative = d.FeeValue
+		}
+	}
+	if len(tiers) == 0 {
+		return nil
+	}
+
+	res := make([]FeeTier, len(tiers))
+	prev := FeeTier{MakerRate: feeRate, MakerRateNative: feeRateNative, TakerRate: feeRate, TakerRateNative: feeRateNative}
+	for i := range res {
+		tier, ok := tiers[i]
+		if !ok {
+			// leave an invalid tier to reject the config
+			res[i] = FeeTier{MinVolume: nilFeeValue, MakerRate: nilFeeValue}
+			continue
+		}
+		inherit := func(rate *int64, prevRate int64) {
+			if *rate == nilFeeValue {
+				*rate = prevRate
+			}
+		}
+		inherit(&tier.MakerRate, prev.MakerRate)
+		inherit(&tier.MakerRateNative, prev.MakerRateNative)
+		inherit(&tier.TakerRate, prev.TakerRate)
+		inherit(&tier.TakerRateNative, prev.TakerRateNative)
+		res[i] = *tier
+		prev = *tier
+	}
+	return res
+}
+
 func ParamToFeeConfig(feeParams []param.FeeParam) *FeeConfig {
 	for _, p := range feeParams {
 		if u, ok := p.(*param.DexFeeParam); ok {
@@ -412,8 +641,15 @@ func ParamToFeeConfig(feeParams []param.FeeParam) *FeeConfig {
 					config.IOCExpireFee = d.FeeValue
 				case IOCExpireFeeNative:
 					config.IOCExpireFeeNative = d.FeeValue
+				case AmendFeeField:
+					config.AmendFee = d.FeeValue
+				case AmendFeeNativeField:
+					config.AmendFeeNative = d.FeeValue
+				case MakerRebateRateField:
+					config.MakerRebateRate = d.FeeValue
 				}
 			}
+			config.FeeTiers = paramToFeeTiers(u.DexFeeFields, config.FeeRate, config.FeeRateNative)
 			return &config
 		}
 	}
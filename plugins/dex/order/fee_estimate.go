@@ -0,0 +1,85 @@
+package order
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/common/types"
+	"github.com/bnb-chain/node/common/utils"
+	"github.com/bnb-chain/node/plugins/dex/store"
+	dexUtils "github.com/bnb-chain/node/plugins/dex/utils"
+)
+
+func toFeeAmount(coin sdk.Coin) store.FeeAmount {
+	return store.FeeAmount{Asset: coin.Denom, Amount: utils.Fixed8(coin.Amount)}
+}
+
+// EstimateFees returns the fees which would be charged for the hypothetical order of the params. The fees are
+// calculated in the same way as the allocation does, against the current engines and the balances of the sender.
+func (kp *DexKeeper) EstimateFees(ctx sdk.Context, params store.FeeEstimateQueryParams) (store.FeeEstimate, error) {
+	addr, err := sdk.AccAddressFromBech32(params.Address)
+	if err != nil {
+		return store.FeeEstimate{}, fmt.Errorf("address is not valid")
+	}
+	symbol := strings.ToUpper(params.Symbol)
+	if _, ok := kp.engines[symbol]; !ok {
+		return store.FeeEstimate{}, fmt.Errorf("trading pair %s does not exist", symbol)
+	}
+	if !IsValidSide(params.Side) {
+		return store.FeeEstimate{}, fmt.Errorf("invalid side %d", params.Side)
+	}
+	if params.Price <= 0 || params.Quantity <= 0 {
+		return store.FeeEstimate{}, fmt.Errorf("price and quantity should be greater than 0")
+	}
+
+	var balances sdk.Coins
+	if acc := kp.am.GetAccount(ctx, addr); acc != nil {
+		balances = acc.GetCoins()
+	}
+	baseAsset, quoteAsset := dexUtils.TradingPair2AssetsSafe(symbol)
+	notional := dexUtils.CalBigNotionalInt64(params.Price, params.Quantity)
+	tran := Transfer{inAsset: baseAsset, in: params.Quantity, outAsset: quoteAsset, out: notional}
+	if params.Side == Side.SELL {
+		tran = Transfer{inAsset: quoteAsset, in: notional, outAsset: baseAsset, out: params.Quantity}
+	}
+	if kp.FeeManager.HasFeeTiers() {
+		tran.feeTier = kp.FeeManager.FeeTier(kp.GetTradingVolume(ctx, addr))
+	}
+
+	var estimate store.FeeEstimate
+	// the trade fee is charged after the locked asset is paid out and the asset bought is received
+	tradeBalances := balances.Minus(sdk.Coins{sdk.NewCoin(tran.outAsset, tran.out)}).Plus(sdk.Coins{sdk.NewCoin(tran.inAsset, tran.in)})
+	charged := kp.FeeManager.calcTradeFeeFromTransfer(tradeBalances, &tran, kp.engines)
+	estimate.Trade.Charged = toFeeAmount(charged.Tokens[0])
+	rateNative, rate := kp.FeeManager.tradeFeeRates(&tran)
+	if nativeFee, isOverflow := kp.FeeManager.calcNativeFee(&tran, kp.engines, rateNative); !isOverflow && (nativeFee > 0 || tran.IsNativeIn()) {
+		estimate.Trade.Candidates = append(estimate.Trade.Candidates, toFeeAmount(sdk.NewCoin(types.NativeTokenSymbol, nativeFee)))
+	}
+	if !tran.IsNativeIn() {
+		fee := tradeFeeByRate(big.NewInt(tran.in), rate).Int64()
+		estimate.Trade.Candidates = append(estimate.Trade.Candidates, toFeeAmount(sdk.NewCoin(tran.inAsset, fee)))
+	}
+
+	// the fixed fees are charged after the locked asset is returned, so the balances are the same as before the order
+	fixedFee := func(eventType transferEventType, nativeFee int64) store.FeeCandidates {
+		charged := kp.FeeManager.CalcFixedFee(balances, eventType, tran.outAsset, kp.engines)
+		candidates := []store.FeeAmount{toFeeAmount(sdk.NewCoin(types.NativeTokenSymbol, nativeFee))}
+		if tran.outAsset != types.NativeTokenSymbol {
+			// the fee falls back to the locked asset if there is no enough native token
+			fee := kp.FeeManager.CalcFixedFee(sdk.Coins{sdk.NewCoin(tran.outAsset, math.MaxInt64)}, eventType, tran.outAsset, kp.engines)
+			candidates = append(candidates, toFeeAmount(fee.Tokens[0]))
+		}
+		return store.FeeCandidates{Charged: toFeeAmount(charged.Tokens[0]), Candidates: candidates}
+	}
+	cancelFeeNative, _ := kp.FeeManager.CancelFees()
+	estimate.Cancel = fixedFee(eventFullyCancel, cancelFeeNative)
+	expireFeeNative, _ := kp.FeeManager.ExpireFees()
+	estimate.Expire = fixedFee(eventFullyExpire, expireFeeNative)
+	iocExpireFeeNative, _ := kp.FeeManager.IOCExpireFees()
+	estimate.IOCExpire = fixedFee(eventIOCFullyExpire, iocExpireFeeNative)
+	return estimate, nil
+}
@@ -2,14 +2,20 @@ package order
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	param "github.com/cosmos/cosmos-sdk/x/paramHub/types"
 
 	"github.com/bnb-chain/node/common/testutils"
 	"github.com/bnb-chain/node/common/types"
+	"github.com/bnb-chain/node/common/upgrade"
+	"github.com/bnb-chain/node/common/utils"
 	"github.com/bnb-chain/node/plugins/dex/matcheng"
+	"github.com/bnb-chain/node/plugins/dex/store"
 	dextype "github.com/bnb-chain/node/plugins/dex/types"
 )
 
@@ -180,17 +186,17 @@ func TestFeeManager_CalcExpiresFee(t *testing.T) {
 
 	// in BNB
 	expireTransfers := ExpireTransfers{
-		{inAsset: "ABC-000", Symbol: "ABC-000_BNB", Oid: "1"},
-		{inAsset: "ABC-000", Symbol: "ABC-000_BTC", Oid: "2"},
-		{inAsset: "XYZ-111", Symbol: "XYZ-111_BTC", Oid: "3"},
-		{inAsset: "XYZ-111", Symbol: "XYZ-111_BNB", Oid: "4"},
-		{inAsset: "ABC-000", Symbol: "ABC-000_XYZ-111", Oid: "5"},
-		{inAsset: "BTC", Symbol: "BNB_BTC", Oid: "6"},
-		{inAsset: "BNB", Symbol: "BNB_BTC", Oid: "7"},
-		{inAsset: "BNB", Symbol: "ABC-000_BNB", Oid: "8"},
-		{inAsset: "ABC-000", Symbol: "ABC-000_BNB", Oid: "9"},
-		{inAsset: "ABC-000", Symbol: "ABC-000_BTC", Oid: "10"},
-		{inAsset: "ZYX-000M", Symbol: "ZYX-000M_BTC", Oid: "11"},
+		{eventType: eventFullyExpire, inAsset: "ABC-000", Symbol: "ABC-000_BNB", Oid: "1"},
+		{eventType: eventFullyExpire, inAsset: "ABC-000", Symbol: "ABC-000_BTC", Oid: "2"},
+		{eventType: eventFullyExpire, inAsset: "XYZ-111", Symbol: "XYZ-111_BTC", Oid: "3"},
+		{eventType: eventFullyExpire, inAsset: "XYZ-111", Symbol: "XYZ-111_BNB", Oid: "4"},
+		{eventType: eventFullyExpire, inAsset: "ABC-000", Symbol: "ABC-000_XYZ-111", Oid: "5"},
+		{eventType: eventFullyExpire, inAsset: "BTC", Symbol: "BNB_BTC", Oid: "6"},
+		{eventType: eventFullyExpire, inAsset: "BNB", Symbol: "BNB_BTC", Oid: "7"},
+		{eventType: eventFullyExpire, inAsset: "BNB", Symbol: "ABC-000_BNB", Oid: "8"},
+		{eventType: eventFullyExpire, inAsset: "ABC-000", Symbol: "ABC-000_BNB", Oid: "9"},
+		{eventType: eventFullyExpire, inAsset: "ABC-000", Symbol: "ABC-000_BTC", Oid: "10"},
+		{eventType: eventFullyExpire, inAsset: "ZYX-000M", Symbol: "ZYX-000M_BTC", Oid: "11"},
 	}
 	_, acc := testutils.NewAccount(ctx, am, 0)
 	_ = acc.SetCoins(sdk.Coins{
@@ -200,7 +206,7 @@ func TestFeeManager_CalcExpiresFee(t *testing.T) {
 		{"XYZ-111", 800000},
 		{"ZYX-000M", 900000},
 	})
-	fees := keeper.FeeManager.CalcExpiresFee(acc.GetCoins(), eventFullyExpire, expireTransfers, keeper.engines, nil)
+	fees := keeper.FeeManager.CalcExpiresFee(acc.GetCoins(), expireTransfers, keeper.engines, nil)
 	require.Equal(t, "ABC-000:1000000;BNB:120000;BTC:500;XYZ-111:800000;ZYX-000M:100000", fees.String())
 	require.Equal(t, "BNB:20000", expireTransfers[0].Fee.String())
 	require.Equal(t, "BNB:20000", expireTransfers[1].Fee.String())
@@ -222,6 +228,26 @@ func TestFeeManager_CalcExpiresFee(t *testing.T) {
 	}, acc.GetCoins())
 }
 
+func TestFeeManager_CalcExpiresFee_MixedEventTypes(t *testing.T) {
+	setChainVersion()
+	defer resetChainVersion()
+	ctx, am, keeper := setup()
+	keeper.FeeManager.UpdateConfig(NewTestFeeConfig())
+	keeper.AddEngine(dextype.NewTradingPair("ABC-000", "BNB", 1e7))
+
+	expireTransfers := ExpireTransfers{
+		{eventType: eventFullyExpire, inAsset: "BNB", Symbol: "ABC-000_BNB", Oid: "1"},
+		{eventType: eventIOCFullyExpire, inAsset: "BNB", Symbol: "ABC-000_BNB", Oid: "2"},
+		{eventType: eventFOKExpire, inAsset: "BNB", Symbol: "ABC-000_BNB", Oid: "3"},
+	}
+	_, acc := testutils.NewAccount(ctx, am, 1e8)
+	fees := keeper.FeeManager.CalcExpiresFee(acc.GetCoins(), expireTransfers, keeper.engines, nil)
+	require.Equal(t, "BNB:40000", fees.String())
+	require.Equal(t, "BNB:20000", expireTransfers[0].Fee.String())
+	require.Equal(t, "BNB:10000", expireTransfers[1].Fee.String())
+	require.Equal(t, "BNB:10000", expireTransfers[2].Fee.String())
+}
+
 func TestFeeManager_calcTradeFee(t *testing.T) {
 	setChainVersion()
 	defer resetChainVersion()
@@ -314,6 +340,9 @@ func feeManagerCalcFixedFee(t *testing.T, symbol1 string, symbol2 string) {
 	fee = keeper.FeeManager.CalcFixedFee(acc.GetCoins(), eventIOCFullyExpire, types.NativeTokenSymbol, keeper.engines)
 	require.Equal(t, sdk.Coins{sdk.NewCoin(types.NativeTokenSymbol, 1e4)}, fee.Tokens)
 
+	fee = keeper.FeeManager.CalcFixedFee(acc.GetCoins(), eventFOKExpire, types.NativeTokenSymbol, keeper.engines)
+	require.Equal(t, sdk.Coins{sdk.NewCoin(types.NativeTokenSymbol, 1e4)}, fee.Tokens)
+
 	fee = keeper.FeeManager.CalcFixedFee(acc.GetCoins(), eventFullyCancel, types.NativeTokenSymbol, keeper.engines)
 	require.Equal(t, sdk.Coins{sdk.NewCoin(types.NativeTokenSymbol, 2e4)}, fee.Tokens)
 
@@ -493,3 +522,164 @@ func TestFeeManager_CalcFixedFee_SupportBUSD(t *testing.T) {
 	fee = keeper.FeeManager.CalcFixedFee(acc.GetCoins(), eventFullyExpire, "XYZ-999", keeper.engines)
 	require.Equal(t, sdk.Coins{sdk.NewCoin("XYZ-999", 1e2)}, fee.Tokens)
 }
+
+func TestFeeManager_FeeTiers(t *testing.T) {
+	setChainVersion()
+	defer resetChainVersion()
+	ctx, am, keeper := setup()
+	fields := []param.DexFeeField{
+		{FeeName: FeeRateField, FeeValue: 1000},
+		{FeeName: FeeRateNativeField, FeeValue: 500},
+		{FeeName: "FeeTier0MakerRateNative", FeeValue: 400},
+		{FeeName: "FeeTier1Volume", FeeValue: 1e8},
+		{FeeName: "FeeTier1MakerRateNative", FeeValue: 200},
+		{FeeName: "FeeTier1TakerRateNative", FeeValue: 300},
+	}
+	config := ParamToFeeConfig([]param.FeeParam{&param.DexFeeParam{DexFeeFields: fields}})
+	require.Equal(t, []FeeTier{
+		{MinVolume: 0, MakerRate: 1000, MakerRateNative: 400, TakerRate: 1000, TakerRateNative: 500},
+		{MinVolume: 1e8, MakerRate: 1000, MakerRateNative: 200, TakerRate: 1000, TakerRateNative: 300},
+	}, config.FeeTiers)
+	require.Nil(t, keeper.FeeManager.UpdateConfig(*config))
+	require.Equal(t, 0, keeper.FeeManager.FeeTier(1e8-1))
+	require.Equal(t, 1, keeper.FeeManager.FeeTier(1e8))
+
+	// the tiers must be contiguous with increasing volumes
+	gap := append(fields, param.DexFeeField{FeeName: "FeeTier3Volume", FeeValue: 1e9})
+	config = ParamToFeeConfig([]param.FeeParam{&param.DexFeeParam{DexFeeFields: gap}})
+	require.NotNil(t, keeper.FeeManager.UpdateConfig(*config))
+	decreasing := append(fields, param.DexFeeField{FeeName: "FeeTier2Volume", FeeValue: 1e7})
+	config = ParamToFeeConfig([]param.FeeParam{&param.DexFeeParam{DexFeeFields: decreasing}})
+	require.NotNil(t, keeper.FeeManager.UpdateConfig(*config))
+
+	keeper.AddEngine(dextype.NewTradingPair("ABC-000", "BNB", 1e7))
+	_, acc := testutils.NewAccount(ctx, am, 100e8)
+	trade := &matcheng.Trade{Bid: "1", Sid: "2", TickType: matcheng.SellTaker}
+	buy := &Transfer{inAsset: "ABC-000", in: 1e8, outAsset: "BNB", out: 1e7, Oid: "1", Trade: trade}
+	sell := &Transfer{inAsset: "BNB", in: 1e7, outAsset: "ABC-000", out: 1e8, Oid: "2", Trade: trade}
+	// the fee tiers are not applied before the upgrade
+	require.False(t, keeper.FeeManager.HasFeeTiers())
+	keeper.FeeManager.CalcTradesFee(acc.GetCoins(), TradeTransfers{buy}, keeper.engines)
+	require.Equal(t, "BNB:5000", trade.BuyerFee.String())
+	require.Equal(t, "BNB:5000", keeper.FeeManager.SerializeTradeFeeForPub(*trade.BuyerFee, buy.feeTier, nil))
+
+	upgrade.Mgr.AddUpgradeHeight(upgrade.DexEnhancements, -1)
+	keeper.FeeManager.CalcTradesFee(acc.GetCoins(), TradeTransfers{buy}, keeper.engines)
+	keeper.FeeManager.CalcTradesFee(acc.GetCoins(), TradeTransfers{sell}, keeper.engines)
+	require.Equal(t, "BNB:4000", trade.BuyerFee.String())
+	require.Equal(t, "BNB:5000", trade.SellerFee.String())
+	require.Equal(t, "BNB:4000;#Tier:0", keeper.FeeManager.SerializeTradeFeeForPub(*trade.BuyerFee, buy.feeTier, nil))
+
+	buy.feeTier, sell.feeTier = 1, 1
+	keeper.FeeManager.CalcTradesFee(acc.GetCoins(), TradeTransfers{buy}, keeper.engines)
+	keeper.FeeManager.CalcTradesFee(acc.GetCoins(), TradeTransfers{sell}, keeper.engines)
+	require.Equal(t, "BNB:2000", trade.BuyerFee.String())
+	require.Equal(t, "BNB:3000", trade.SellerFee.String())
+	require.Equal(t, "BNB:3000;#Tier:1", keeper.FeeManager.SerializeTradeFeeForPub(*trade.SellerFee, sell.feeTier, nil))
+	require.Equal(t, int64(2e7), keeper.FeeManager.CalcTradesVolume(TradeTransfers{buy, sell}, keeper.engines))
+}
+
+func TestKeeper_TradingVolume(t *testing.T) {
+	ctx, _, keeper := setup()
+	addr := sdk.AccAddress([]byte("addr"))
+	day := time.Unix(100*secondsPerDay, 0)
+
+	ctx = ctx.WithBlockHeader(abci.Header{Time: day})
+	keeper.addTradingVolume(ctx, addr, keeper.getTradingVolume(ctx, addr), 100)
+	ctx = ctx.WithBlockHeader(abci.Header{Time: day.Add(time.Hour)})
+	keeper.addTradingVolume(ctx, addr, keeper.getTradingVolume(ctx, addr), 200)
+	ctx = ctx.WithBlockHeader(abci.Header{Time: day.AddDate(0, 0, 10)})
+	keeper.addTradingVolume(ctx, addr, keeper.getTradingVolume(ctx, addr), 300)
+	require.Equal(t, int64(600), keeper.GetTradingVolume(ctx, addr))
+
+	// the volume of the first day is out of the trailing days
+	ctx = ctx.WithBlockHeader(abci.Header{Time: day.AddDate(0, 0, TradingVolumeDays)})
+	require.Equal(t, int64(300), keeper.GetTradingVolume(ctx, addr))
+	ctx = ctx.WithBlockHeader(abci.Header{Time: day.AddDate(0, 0, 3*TradingVolumeDays)})
+	require.Equal(t, int64(0), keeper.GetTradingVolume(ctx, addr))
+}
+
+func TestFeeManager_MakerRebate(t *testing.T) {
+	setChainVersion()
+	defer resetChainVersion()
+	ctx, am, keeper := setup()
+	config := NewTestFeeConfig()
+	config.MakerRebateRate = 1e6 + 1
+	require.NotNil(t, keeper.FeeManager.UpdateConfig(config))
+	config.MakerRebateRate = 2e5
+	require.Nil(t, keeper.FeeManager.UpdateConfig(config))
+	// the rebates are not paid before the upgrade
+	require.False(t, keeper.FeeManager.HasMakerRebate())
+	upgrade.Mgr.AddUpgradeHeight(upgrade.DexEnhancements, -1)
+	require.True(t, keeper.FeeManager.HasMakerRebate())
+
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 1000)},
+		keeper.FeeManager.CalcMakerRebate(sdk.NewFee(sdk.Coins{sdk.NewCoin("BNB", 5000)}, sdk.FeeForProposer)))
+	// the rebate rounding to 0 is not paid
+	require.Nil(t, keeper.FeeManager.CalcMakerRebate(sdk.NewFee(sdk.Coins{sdk.NewCoin("BNB", 4)}, sdk.FeeForProposer)))
+
+	keeper.AddEngine(dextype.NewTradingPair("ABC-000", "BNB", 1e7))
+	_, taker := testutils.NewAccount(ctx, am, 100e8)
+	_, makerAcc := testutils.NewAccount(ctx, am, 0)
+	maker := makerAcc.GetAddress()
+	trade := &matcheng.Trade{Bid: "1", Sid: "2", TickType: matcheng.SellTaker}
+	buy := &Transfer{accAddress: maker, inAsset: "ABC-000", in: 1e8, outAsset: "BNB", out: 1e7, Oid: "1", Trade: trade}
+	sell := &Transfer{inAsset: "BNB", in: 1e7, outAsset: "ABC-000", out: 1e8, Oid: "2", Trade: trade}
+	keeper.FeeManager.CalcTradesFee(sdk.Coins{}, TradeTransfers{buy}, keeper.engines)
+	keeper.FeeManager.CalcTradesFee(taker.GetCoins(), TradeTransfers{sell}, keeper.engines)
+	require.Equal(t, "BNB:5000", trade.SellerFee.String())
+
+	rebates := keeper.payMakerRebates(ctx, TradeTransfers{buy})
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 1000)}, rebates)
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 1000)}, am.GetAccount(ctx, maker).GetCoins())
+	require.Equal(t, rebates, keeper.GetRoundRebates())
+	require.Equal(t, "ABC-000:100000;#Rebate:BNB:1000", keeper.SerializeTradeFeeForPub(trade, true))
+	require.Equal(t, "BNB:5000", keeper.SerializeTradeFeeForPub(trade, false))
+	require.Equal(t, "BNB:9000;#Rebate:BNB:1000", AppendRebatesForPub("BNB:9000", rebates))
+}
+
+func TestKeeper_EstimateFees(t *testing.T) {
+	setChainVersion()
+	defer resetChainVersion()
+	ctx, am, keeper := setup()
+	keeper.FeeManager.UpdateConfig(NewTestFeeConfig())
+	keeper.AddEngine(dextype.NewTradingPair("ABC-000", "BNB", 1e7))
+	_, acc := testutils.NewAccount(ctx, am, 0)
+	_ = acc.SetCoins(sdk.Coins{sdk.NewCoin("ABC-000", 100e8), sdk.NewCoin("BNB", 1e8)})
+	am.SetAccount(ctx, acc)
+	params := store.FeeEstimateQueryParams{Address: acc.GetAddress().String(), Symbol: "abc-000_bnb", Side: Side.BUY, Price: 1e7, Quantity: 1e8}
+
+	estimate, err := keeper.EstimateFees(ctx, params)
+	require.Nil(t, err)
+	require.Equal(t, store.FeeCandidates{
+		Charged:    store.FeeAmount{Asset: "BNB", Amount: utils.Fixed8(5000)},
+		Candidates: []store.FeeAmount{{Asset: "BNB", Amount: utils.Fixed8(5000)}, {Asset: "ABC-000", Amount: utils.Fixed8(1e5)}},
+	}, estimate.Trade)
+	// the buy order locks the native token
+	require.Equal(t, store.FeeCandidates{
+		Charged:    store.FeeAmount{Asset: "BNB", Amount: utils.Fixed8(2e4)},
+		Candidates: []store.FeeAmount{{Asset: "BNB", Amount: utils.Fixed8(2e4)}},
+	}, estimate.Cancel)
+
+	// no enough native token left after the trade
+	params.Quantity = 10e8
+	estimate, err = keeper.EstimateFees(ctx, params)
+	require.Nil(t, err)
+	require.Equal(t, store.FeeAmount{Asset: "ABC-000", Amount: utils.Fixed8(1e6)}, estimate.Trade.Charged)
+
+	_ = acc.SetCoins(sdk.Coins{sdk.NewCoin("ABC-000", 100e8)})
+	am.SetAccount(ctx, acc)
+	params.Side = Side.SELL
+	estimate, err = keeper.EstimateFees(ctx, params)
+	require.Nil(t, err)
+	require.Equal(t, store.FeeAmount{Asset: "BNB", Amount: utils.Fixed8(5e4)}, estimate.Trade.Charged)
+	require.Equal(t, store.FeeCandidates{
+		Charged:    store.FeeAmount{Asset: "ABC-000", Amount: utils.Fixed8(1e6)},
+		Candidates: []store.FeeAmount{{Asset: "BNB", Amount: utils.Fixed8(2e4)}, {Asset: "ABC-000", Amount: utils.Fixed8(1e6)}},
+	}, estimate.Expire)
+	require.Equal(t, store.FeeAmount{Asset: "ABC-000", Amount: utils.Fixed8(5e5)}, estimate.IOCExpire.Charged)
+
+	params.Symbol = "XYZ-000_BNB"
+	_, err = keeper.EstimateFees(ctx, params)
+	require.NotNil(t, err)
+}
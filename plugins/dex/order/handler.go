@@ -30,9 +30,29 @@ func NewHandler(dexKeeper *DexKeeper) sdk.Handler {
 			if sdk.IsUpgrade(upgrade.BEP151) {
 				return sdk.ErrMsgNotSupported("NewOrderMsg disabled in BEP-151").Result()
 			}
+			if status := dexKeeper.GetTradingStatus(msg.Symbol); status != types.TradingStatusTrading {
+				return types.ErrTradingPairNotTrading(strings.ToUpper(msg.Symbol), status).Result()
+			}
 			return handleNewOrder(ctx, dexKeeper, msg)
 		case CancelOrderMsg:
+			if status := dexKeeper.GetTradingStatus(msg.Symbol); status == types.TradingStatusHalted {
+				return types.ErrTradingPairNotTrading(strings.ToUpper(msg.Symbol), status).Result()
+			}
 			return handleCancelOrder(ctx, dexKeeper, msg)
+		case AmendOrderMsg:
+			if sdk.IsUpgrade(upgrade.BEP151) {
+				return sdk.ErrMsgNotSupported("AmendOrderMsg disabled in BEP-151").Result()
+			}
+			if status := dexKeeper.GetTradingStatus(msg.Symbol); status != types.TradingStatusTrading {
+				return types.ErrTradingPairNotTrading(strings.ToUpper(msg.Symbol), status).Result()
+			}
+			return handleAmendOrder(ctx, dexKeeper, msg)
+		case CancelAllOrdersMsg:
+			// the orders of the halted pairs are skipped if no symbol is specified
+			if status := dexKeeper.GetTradingStatus(msg.Symbol); msg.Symbol != "" && status == types.TradingStatusHalted {
+				return types.ErrTradingPairNotTrading(strings.ToUpper(msg.Symbol), status).Result()
+			}
+			return handleCancelAllOrders(ctx, dexKeeper, msg)
 		default:
 			errMsg := fmt.Sprintf("Unrecognized dex msg type: %v", reflect.TypeOf(msg).Name())
 			return sdk.ErrUnknownRequest(errMsg).Result()
@@ -109,6 +129,15 @@ func handleNewOrder(
 		return sdk.NewError(types.DefaultCodespace, types.CodeDuplicatedOrder, errString).Result()
 	}
 
+	var err error
+	// the market orders are rejected by ValidateBasic before the upgrade
+	if sdk.IsUpgrade(upgrade.DexEnhancements) {
+		msg, err = dexKeeper.PriceMarketOrder(ctx, msg)
+		if err != nil {
+			return sdk.NewError(types.DefaultCodespace, types.CodeInvalidOrderParam, err.Error()).Result()
+		}
+	}
+
 	acc := dexKeeper.am.GetAccount(ctx, msg.Sender).(common.NamedAccount)
 	if !ctx.IsReCheckTx() {
 		//for recheck:
@@ -122,10 +151,14 @@ func handleNewOrder(
 		if err != nil {
 			return sdk.NewError(types.DefaultCodespace, types.CodeInvalidOrderParam, err.Error()).Result()
 		}
+
+		if err := dexKeeper.validateOrderLimits(ctx, msg.Sender, strings.ToUpper(msg.Symbol)); err != nil {
+			return types.ErrOrderLimitExceeded(err.Error()).Result()
+		}
 	}
 
 	// the following is done in the app's checkstate / deliverstate, so it's safe to ignore isCheckTx
-	err := validateQtyAndLockBalance(ctx, dexKeeper, acc, msg)
+	err = validateQtyAndLockBalance(ctx, dexKeeper, acc, msg)
 	if err != nil {
 		return sdk.NewError(types.DefaultCodespace, types.CodeInvalidOrderParam, err.Error()).Result()
 	}
@@ -161,6 +194,7 @@ func handleNewOrder(
 			panic("cannot get txHash from ctx")
 		}
 	}
+	dexKeeper.recordNewOrder(ctx, msg.Sender)
 
 	response := NewOrderResponse{
 		OrderID: msg.Id,
@@ -234,6 +268,197 @@ func handleCancelOrder(
 	return sdk.Result{}
 }
 
+func handleCancelAllOrders(
+	ctx sdk.Context, dexKeeper *DexKeeper, msg CancelAllOrdersMsg,
+) sdk.Result {
+	// the orders are sorted, so the balance changes and the order changes are in a deterministic sequence
+	origOrds := dexKeeper.getCancelableOrderInfos(msg.Sender, msg.Symbol, msg.Side)
+	if len(origOrds) == 0 {
+		errString := fmt.Sprintf("Failed to find any open order of [%v] to cancel", msg.Sender)
+		return sdk.NewError(types.DefaultCodespace, types.CodeFailLocateOrderToCancel, errString).Result()
+	}
+
+	// every order is charged the same cancel fee as a single CancelOrderMsg, calculated on the balance
+	// left by the orders before it, and the fees are collected as one fee of the tx
+	totalFee := sdk.Fee{}
+	orderFees := make([]sdk.Fee, len(origOrds))
+	for i, origOrd := range origOrds {
+		ord, err := dexKeeper.GetOrder(origOrd.Id, origOrd.Symbol, origOrd.Side, origOrd.Price)
+		if err != nil {
+			return sdk.NewError(types.DefaultCodespace, types.CodeFailLocateOrderToCancel, err.Error()).Result()
+		}
+		transfer := TransferFromCanceled(ord, origOrd, false)
+		sdkError := dexKeeper.doTransfer(ctx, &transfer)
+		if sdkError != nil {
+			return sdkError.Result()
+		}
+		if !transfer.FeeFree() {
+			acc := dexKeeper.am.GetAccount(ctx, msg.Sender)
+			orderFees[i] = dexKeeper.FeeManager.CalcFixedFee(acc.GetCoins(), transfer.eventType, transfer.inAsset, dexKeeper.GetEngines())
+			_ = acc.SetCoins(acc.GetCoins().Minus(orderFees[i].Tokens))
+			dexKeeper.am.SetAccount(ctx, acc)
+			totalFee.AddFee(orderFees[i])
+		}
+	}
+
+	// this is done in memory! we must not run this block in checktx or simulate!
+	if ctx.IsDeliverTx() {
+		txHash, ok := ctx.Value(baseapp.TxHashKey).(string)
+		if !ok {
+			panic("cannot get txHash from ctx")
+		}
+		// all the orders are located above, and the tx can not fail once any of them is removed from memory
+		for i, origOrd := range origOrds {
+			fee := orderFees[i]
+			//remove order from cache and order book
+			err := dexKeeper.RemoveOrder(origOrd.Id, origOrd.Symbol, func(ord me.OrderPart) {
+				if dexKeeper.ShouldPublishOrder() {
+					change := OrderChange{origOrd.Id, Canceled, fee.String(), nil}
+					dexKeeper.UpdateOrderChangeSync(change, origOrd.Symbol)
+				}
+			})
+			if err != nil {
+				dexKeeper.logger.Error("Failed to remove order to cancel, may be fatal!", "orderID", origOrd.Id, "err", err)
+			}
+		}
+		// add fee to pool, even it's free
+		fees.Pool.AddFee(txHash, totalFee)
+		if dexKeeper.ShouldPublishOrder() {
+			dexKeeper.updateRoundOrderFee(string(msg.Sender), totalFee)
+		}
+	}
+
+	return sdk.Result{}
+}
+
+func handleAmendOrder(
+	ctx sdk.Context, dexKeeper *DexKeeper, msg AmendOrderMsg,
+) sdk.Result {
+	origOrd, ok := dexKeeper.OrderExists(msg.Symbol, msg.RefId)
+	if !ok {
+		errString := fmt.Sprintf("Failed to find order [%v]", msg.RefId)
+		return sdk.NewError(types.DefaultCodespace, types.CodeFailLocateOrderToAmend, errString).Result()
+	}
+
+	// only can amend their own order
+	if !reflect.DeepEqual(msg.Sender, origOrd.Sender) {
+		errString := fmt.Sprintf("Order [%v] does not belong to transaction sender", msg.RefId)
+		return sdk.NewError(types.DefaultCodespace, types.CodeFailLocateOrderToAmend, errString).Result()
+	}
+
+	if !ctx.IsReCheckTx() {
+		if err := validateAmendOrder(ctx, dexKeeper, origOrd, msg); err != nil {
+			return sdk.NewError(types.DefaultCodespace, types.CodeInvalidOrderParam, err.Error()).Result()
+		}
+	}
+
+	acc := dexKeeper.am.GetAccount(ctx, msg.Sender).(common.NamedAccount)
+	lockAsset, toLock := amendedLockDelta(origOrd, msg)
+	if toLock > 0 && acc.GetCoins().AmountOf(lockAsset) < toLock {
+		return sdk.NewError(types.DefaultCodespace, types.CodeInvalidOrderParam, "do not have enough token to lock").Result()
+	}
+	if toLock > 0 {
+		toLockCoins := sdk.Coins{sdk.NewCoin(lockAsset, toLock)}
+		_ = acc.SetCoins(acc.GetCoins().Minus(toLockCoins))
+		acc.SetLockedCoins(acc.GetLockedCoins().Plus(toLockCoins))
+	} else if toLock < 0 {
+		toUnlockCoins := sdk.Coins{sdk.NewCoin(lockAsset, -toLock)}
+		acc.SetLockedCoins(acc.GetLockedCoins().Minus(toUnlockCoins))
+		_ = acc.SetCoins(acc.GetCoins().Plus(toUnlockCoins))
+	}
+	fee := dexKeeper.FeeManager.CalcFixedFee(acc.GetCoins(), eventAmend, lockAsset, dexKeeper.GetEngines())
+	_ = acc.SetCoins(acc.GetCoins().Minus(fee.Tokens))
+	dexKeeper.am.SetAccount(ctx, acc)
+
+	// this is done in memory! we must not run this block in checktx or simulate!
+	if ctx.IsDeliverTx() {
+		if txHash, ok := ctx.Value(baseapp.TxHashKey).(string); !ok {
+			panic("cannot get txHash from ctx")
+		} else {
+			// add fee to pool, even it's free
+			fees.Pool.AddFee(txHash, fee)
+		}
+		blockHeader := ctx.BlockHeader()
+		origOrd, err := dexKeeper.AmendOrder(msg, blockHeader.Height, blockHeader.Time.UnixNano())
+		if err != nil {
+			return sdk.NewError(types.DefaultCodespace, types.CodeFailAmendOrder, err.Error()).Result()
+		}
+		if dexKeeper.ShouldPublishOrder() {
+			change := OrderChange{msg.RefId, Amended, fee.String(), origOrd}
+			dexKeeper.UpdateOrderChangeSync(change, msg.Symbol)
+			dexKeeper.updateRoundOrderFee(string(msg.Sender), fee)
+		}
+	}
+
+	return sdk.Result{}
+}
+
+// amendedLockDelta returns the asset locked by the order and how much more of it the amended order
+// needs to lock, which is negative if some of the locked balance should be unlocked.
+func amendedLockDelta(origOrd OrderInfo, msg AmendOrderMsg) (string, int64) {
+	baseAsset, quoteAsset := utils.TradingPair2AssetsSafe(strings.ToUpper(origOrd.Symbol))
+	if origOrd.Side == Side.BUY {
+		locked := utils.CalBigNotionalInt64(origOrd.Price, origOrd.Quantity) - utils.CalBigNotionalInt64(origOrd.Price, origOrd.CumQty)
+		toLock := utils.CalBigNotionalInt64(msg.Price, msg.Quantity) - utils.CalBigNotionalInt64(msg.Price, origOrd.CumQty)
+		return quoteAsset, toLock - locked
+	}
+	return baseAsset, msg.Quantity - origOrd.Quantity
+}
+
+func validateAmendOrder(ctx sdk.Context, dexKeeper *DexKeeper, origOrd OrderInfo, msg AmendOrderMsg) error {
+	// IOC and FOK orders never rest in the order book, and the price of market orders is decided by the match engine
+	if origOrd.OrderType == OrderType.MARKET ||
+		(origOrd.TimeInForce != TimeInForce.GTE && origOrd.TimeInForce != TimeInForce.POST_ONLY) {
+		return fmt.Errorf("order [%v] cannot be amended", origOrd.Id)
+	}
+	// the new price of an untriggered stop order could cross its stop price, so it has to be canceled and placed again
+	if dexKeeper.isUntriggeredStopOrder(origOrd.Symbol, origOrd.Id) {
+		return fmt.Errorf("untriggered stop order [%v] cannot be amended", origOrd.Id)
+	}
+	if msg.Price == origOrd.Price && msg.Quantity == origOrd.Quantity {
+		return errors.New("neither price nor quantity is changed")
+	}
+	if msg.Quantity <= origOrd.CumQty {
+		return fmt.Errorf("quantity(%v) is not larger than the filled quantity(%v)", msg.Quantity, origOrd.CumQty)
+	}
+	if msg.Quantity < origOrd.DisplayQty {
+		return fmt.Errorf("quantity(%v) is less than the display quantity(%v)", msg.Quantity, origOrd.DisplayQty)
+	}
+
+	baseAsset, quoteAsset, err := utils.TradingPair2Assets(origOrd.Symbol)
+	if err != nil {
+		return err
+	}
+	pair, err := dexKeeper.PairMapper.GetTradingPair(ctx, baseAsset, quoteAsset)
+	if err != nil {
+		return err
+	}
+	if msg.Quantity%pair.LotSize.ToInt64() != 0 {
+		return fmt.Errorf("quantity(%v) is not rounded to lotSize(%v)", msg.Quantity, pair.LotSize.ToInt64())
+	}
+	if msg.Price%pair.TickSize.ToInt64() != 0 {
+		return fmt.Errorf("price(%v) is not rounded to tickSize(%v)", msg.Price, pair.TickSize.ToInt64())
+	}
+	if sdk.IsUpgrade(sdk.BEP8) && isMiniSymbolPair(baseAsset, quoteAsset) && msg.Quantity < common.MiniTokenMinExecutionAmount {
+		return fmt.Errorf("quantity is too small, the min quantity is %d", common.MiniTokenMinExecutionAmount)
+	}
+	if err := pair.ValidateOrderSize(msg.Price, msg.Quantity); err != nil {
+		return err
+	}
+
+	if origOrd.Side == Side.BUY && !msg.IsSizeReduction(origOrd.NewOrderMsg) {
+		// the same implicit requirement from the match engine as new orders
+		totalQty := msg.Quantity - origOrd.CumQty
+		if pl := dexKeeper.GetPriceLevel(origOrd.Symbol, origOrd.Side, msg.Price); pl != nil {
+			totalQty += pl.TotalLeavesQty()
+		}
+		if totalQty < 0 {
+			return errors.New("order quantity is too large to be placed on this price level")
+		}
+	}
+	return nil
+}
+
 func validateOrder(ctx sdk.Context, dexKeeper *DexKeeper, acc sdk.Account, msg NewOrderMsg) error {
 	baseAsset, quoteAsset, err := utils.TradingPair2Assets(msg.Symbol)
 	if err != nil {
@@ -259,6 +484,22 @@ func validateOrder(ctx sdk.Context, dexKeeper *DexKeeper, acc sdk.Account, msg N
 		return fmt.Errorf("price(%v) is not rounded to tickSize(%v)", msg.Price, pair.TickSize.ToInt64())
 	}
 
+	if msg.OrderType == OrderType.STOP_LIMIT && msg.StopPrice%pair.TickSize.ToInt64() != 0 {
+		return fmt.Errorf("stop price(%v) is not rounded to tickSize(%v)", msg.StopPrice, pair.TickSize.ToInt64())
+	}
+
+	if msg.DisplayQty%pair.LotSize.ToInt64() != 0 {
+		return fmt.Errorf("display quantity(%v) is not rounded to lotSize(%v)", msg.DisplayQty, pair.LotSize.ToInt64())
+	}
+
+	blockHeader := ctx.BlockHeader()
+	if msg.GoodTillHeight != 0 && msg.GoodTillHeight < blockHeader.Height {
+		return fmt.Errorf("good till height(%v) is before the current height(%v)", msg.GoodTillHeight, blockHeader.Height)
+	}
+	if msg.GoodTillTime != 0 && msg.GoodTillTime < blockHeader.Time.Unix() {
+		return fmt.Errorf("good till time(%v) is before the current block time(%v)", msg.GoodTillTime, blockHeader.Time.Unix())
+	}
+
 	if sdk.IsUpgrade(upgrade.LotSizeOptimization) {
 		if utils.IsUnderMinNotional(msg.Price, msg.Quantity) {
 			return errors.New("notional value of the order is too small")
@@ -269,5 +510,9 @@ func validateOrder(ctx sdk.Context, dexKeeper *DexKeeper, acc sdk.Account, msg N
 		return errors.New("notional value of the order is too large(cannot fit in int64)")
 	}
 
+	if err := pair.ValidateOrderSize(msg.Price, msg.Quantity); err != nil {
+		return err
+	}
+
 	return nil
 }
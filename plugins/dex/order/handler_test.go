@@ -5,8 +5,10 @@ import (
 	"math"
 	"testing"
 
+	"github.com/cosmos/cosmos-sdk/baseapp"
 	cstore "github.com/cosmos/cosmos-sdk/store"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/fees"
 	"github.com/cosmos/cosmos-sdk/x/auth"
 	"github.com/stretchr/testify/require"
 	abci "github.com/tendermint/tendermint/abci/types"
@@ -14,6 +16,11 @@ import (
 	"github.com/tendermint/tendermint/libs/log"
 
 	"github.com/bnb-chain/node/common"
+	"github.com/bnb-chain/node/common/testutils"
+	commontypes "github.com/bnb-chain/node/common/types"
+	"github.com/bnb-chain/node/common/upgrade"
+	"github.com/bnb-chain/node/common/utils"
+	me "github.com/bnb-chain/node/plugins/dex/matcheng"
 	"github.com/bnb-chain/node/plugins/dex/store"
 	"github.com/bnb-chain/node/plugins/dex/types"
 	dextypes "github.com/bnb-chain/node/plugins/dex/types"
@@ -192,3 +199,744 @@ func TestHandler_ValidateOrder_MaxNotional(t *testing.T) {
 	require.Error(t, err)
 	require.Equal(t, "notional value of the order is too large(cannot fit in int64)", err.Error())
 }
+
+func TestHandler_ValidateOrder_GoodTill(t *testing.T) {
+	pairMapper, accMapper, ctx, keeper := setupMappers()
+	err := pairMapper.AddTradingPair(ctx, types.NewTradingPair("AAA-000", "BNB", 1e8))
+	require.NoError(t, err)
+
+	acc, _ := setupAccount(ctx, accMapper)
+	ctx = ctx.WithBlockHeader(abci.Header{Height: 10})
+
+	msg := NewOrderMsg{
+		Symbol:         "AAA-000_BNB",
+		Sender:         acc.GetAddress(),
+		Price:          1e3,
+		Quantity:       1e5,
+		Id:             fmt.Sprintf("%X-0", acc.GetAddress()),
+		OrderType:      OrderType.LIMIT,
+		Side:           Side.BUY,
+		TimeInForce:    TimeInForce.GTE,
+		GoodTillHeight: 20,
+	}
+	require.Contains(t, msg.ValidateBasic().Error(), "GoodTillHeight and GoodTillTime are not supported yet")
+
+	upgrade.Mgr.AddUpgradeHeight(upgrade.DexEnhancements, -1)
+	defer resetChainVersion()
+	require.Nil(t, msg.ValidateBasic())
+	err = validateOrder(ctx, keeper, acc, msg)
+	require.NoError(t, err)
+
+	msg.GoodTillHeight = 9
+	err = validateOrder(ctx, keeper, acc, msg)
+	require.EqualError(t, err, "good till height(9) is before the current height(10)")
+}
+
+func TestHandler_ValidateOrder_Iceberg(t *testing.T) {
+	pairMapper, accMapper, ctx, keeper := setupMappers()
+	pair := types.NewTradingPair("AAA-000", "BNB", 1e8)
+	err := pairMapper.AddTradingPair(ctx, pair)
+	require.NoError(t, err)
+
+	acc, _ := setupAccount(ctx, accMapper)
+
+	msg := NewOrderMsg{
+		Symbol:      "AAA-000_BNB",
+		Sender:      acc.GetAddress(),
+		Price:       1e3,
+		Quantity:    1e6,
+		Id:          fmt.Sprintf("%X-0", acc.GetAddress()),
+		OrderType:   OrderType.LIMIT,
+		Side:        Side.BUY,
+		TimeInForce: TimeInForce.GTE,
+		DisplayQty:  1e5,
+	}
+	require.Contains(t, msg.ValidateBasic().Error(), "DisplayQty is not supported yet")
+
+	upgrade.Mgr.AddUpgradeHeight(upgrade.DexEnhancements, -1)
+	defer resetChainVersion()
+	require.Nil(t, msg.ValidateBasic())
+	err = validateOrder(ctx, keeper, acc, msg)
+	require.NoError(t, err)
+
+	msg.DisplayQty = 1e5 + 1e4
+	err = validateOrder(ctx, keeper, acc, msg)
+	require.EqualError(t, err, fmt.Sprintf("display quantity(%v) is not rounded to lotSize(%v)", msg.DisplayQty, pair.LotSize.ToInt64()))
+}
+
+func TestHandler_CancelStopLimitOrder(t *testing.T) {
+	ctx, am, keeper := setup()
+	keeper.EnablePublish()
+	keeper.FeeManager.UpdateConfig(NewTestFeeConfig())
+	_, acc := testutils.NewAccount(ctx, am, 100e8)
+	addr := acc.GetAddress()
+	pair := types.NewTradingPair("XYZ-000", "BNB", 1e8)
+	err := keeper.PairMapper.AddTradingPair(ctx, pair)
+	require.NoError(t, err)
+	keeper.AddEngine(pair)
+	ctx = ctx.WithValue(baseapp.TxHashKey, "000001")
+
+	msg := NewStopLimitOrderMsg(addr, GenerateOrderID(0, addr), Side.BUY, "XYZ-000_BNB", 1.1e8, 1.2e8, 1e8)
+	require.Contains(t, msg.ValidateBasic().Error(), "Order type is not supported yet:3")
+
+	upgrade.Mgr.AddUpgradeHeight(upgrade.DexEnhancements, -1)
+	defer resetChainVersion()
+	require.Nil(t, msg.ValidateBasic())
+	res := handleNewOrder(ctx, keeper, msg)
+	require.True(t, res.IsOK(), res.Log)
+	acc = am.GetAccount(ctx, addr)
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 1.2e8)}, acc.(commontypes.NamedAccount).GetLockedCoins())
+	buys, _ := keeper.engines["XYZ-000_BNB"].Book.GetAllLevels()
+	require.Len(t, buys, 0)
+
+	res = handleCancelOrder(ctx, keeper, NewCancelOrderMsg(addr, "XYZ-000_BNB", msg.Id))
+	require.True(t, res.IsOK(), res.Log)
+	acc = am.GetAccount(ctx, addr)
+	require.Equal(t, sdk.Coins(nil), acc.(commontypes.NamedAccount).GetLockedCoins())
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 100e8-2e4)}, acc.GetCoins())
+	_, ok := keeper.OrderExists("XYZ-000_BNB", msg.Id)
+	require.False(t, ok)
+	require.Equal(t, OrderChanges{
+		{msg.Id, StopPlaced, "", nil},
+		{msg.Id, Canceled, "BNB:20000", nil},
+	}, keeper.GetAllOrderChanges())
+	fees.Pool.Clear()
+}
+
+func TestHandler_MarketOrder(t *testing.T) {
+	ctx, am, keeper := setup()
+	keeper.FeeManager.UpdateConfig(NewTestFeeConfig())
+	_, buyer := testutils.NewAccount(ctx, am, 100e8)
+	_, seller := testutils.NewAccount(ctx, am, 0)
+	buyerAddr, sellerAddr := buyer.GetAddress(), seller.GetAddress()
+	seller.SetCoins(sdk.Coins{sdk.NewCoin("XYZ-000", 10e8)})
+	am.SetAccount(ctx, seller)
+	pair := types.NewTradingPair("XYZ-000", "BNB", 1e8)
+	err := keeper.PairMapper.AddTradingPair(ctx, pair)
+	require.NoError(t, err)
+	keeper.AddEngine(pair)
+	ctx = ctx.WithValue(baseapp.TxHashKey, "000001")
+
+	sellMsg := NewNewOrderMsg(sellerAddr, GenerateOrderID(0, sellerAddr), Side.SELL, "XYZ-000_BNB", 1e8, 1e8)
+	res := handleNewOrder(ctx, keeper, sellMsg)
+	require.True(t, res.IsOK(), res.Log)
+
+	// market buy locks the quote asset at the price limit of the engine
+	buyMsg := NewMarketOrderMsg(buyerAddr, GenerateOrderID(0, buyerAddr), Side.BUY, "XYZ-000_BNB", 2e8)
+	require.Contains(t, buyMsg.ValidateBasic().Error(), "Order type is not supported yet:1")
+	// nor is it priced by the handler
+	res = handleNewOrder(ctx, keeper, buyMsg)
+	require.False(t, res.IsOK())
+	require.Contains(t, res.Log, "price(0) is not rounded to tickSize")
+
+	upgrade.Mgr.AddUpgradeHeight(upgrade.DexEnhancements, -1)
+	defer resetChainVersion()
+	require.Nil(t, buyMsg.ValidateBasic())
+	res = handleNewOrder(ctx, keeper, buyMsg)
+	require.True(t, res.IsOK(), res.Log)
+	marketPrice, err := keeper.engines["XYZ-000_BNB"].MarketOrderPrice(Side.BUY, pair.TickSize.ToInt64())
+	require.NoError(t, err)
+	require.Equal(t, int64(1.05e8), marketPrice)
+	buyer = am.GetAccount(ctx, buyerAddr)
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 2*marketPrice)}, buyer.(commontypes.NamedAccount).GetLockedCoins())
+
+	keeper.MatchAndAllocateSymbols(ctx, nil, false)
+
+	// the filled part trades within the price limit and the rest expires as IOC without extra fee
+	tradePrice := keeper.engines["XYZ-000_BNB"].LastTradePrice
+	require.True(t, tradePrice <= marketPrice)
+	buyer = am.GetAccount(ctx, buyerAddr)
+	require.Equal(t, sdk.Coins(nil), buyer.(commontypes.NamedAccount).GetLockedCoins())
+	require.Equal(t, int64(1e8), buyer.GetCoins().AmountOf("XYZ-000"))
+	require.Equal(t, 100e8-tradePrice-tradePrice/2000, buyer.GetCoins().AmountOf("BNB"))
+	_, ok := keeper.OrderExists("XYZ-000_BNB", buyMsg.Id)
+	require.False(t, ok)
+	fees.Pool.Clear()
+}
+
+func TestHandler_PostOnlyOrder(t *testing.T) {
+	upgrade.Mgr.AddUpgradeHeight(upgrade.BEP19, -1)
+	defer resetChainVersion()
+	ctx, am, keeper := setup()
+	keeper.EnablePublish()
+	keeper.FeeManager.UpdateConfig(NewTestFeeConfig())
+	_, buyer := testutils.NewAccount(ctx, am, 100e8)
+	_, maker := testutils.NewAccount(ctx, am, 100e8)
+	_, seller := testutils.NewAccount(ctx, am, 0)
+	buyerAddr, makerAddr, sellerAddr := buyer.GetAddress(), maker.GetAddress(), seller.GetAddress()
+	seller.SetCoins(sdk.Coins{sdk.NewCoin("XYZ-000", 10e8)})
+	am.SetAccount(ctx, seller)
+	pair := types.NewTradingPair("XYZ-000", "BNB", 1e8)
+	err := keeper.PairMapper.AddTradingPair(ctx, pair)
+	require.NoError(t, err)
+	keeper.AddEngine(pair)
+	ctx = ctx.WithValue(baseapp.TxHashKey, "000001")
+
+	// the resting sell order becomes a maker after the first match
+	ctx = ctx.WithBlockHeader(abci.Header{Height: 1})
+	sellMsg := NewNewOrderMsg(sellerAddr, GenerateOrderID(0, sellerAddr), Side.SELL, "XYZ-000_BNB", 1e8, 1e8)
+	res := handleNewOrder(ctx, keeper, sellMsg)
+	require.True(t, res.IsOK(), res.Log)
+	keeper.MatchAndAllocateSymbols(ctx, nil, false)
+	keeper.ClearOrderChanges()
+
+	ctx = ctx.WithBlockHeader(abci.Header{Height: 2})
+	crossMsg := NewNewOrderMsg(buyerAddr, GenerateOrderID(0, buyerAddr), Side.BUY, "XYZ-000_BNB", 1e8, 1e8)
+	crossMsg.TimeInForce = TimeInForce.POST_ONLY
+	require.Contains(t, crossMsg.ValidateBasic().Error(), "TimeInForce is not supported yet:5")
+
+	upgrade.Mgr.AddUpgradeHeight(upgrade.DexEnhancements, -1)
+	require.Nil(t, crossMsg.ValidateBasic())
+	res = handleNewOrder(ctx, keeper, crossMsg)
+	require.True(t, res.IsOK(), res.Log)
+	restMsg := NewNewOrderMsg(makerAddr, GenerateOrderID(0, makerAddr), Side.BUY, "XYZ-000_BNB", 0.9e8, 1e8)
+	restMsg.TimeInForce = TimeInForce.POST_ONLY
+	res = handleNewOrder(ctx, keeper, restMsg)
+	require.True(t, res.IsOK(), res.Log)
+	keeper.MatchAndAllocateSymbols(ctx, nil, false)
+
+	// the crossing order is rejected without any fee, the other one rests in the book
+	require.Equal(t, OrderChanges{
+		{crossMsg.Id, Ack, "", nil},
+		{restMsg.Id, Ack, "", nil},
+		{crossMsg.Id, PostOnlyRejected, "", nil},
+	}, keeper.GetAllOrderChanges())
+	_, ok := keeper.OrderExists("XYZ-000_BNB", crossMsg.Id)
+	require.False(t, ok)
+	_, ok = keeper.OrderExists("XYZ-000_BNB", restMsg.Id)
+	require.True(t, ok)
+	_, ok = keeper.OrderExists("XYZ-000_BNB", sellMsg.Id)
+	require.True(t, ok)
+	buyer = am.GetAccount(ctx, buyerAddr)
+	require.Equal(t, sdk.Coins(nil), buyer.(commontypes.NamedAccount).GetLockedCoins())
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 100e8)}, buyer.GetCoins())
+	maker = am.GetAccount(ctx, makerAddr)
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 0.9e8)}, maker.(commontypes.NamedAccount).GetLockedCoins())
+	fees.Pool.Clear()
+}
+
+func TestHandler_FOKOrder(t *testing.T) {
+	upgrade.Mgr.AddUpgradeHeight(upgrade.BEP19, -1)
+	defer resetChainVersion()
+	ctx, am, keeper := setup()
+	keeper.FeeManager.UpdateConfig(NewTestFeeConfig())
+	_, buyer := testutils.NewAccount(ctx, am, 100e8)
+	_, seller := testutils.NewAccount(ctx, am, 0)
+	buyerAddr, sellerAddr := buyer.GetAddress(), seller.GetAddress()
+	seller.SetCoins(sdk.Coins{sdk.NewCoin("XYZ-000", 10e8)})
+	am.SetAccount(ctx, seller)
+	pair := types.NewTradingPair("XYZ-000", "BNB", 1e8)
+	err := keeper.PairMapper.AddTradingPair(ctx, pair)
+	require.NoError(t, err)
+	keeper.AddEngine(pair)
+	ctx = ctx.WithValue(baseapp.TxHashKey, "000001")
+
+	ctx = ctx.WithBlockHeader(abci.Header{Height: 1})
+	sellMsg := NewNewOrderMsg(sellerAddr, GenerateOrderID(0, sellerAddr), Side.SELL, "XYZ-000_BNB", 1e8, 1e8)
+	res := handleNewOrder(ctx, keeper, sellMsg)
+	require.True(t, res.IsOK(), res.Log)
+	keeper.MatchAndAllocateSymbols(ctx, nil, false)
+
+	// the FOK order asks for more than the book can fill, so it is killed with the IOC expire fee
+	ctx = ctx.WithBlockHeader(abci.Header{Height: 2})
+	fokMsg := NewNewOrderMsg(buyerAddr, GenerateOrderID(0, buyerAddr), Side.BUY, "XYZ-000_BNB", 1e8, 2e8)
+	fokMsg.TimeInForce = TimeInForce.FOK
+	require.Contains(t, fokMsg.ValidateBasic().Error(), "TimeInForce is not supported yet:4")
+
+	upgrade.Mgr.AddUpgradeHeight(upgrade.DexEnhancements, -1)
+	require.Nil(t, fokMsg.ValidateBasic())
+	res = handleNewOrder(ctx, keeper, fokMsg)
+	require.True(t, res.IsOK(), res.Log)
+	var expired []Transfer
+	keeper.MatchAndAllocateSymbols(ctx, func(tran Transfer) {
+		if tran.IsFOKExpire() {
+			expired = append(expired, tran)
+		}
+	}, false)
+
+	require.Len(t, expired, 1)
+	require.Equal(t, fokMsg.Id, expired[0].Oid)
+	_, ok := keeper.OrderExists("XYZ-000_BNB", fokMsg.Id)
+	require.False(t, ok)
+	buyer = am.GetAccount(ctx, buyerAddr)
+	require.Equal(t, sdk.Coins(nil), buyer.(commontypes.NamedAccount).GetLockedCoins())
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 100e8-1e4)}, buyer.GetCoins())
+	seller = am.GetAccount(ctx, sellerAddr)
+	require.Equal(t, sdk.Coins{sdk.NewCoin("XYZ-000", 1e8)}, seller.(commontypes.NamedAccount).GetLockedCoins())
+	fees.Pool.Clear()
+}
+
+func TestHandler_AmendOrder(t *testing.T) {
+	ctx, am, keeper := setup()
+	keeper.EnablePublish()
+	feeConfig := NewTestFeeConfig()
+	feeConfig.AmendFeeNative = 1e4
+	keeper.FeeManager.UpdateConfig(feeConfig)
+	_, acc := testutils.NewAccount(ctx, am, 100e8)
+	addr := acc.GetAddress()
+	pair := types.NewTradingPair("XYZ-000", "BNB", 1e8)
+	err := keeper.PairMapper.AddTradingPair(ctx, pair)
+	require.NoError(t, err)
+	keeper.AddEngine(pair)
+	ctx = ctx.WithValue(baseapp.TxHashKey, "000001")
+
+	msg1 := NewNewOrderMsg(addr, GenerateOrderID(0, addr), Side.BUY, "XYZ-000_BNB", 1e8, 2e8)
+	res := handleNewOrder(ctx, keeper, msg1)
+	require.True(t, res.IsOK(), res.Log)
+	acc = am.GetAccount(ctx, addr)
+	_ = acc.SetSequence(1)
+	am.SetAccount(ctx, acc)
+	msg2 := NewNewOrderMsg(addr, GenerateOrderID(1, addr), Side.BUY, "XYZ-000_BNB", 1e8, 1e8)
+	res = handleNewOrder(ctx, keeper, msg2)
+	require.True(t, res.IsOK(), res.Log)
+	keeper.ClearOrderChanges()
+
+	// reducing the quantity keeps the place of the order in its price level and unlocks the balance
+	res = handleAmendOrder(ctx, keeper, NewAmendOrderMsg(addr, "XYZ-000_BNB", msg1.Id, 1e8, 1e8))
+	require.True(t, res.IsOK(), res.Log)
+	pl := keeper.GetPriceLevel("XYZ-000_BNB", Side.BUY, 1e8)
+	require.Len(t, pl.Orders, 2)
+	require.Equal(t, msg1.Id, pl.Orders[0].Id)
+	require.Equal(t, int64(1e8), pl.Orders[0].Qty)
+	acc = am.GetAccount(ctx, addr)
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 2e8)}, acc.(commontypes.NamedAccount).GetLockedCoins())
+
+	// changing the price moves the order to the new price level and locks more balance
+	res = handleAmendOrder(ctx, keeper, NewAmendOrderMsg(addr, "XYZ-000_BNB", msg2.Id, 1.1e8, 1e8))
+	require.True(t, res.IsOK(), res.Log)
+	pl = keeper.GetPriceLevel("XYZ-000_BNB", Side.BUY, 1e8)
+	require.Len(t, pl.Orders, 1)
+	pl = keeper.GetPriceLevel("XYZ-000_BNB", Side.BUY, 1.1e8)
+	require.Len(t, pl.Orders, 1)
+	require.Equal(t, msg2.Id, pl.Orders[0].Id)
+	ord, ok := keeper.OrderExists("XYZ-000_BNB", msg2.Id)
+	require.True(t, ok)
+	require.Equal(t, int64(1.1e8), ord.Price)
+	acc = am.GetAccount(ctx, addr)
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 2.1e8)}, acc.(commontypes.NamedAccount).GetLockedCoins())
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 100e8-2.1e8-2e4)}, acc.GetCoins())
+
+	changes := keeper.GetAllOrderChanges()
+	require.Len(t, changes, 2)
+	require.Equal(t, Amended, changes[0].Tpe)
+	require.Equal(t, "BNB:10000", changes[0].SingleFee)
+	require.Equal(t, int64(2e8), changes[0].MsgForFailedTx.(OrderInfo).Quantity)
+	require.Equal(t, int64(1e8), changes[1].MsgForFailedTx.(OrderInfo).Price)
+
+	// amending to the current price and quantity is rejected
+	res = handleAmendOrder(ctx, keeper, NewAmendOrderMsg(addr, "XYZ-000_BNB", msg2.Id, 1.1e8, 1e8))
+	require.False(t, res.IsOK())
+
+	// an untriggered stop order can only be canceled
+	acc = am.GetAccount(ctx, addr)
+	_ = acc.SetSequence(2)
+	am.SetAccount(ctx, acc)
+	stopMsg := NewStopLimitOrderMsg(addr, GenerateOrderID(2, addr), Side.BUY, "XYZ-000_BNB", 1.2e8, 1.2e8, 1e8)
+	res = handleNewOrder(ctx, keeper, stopMsg)
+	require.True(t, res.IsOK(), res.Log)
+	res = handleAmendOrder(ctx, keeper, NewAmendOrderMsg(addr, "XYZ-000_BNB", stopMsg.Id, 1e8, 1e8))
+	require.False(t, res.IsOK())
+	require.Contains(t, res.Log, "untriggered stop order")
+	ord, ok = keeper.OrderExists("XYZ-000_BNB", stopMsg.Id)
+	require.True(t, ok)
+	require.Equal(t, int64(1.2e8), ord.Price)
+	fees.Pool.Clear()
+}
+
+func TestHandler_CancelAllOrders(t *testing.T) {
+	ctx, am, keeper := setup()
+	keeper.EnablePublish()
+	keeper.FeeManager.UpdateConfig(NewTestFeeConfig())
+	_, acc := testutils.NewAccount(ctx, am, 100e8)
+	addr := acc.GetAddress()
+	acc.SetCoins(sdk.Coins{sdk.NewCoin("BNB", 100e8), sdk.NewCoin("XYZ-000", 10e8)})
+	am.SetAccount(ctx, acc)
+	for _, base := range []string{"XYZ-000", "ABC-000"} {
+		pair := types.NewTradingPair(base, "BNB", 1e8)
+		err := keeper.PairMapper.AddTradingPair(ctx, pair)
+		require.NoError(t, err)
+		keeper.AddEngine(pair)
+	}
+	ctx = ctx.WithValue(baseapp.TxHashKey, "000001")
+
+	var msgs []NewOrderMsg
+	for i, msg := range []struct {
+		side   int8
+		symbol string
+		price  int64
+	}{
+		{Side.BUY, "XYZ-000_BNB", 1e8},
+		{Side.SELL, "XYZ-000_BNB", 2e8},
+		{Side.BUY, "ABC-000_BNB", 1e8},
+	} {
+		acc = am.GetAccount(ctx, addr)
+		_ = acc.SetSequence(int64(i))
+		am.SetAccount(ctx, acc)
+		newMsg := NewNewOrderMsg(addr, GenerateOrderID(int64(i), addr), msg.side, msg.symbol, msg.price, 1e8)
+		res := handleNewOrder(ctx, keeper, newMsg)
+		require.True(t, res.IsOK(), res.Log)
+		msgs = append(msgs, newMsg)
+	}
+	keeper.ClearOrderChanges()
+
+	// only the orders of the pair and the side are canceled
+	res := handleCancelAllOrders(ctx, keeper, NewCancelAllOrdersMsg(addr, "XYZ-000_BNB", Side.SELL))
+	require.True(t, res.IsOK(), res.Log)
+	_, ok := keeper.OrderExists("XYZ-000_BNB", msgs[1].Id)
+	require.False(t, ok)
+	_, ok = keeper.OrderExists("XYZ-000_BNB", msgs[0].Id)
+	require.True(t, ok)
+	acc = am.GetAccount(ctx, addr)
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 2e8)}, acc.(commontypes.NamedAccount).GetLockedCoins())
+
+	// the rest are canceled in the order of pair and id, and each of them is charged the cancel fee
+	keeper.ClearOrderChanges()
+	res = handleCancelAllOrders(ctx, keeper, NewCancelAllOrdersMsg(addr, "", 0))
+	require.True(t, res.IsOK(), res.Log)
+	require.Equal(t, OrderChanges{
+		{msgs[2].Id, Canceled, "BNB:20000", nil},
+		{msgs[0].Id, Canceled, "BNB:20000", nil},
+	}, keeper.GetAllOrderChanges())
+	acc = am.GetAccount(ctx, addr)
+	require.Equal(t, sdk.Coins(nil), acc.(commontypes.NamedAccount).GetLockedCoins())
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 100e8-3*2e4), sdk.NewCoin("XYZ-000", 10e8)}, acc.GetCoins())
+
+	// nothing left to cancel
+	res = handleCancelAllOrders(ctx, keeper, NewCancelAllOrdersMsg(addr, "", 0))
+	require.False(t, res.IsOK())
+	fees.Pool.Clear()
+}
+
+func TestHandler_TradingStatus(t *testing.T) {
+	ctx, am, keeper := setup()
+	keeper.FeeManager.UpdateConfig(NewTestFeeConfig())
+	_, acc := testutils.NewAccount(ctx, am, 100e8)
+	addr := acc.GetAddress()
+	for _, base := range []string{"XYZ-000", "ABC-000"} {
+		pair := types.NewTradingPair(base, "BNB", 1e8)
+		err := keeper.PairMapper.AddTradingPair(ctx, pair)
+		require.NoError(t, err)
+		keeper.AddEngine(pair)
+	}
+	ctx = ctx.WithValue(baseapp.TxHashKey, "000001")
+	handler := NewHandler(keeper)
+	notTrading := sdk.ToABCICode(types.DefaultCodespace, types.CodeTradingPairNotTrading)
+
+	var msgs []NewOrderMsg
+	for i, symbol := range []string{"XYZ-000_BNB", "XYZ-000_BNB", "ABC-000_BNB"} {
+		acc = am.GetAccount(ctx, addr)
+		_ = acc.SetSequence(int64(i))
+		am.SetAccount(ctx, acc)
+		msg := NewNewOrderMsg(addr, GenerateOrderID(int64(i), addr), Side.BUY, symbol, 1e8, 1e8)
+		res := handler(ctx, msg)
+		require.True(t, res.IsOK(), res.Log)
+		msgs = append(msgs, msg)
+	}
+	acc = am.GetAccount(ctx, addr)
+	_ = acc.SetSequence(3)
+	am.SetAccount(ctx, acc)
+
+	require.NoError(t, keeper.SetTradingStatus(ctx, "XYZ-000_BNB", types.TradingStatusCancelOnly, 1))
+	require.NoError(t, keeper.SetTradingStatus(ctx, "abc-000_bnb", types.TradingStatusHalted, 2))
+	require.Error(t, keeper.SetTradingStatus(ctx, "NNB-000_BNB", types.TradingStatusHalted, 3))
+	pair, err := keeper.PairMapper.GetTradingPair(ctx, "XYZ-000", "BNB")
+	require.NoError(t, err)
+	require.Equal(t, types.TradingStatusCancelOnly, pair.Status)
+
+	// no order can be placed or amended in the pairs not trading
+	res := handler(ctx, NewNewOrderMsg(addr, GenerateOrderID(3, addr), Side.BUY, "XYZ-000_BNB", 1e8, 1e8))
+	require.Equal(t, notTrading, res.Code)
+	require.Contains(t, res.Log, "Trading pair XYZ-000_BNB is cancel_only")
+	res = handler(ctx, NewAmendOrderMsg(addr, "XYZ-000_BNB", msgs[0].Id, 1e8, 2e8))
+	require.Equal(t, notTrading, res.Code)
+	res = handler(ctx, NewNewOrderMsg(addr, GenerateOrderID(3, addr), Side.BUY, "ABC-000_BNB", 1e8, 1e8))
+	require.Equal(t, notTrading, res.Code)
+
+	// the orders of the cancel-only pair can be canceled, but not the ones of the halted pair
+	res = handler(ctx, NewCancelOrderMsg(addr, "XYZ-000_BNB", msgs[0].Id))
+	require.True(t, res.IsOK(), res.Log)
+	res = handler(ctx, NewCancelOrderMsg(addr, "ABC-000_BNB", msgs[2].Id))
+	require.Equal(t, notTrading, res.Code)
+	res = handler(ctx, NewCancelAllOrdersMsg(addr, "ABC-000_BNB", 0))
+	require.Equal(t, notTrading, res.Code)
+	res = handler(ctx, NewCancelAllOrdersMsg(addr, "", 0))
+	require.True(t, res.IsOK(), res.Log)
+	_, ok := keeper.OrderExists("XYZ-000_BNB", msgs[1].Id)
+	require.False(t, ok)
+	_, ok = keeper.OrderExists("ABC-000_BNB", msgs[2].Id)
+	require.True(t, ok)
+
+	// the pair can be traded again after it is resumed
+	require.NoError(t, keeper.SetTradingStatus(ctx, "XYZ-000_BNB", types.TradingStatusTrading, 4))
+	require.Equal(t, types.TradingStatusTrading, keeper.GetTradingStatus("XYZ-000_BNB"))
+	res = handler(ctx, NewNewOrderMsg(addr, GenerateOrderID(3, addr), Side.BUY, "XYZ-000_BNB", 1e8, 1e8))
+	require.True(t, res.IsOK(), res.Log)
+	fees.Pool.Clear()
+}
+
+func TestHandler_SelfTradePrevention(t *testing.T) {
+	upgrade.Mgr.AddUpgradeHeight(upgrade.BEP19, -1)
+	defer resetChainVersion()
+	ctx, am, keeper := setup()
+	keeper.EnablePublish()
+	keeper.FeeManager.UpdateConfig(NewTestFeeConfig())
+	keeper.SetSelfTradePrevention(me.STPDecrement)
+	_, trader := testutils.NewAccount(ctx, am, 0)
+	_, buyer := testutils.NewAccount(ctx, am, 100e8)
+	traderAddr, buyerAddr := trader.GetAddress(), buyer.GetAddress()
+	trader.SetCoins(sdk.Coins{sdk.NewCoin("BNB", 100e8), sdk.NewCoin("XYZ-000", 10e8)})
+	am.SetAccount(ctx, trader)
+	pair := types.NewTradingPair("XYZ-000", "BNB", 1e8)
+	err := keeper.PairMapper.AddTradingPair(ctx, pair)
+	require.NoError(t, err)
+	keeper.AddEngine(pair)
+	ctx = ctx.WithValue(baseapp.TxHashKey, "000001")
+
+	ctx = ctx.WithBlockHeader(abci.Header{Height: 1})
+	sellMsg := NewNewOrderMsg(traderAddr, GenerateOrderID(0, traderAddr), Side.SELL, "XYZ-000_BNB", 1e8, 2e8)
+	res := handleNewOrder(ctx, keeper, sellMsg)
+	require.True(t, res.IsOK(), res.Log)
+	keeper.MatchAndAllocateSymbols(ctx, nil, false)
+	keeper.ClearOrderChanges()
+
+	// the buy order of the same trader would trade with its own sell order
+	ctx = ctx.WithBlockHeader(abci.Header{Height: 2})
+	trader = am.GetAccount(ctx, traderAddr)
+	_ = trader.SetSequence(1)
+	am.SetAccount(ctx, trader)
+	selfBuyMsg := NewNewOrderMsg(traderAddr, GenerateOrderID(1, traderAddr), Side.BUY, "XYZ-000_BNB", 1e8, 1e8)
+	res = handleNewOrder(ctx, keeper, selfBuyMsg)
+	require.True(t, res.IsOK(), res.Log)
+	buyMsg := NewNewOrderMsg(buyerAddr, GenerateOrderID(0, buyerAddr), Side.BUY, "XYZ-000_BNB", 1e8, 2e8)
+	res = handleNewOrder(ctx, keeper, buyMsg)
+	require.True(t, res.IsOK(), res.Log)
+	var prevented []Transfer
+	keeper.MatchAndAllocateSymbols(ctx, func(tran Transfer) {
+		if tran.IsSelfTradePrevented() {
+			prevented = append(prevented, tran)
+		}
+	}, false)
+
+	// both orders are decremented by 1e8, the buy order is canceled and the rest of the sell order trades with the other buyer
+	require.Len(t, prevented, 2)
+	changes := keeper.GetAllOrderChanges()
+	require.Contains(t, changes, OrderChange{selfBuyMsg.Id, SelfTradePrevented, "", nil})
+	_, ok := keeper.OrderExists("XYZ-000_BNB", selfBuyMsg.Id)
+	require.False(t, ok)
+	_, ok = keeper.OrderExists("XYZ-000_BNB", sellMsg.Id)
+	require.False(t, ok)
+	buyOrd, ok := keeper.OrderExists("XYZ-000_BNB", buyMsg.Id)
+	require.True(t, ok)
+	require.Equal(t, int64(1e8), buyOrd.CumQty)
+	trader = am.GetAccount(ctx, traderAddr)
+	require.Equal(t, sdk.Coins(nil), trader.(commontypes.NamedAccount).GetLockedCoins())
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 101e8-5e4), sdk.NewCoin("XYZ-000", 9e8)}, trader.GetCoins())
+	fees.Pool.Clear()
+}
+
+func TestHandler_CircuitBreaker(t *testing.T) {
+	upgrade.Mgr.AddUpgradeHeight(upgrade.BEP19, -1)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.DexEnhancements, -1)
+	defer resetChainVersion()
+	ctx, am, keeper := setup()
+	keeper.FeeManager.UpdateConfig(NewTestFeeConfig())
+	require.NoError(t, keeper.applyDexParam(&CircuitBreakerParam{Band: 3e4, Window: 1, HaltBlocks: 2}))
+	require.Equal(t, CircuitBreakerConfig{Band: 3e4, Window: 1, HaltBlocks: 2}, keeper.GetCircuitBreakerConfig())
+	pair := types.NewTradingPair("XYZ-000", "BNB", 1e8)
+	err := keeper.PairMapper.AddTradingPair(ctx, pair)
+	require.NoError(t, err)
+	keeper.AddEngine(pair)
+	keeper.recentPrices[pair.GetSymbol()] = utils.NewFixedSizedRing(numPricesStored).Push(int64(1e8))
+	ctx = ctx.WithValue(baseapp.TxHashKey, "000001")
+	placeOrder := func(side int8, price int64, timeInForce int8) NewOrderMsg {
+		_, acc := testutils.NewAccount(ctx, am, 100e8)
+		acc.SetCoins(acc.GetCoins().Plus(sdk.Coins{sdk.NewCoin("XYZ-000", 10e8)}))
+		am.SetAccount(ctx, acc)
+		msg := NewNewOrderMsg(acc.GetAddress(), GenerateOrderID(0, acc.GetAddress()), side, pair.GetSymbol(), price, 1e8)
+		msg.TimeInForce = timeInForce
+		res := handleNewOrder(ctx, keeper, msg)
+		require.True(t, res.IsOK(), res.Log)
+		return msg
+	}
+
+	// the concluded price moves 4% against the recent price, which trips the circuit breaker
+	ctx = ctx.WithBlockHeader(abci.Header{Height: 10})
+	placeOrder(Side.SELL, 1.04e8, TimeInForce.GTE)
+	placeOrder(Side.BUY, 1.04e8, TimeInForce.GTE)
+	keeper.MatchAndAllocateSymbols(ctx, nil, false)
+	require.Equal(t, int64(1.04e8), keeper.engines[pair.GetSymbol()].LastTradePrice)
+	trip := &CircuitBreakerTrip{Symbol: pair.GetSymbol(), TripHeight: 10, ResumeHeight: 13, TripPrice: 1.04e8}
+	require.Equal(t, trip, keeper.GetCircuitBreakerTrip(pair.GetSymbol(), 11))
+	require.True(t, keeper.IsMatchSuspended(pair.GetSymbol(), 12))
+	require.False(t, keeper.IsMatchSuspended(pair.GetSymbol(), 13))
+
+	// the orders are accepted but not matched while suspended, except the IOC orders which are expired
+	ctx = ctx.WithBlockHeader(abci.Header{Height: 11})
+	sellMsg := placeOrder(Side.SELL, 1.05e8, TimeInForce.GTE)
+	buyMsg := placeOrder(Side.BUY, 1.05e8, TimeInForce.GTE)
+	iocMsg := placeOrder(Side.BUY, 1.05e8, TimeInForce.IOC)
+	keeper.MatchAndAllocateSymbols(ctx, nil, false)
+	_, ok := keeper.OrderExists(pair.GetSymbol(), sellMsg.Id)
+	require.True(t, ok)
+	_, ok = keeper.OrderExists(pair.GetSymbol(), buyMsg.Id)
+	require.True(t, ok)
+	_, ok = keeper.OrderExists(pair.GetSymbol(), iocMsg.Id)
+	require.False(t, ok)
+	require.Equal(t, int64(1.04e8), keeper.engines[pair.GetSymbol()].LastTradePrice)
+
+	// the trips are saved in the store
+	trips := keeper.circuitBreakerTrips
+	keeper.initCircuitBreakerTrips(ctx)
+	require.Equal(t, trips, keeper.circuitBreakerTrips)
+
+	// the accumulated orders are matched at the resume height without any new order
+	require.Empty(t, keeper.SelectSymbolsToMatch(12, false))
+	require.Equal(t, []string{pair.GetSymbol()}, keeper.SelectSymbolsToMatch(13, false))
+	ctx = ctx.WithBlockHeader(abci.Header{Height: 13})
+	keeper.MatchAndAllocateSymbols(ctx, nil, false)
+	_, ok = keeper.OrderExists(pair.GetSymbol(), buyMsg.Id)
+	require.False(t, ok)
+	trip.ResumePrice = 1.05e8
+	require.Equal(t, trip, keeper.GetCircuitBreakerTrip(pair.GetSymbol(), 14))
+
+	// the recent prices before the resume are replaced by the resume price
+	ctx = ctx.WithBlockHeader(abci.Header{Height: 14})
+	placeOrder(Side.SELL, 1.06e8, TimeInForce.GTE)
+	placeOrder(Side.BUY, 1.06e8, TimeInForce.GTE)
+	keeper.MatchAndAllocateSymbols(ctx, nil, false)
+	require.Equal(t, int64(1.06e8), keeper.engines[pair.GetSymbol()].LastTradePrice)
+	require.False(t, keeper.IsMatchSuspended(pair.GetSymbol(), 15))
+
+	// the trip is kept until enough recent prices are stored after the resume
+	keeper.PruneCircuitBreakerTrips(ctx, 14)
+	require.Equal(t, trip, keeper.GetCircuitBreakerTrip(pair.GetSymbol(), 15))
+	keeper.PruneCircuitBreakerTrips(ctx, pricesStoreEvery)
+	require.Nil(t, keeper.GetCircuitBreakerTrip(pair.GetSymbol(), pricesStoreEvery+1))
+	keeper.initCircuitBreakerTrips(ctx)
+	require.Empty(t, keeper.circuitBreakerTrips)
+	fees.Pool.Clear()
+}
+
+func TestHandler_OrderSizeLimits(t *testing.T) {
+	ctx, am, keeper := setup()
+	keeper.FeeManager.UpdateConfig(NewTestFeeConfig())
+	_, acc := testutils.NewAccount(ctx, am, 100e8)
+	addr := acc.GetAddress()
+	pair := types.NewTradingPair("XYZ-000", "BNB", 1e8)
+	err := keeper.PairMapper.AddTradingPair(ctx, pair)
+	require.NoError(t, err)
+	keeper.AddEngine(pair)
+	minNotional, maxQuantity := int64(1e8), int64(5e8)
+	err = keeper.SetOrderSizeLimits(ctx, "XYZ-000_BNB", &minNotional, &maxQuantity)
+	require.NoError(t, err)
+	ctx = ctx.WithValue(baseapp.TxHashKey, "000001")
+
+	msg := NewNewOrderMsg(addr, GenerateOrderID(0, addr), Side.BUY, "XYZ-000_BNB", 1e8, 0.5e8)
+	err = validateOrder(ctx, keeper, acc, msg)
+	require.EqualError(t, err, "notional of the order is less than the minimum notional(100000000) of XYZ-000_BNB")
+	msg.Quantity = 6e8
+	err = validateOrder(ctx, keeper, acc, msg)
+	require.EqualError(t, err, "quantity(600000000) is greater than the maximum quantity(500000000) of XYZ-000_BNB")
+	msg.Quantity = 2e8
+	res := handleNewOrder(ctx, keeper, msg)
+	require.True(t, res.IsOK(), res.Log)
+
+	// the limits apply to the amended price and quantity as well
+	res = handleAmendOrder(ctx, keeper, NewAmendOrderMsg(addr, "XYZ-000_BNB", msg.Id, 0.4e8, 2e8))
+	require.False(t, res.IsOK())
+	require.Contains(t, res.Log, "less than the minimum notional")
+	res = handleAmendOrder(ctx, keeper, NewAmendOrderMsg(addr, "XYZ-000_BNB", msg.Id, 1e8, 6e8))
+	require.False(t, res.IsOK())
+	require.Contains(t, res.Log, "greater than the maximum quantity")
+
+	// 0 removes the limit
+	minNotional = 0
+	err = keeper.SetOrderSizeLimits(ctx, "XYZ-000_BNB", &minNotional, nil)
+	require.NoError(t, err)
+	pair, err = keeper.PairMapper.GetTradingPair(ctx, "XYZ-000", "BNB")
+	require.NoError(t, err)
+	require.Equal(t, utils.Fixed8(0), pair.MinNotional)
+	require.Equal(t, utils.Fixed8(5e8), pair.MaxQuantity)
+	res = handleAmendOrder(ctx, keeper, NewAmendOrderMsg(addr, "XYZ-000_BNB", msg.Id, 0.4e8, 2e8))
+	require.True(t, res.IsOK(), res.Log)
+
+	err = keeper.SetOrderSizeLimits(ctx, "ABC-000_BNB", &minNotional, nil)
+	require.EqualError(t, err, "trading pair ABC-000_BNB does not exist")
+	fees.Pool.Clear()
+}
+
+func TestHandler_OrderLimits(t *testing.T) {
+	defer resetChainVersion()
+	ctx, am, keeper := setup()
+	keeper.FeeManager.UpdateConfig(NewTestFeeConfig())
+	require.NoError(t, keeper.applyDexParam(&OrderLimitsParam{MaxOpenOrdersPerPair: 2, MaxOpenOrders: 3}))
+	require.Equal(t, OrderLimitsConfig{MaxOpenOrdersPerPair: 2, MaxOpenOrders: 3}, keeper.GetOrderLimitsConfig())
+	for _, pair := range []types.TradingPair{types.NewTradingPair("XYZ-000", "BNB", 1e8), types.NewTradingPair("ABC-000", "BNB", 1e8)} {
+		err := keeper.PairMapper.AddTradingPair(ctx, pair)
+		require.NoError(t, err)
+		keeper.AddEngine(pair)
+	}
+	ctx = ctx.WithValue(baseapp.TxHashKey, "000001").WithBlockHeader(abci.Header{Height: 10})
+	placeOrder := func(ctx sdk.Context, addr sdk.AccAddress, symbol string) sdk.Result {
+		acc := am.GetAccount(ctx, addr)
+		msg := NewNewOrderMsg(addr, GenerateOrderID(acc.GetSequence(), addr), Side.BUY, symbol, 1e8, 1e8)
+		res := handleNewOrder(ctx, keeper, msg)
+		if res.IsOK() {
+			_ = acc.SetSequence(acc.GetSequence() + 1)
+			am.SetAccount(ctx, acc)
+		}
+		return res
+	}
+	orderLimitExceeded := sdk.ToABCICode(types.DefaultCodespace, types.CodeOrderLimitExceeded)
+
+	// the orders are not limited before the upgrade
+	_, acc := testutils.NewAccount(ctx, am, 100e8)
+	addr := acc.GetAddress()
+	for i := 0; i < 3; i++ {
+		res := placeOrder(ctx, addr, "XYZ-000_BNB")
+		require.True(t, res.IsOK(), res.Log)
+	}
+
+	// the open orders are limited in each pair and in all the pairs
+	upgrade.Mgr.AddUpgradeHeight(upgrade.DexEnhancements, -1)
+	_, acc = testutils.NewAccount(ctx, am, 100e8)
+	addr = acc.GetAddress()
+	for i := 0; i < 2; i++ {
+		res := placeOrder(ctx, addr, "XYZ-000_BNB")
+		require.True(t, res.IsOK(), res.Log)
+	}
+	res := placeOrder(ctx, addr, "XYZ-000_BNB")
+	require.Equal(t, orderLimitExceeded, res.Code)
+	require.Contains(t, res.Log, "the number of open orders in XYZ-000_BNB is limited to 2")
+	res = placeOrder(ctx, addr, "ABC-000_BNB")
+	require.True(t, res.IsOK(), res.Log)
+	res = placeOrder(ctx, addr, "ABC-000_BNB")
+	require.Equal(t, orderLimitExceeded, res.Code)
+	require.Contains(t, res.Log, "the number of open orders is limited to 3")
+	require.Equal(t, store.OrderLimits{
+		MaxOpenOrdersPerPair: 2,
+		MaxOpenOrders:        3,
+		OpenOrders:           3,
+		OpenOrdersPerPair:    []store.PairOpenOrders{{Symbol: "ABC-000_BNB", Count: 1}, {Symbol: "XYZ-000_BNB", Count: 2}},
+	}, keeper.GetOrderLimits(addr))
+
+	// the new orders are limited in each block, apart for CheckTx and DeliverTx
+	keeper.SetOrderLimitsConfig(OrderLimitsConfig{MaxNewOrdersPerBlock: 2})
+	_, acc = testutils.NewAccount(ctx, am, 100e8)
+	addr = acc.GetAddress()
+	checkCtx := ctx.WithRunTxMode(sdk.RunTxModeCheck).WithBlockHeader(abci.Header{Height: 9})
+	for _, c := range []sdk.Context{ctx, checkCtx} {
+		for i := 0; i < 2; i++ {
+			res = placeOrder(c, addr, "XYZ-000_BNB")
+			require.True(t, res.IsOK(), res.Log)
+		}
+		res = placeOrder(c, addr, "ABC-000_BNB")
+		require.Equal(t, orderLimitExceeded, res.Code)
+		require.Contains(t, res.Log, "the number of new orders in a block is limited to 2")
+	}
+	res = placeOrder(ctx.WithBlockHeader(abci.Header{Height: 11}), addr, "XYZ-000_BNB")
+	require.True(t, res.IsOK(), res.Log)
+	res = placeOrder(checkCtx.WithBlockHeader(abci.Header{Height: 10}), addr, "XYZ-000_BNB")
+	require.True(t, res.IsOK(), res.Log)
+	fees.Pool.Clear()
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -14,6 +15,7 @@ import (
 	tmlog "github.com/tendermint/tendermint/libs/log"
 	tmstore "github.com/tendermint/tendermint/store"
 
+	"github.com/cosmos/cosmos-sdk/pubsub"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/fees"
 	"github.com/cosmos/cosmos-sdk/x/auth"
@@ -64,6 +66,22 @@ type DexKeeper struct {
 	poolSize                   uint // number of concurrent channels, counted in the pow of 2
 	cdc                        *wire.Codec
 	OrderKeepers               []DexOrderKeeper
+	selfTradePrevention        me.SelfTradePrevention
+	orderBookTypes             map[string]string                 // symbol -> order book implementation, the default one if absent
+	tradingStatuses            map[string]dexTypes.TradingStatus // symbol -> trading status, only the pairs not trading are kept
+	circuitBreaker             CircuitBreakerConfig
+	circuitBreakerTrips        map[string][]*CircuitBreakerTrip // symbol -> trips ordered by the trip height
+	klines                     *KlineStore                      // nil if the klines are not maintained
+	tickers                    *TickerStore
+	recentTrades               *RecentTradesStore   // nil if the recent trades are not maintained
+	orderHistory               *OrderHistoryStore   // nil if the order history is not maintained
+	tradeFeeTiers              map[string]int       // order ID -> fee tier applied to its trades in the last match, nil without the fee tiers
+	tradeRebates               map[string]sdk.Coins // trade key -> rebate paid to the maker in the last match
+	roundRebates               sdk.Coins            // rebates paid to the makers in the last match
+	orderLimits                OrderLimitsConfig
+	checkNewOrders             newOrderCounter // new orders checked into the mempool since the last commit
+	deliverNewOrders           newOrderCounter // new orders delivered in the current block
+	PbsbServer                 *pubsub.Server
 }
 
 func NewDexKeeper(key sdk.StoreKey, am auth.AccountKeeper, tradingPairMapper store.TradingPairMapper, codespace sdk.CodespaceType, concurrency uint, cdc *wire.Codec, collectOrderInfoForPublish bool) *DexKeeper {
@@ -85,6 +103,10 @@ func NewDexKeeper(key sdk.StoreKey, am auth.AccountKeeper, tradingPairMapper sto
 		CollectOrderInfoForPublish: collectOrderInfoForPublish,
 		engines:                    make(map[string]*me.MatchEng),
 		pairsType:                  make(map[string]SymbolPairType),
+		orderBookTypes:             make(map[string]string),
+		tradingStatuses:            make(map[string]dexTypes.TradingStatus),
+		circuitBreakerTrips:        make(map[string][]*CircuitBreakerTrip),
+		tickers:                    NewTickerStore(),
 		poolSize:                   concurrency,
 		cdc:                        cdc,
 		logger:                     logger,
@@ -93,6 +115,10 @@ func NewDexKeeper(key sdk.StoreKey, am auth.AccountKeeper, tradingPairMapper sto
 }
 
 func (kp *DexKeeper) Init(ctx sdk.Context, blockInterval, daysBack int, blockStore *tmstore.BlockStore, stateDB dbm.DB, lastHeight int64, txDecoder sdk.TxDecoder) {
+	// the governed params like the self-trade prevention decide how the replayed blocks are matched
+	kp.loadDexParams(ctx)
+	kp.initCircuitBreakerTrips(ctx)
+	kp.initTickers(ctx, blockInterval, daysBack, blockStore, stateDB, lastHeight, txDecoder)
 	kp.initOrderBook(ctx, blockInterval, daysBack, blockStore, stateDB, lastHeight, txDecoder)
 	kp.InitRecentPrices(ctx)
 }
@@ -105,6 +131,99 @@ func (kp *DexKeeper) SetBUSDSymbol(symbol string) {
 	BUSDSymbol = symbol
 }
 
+// SetSelfTradePrevention sets the self-trade prevention policy of all the match engines
+func (kp *DexKeeper) SetSelfTradePrevention(stp me.SelfTradePrevention) {
+	kp.selfTradePrevention = stp
+	for _, eng := range kp.engines {
+		eng.SelfTradePrevention = stp
+	}
+}
+
+// orderSenderFunc returns the sender of the orders of the pair for the self-trade prevention of its match engine
+func (kp *DexKeeper) orderSenderFunc(symbol string) me.OrderSenderFunc {
+	return func(id string) string {
+		if ord, ok := kp.OrderExists(symbol, id); ok {
+			return string(ord.Sender.Bytes())
+		}
+		return ""
+	}
+}
+
+// SetOrderBookType sets the order book implementation of the pair, the orders of an existing match engine
+// are moved to the new order book.
+func (kp *DexKeeper) SetOrderBookType(symbol string, bookType string) error {
+	symbol = strings.ToUpper(symbol)
+	book, err := me.NewOrderBook(bookType)
+	if err != nil {
+		return err
+	}
+	kp.orderBookTypes[symbol] = bookType
+	eng, ok := kp.engines[symbol]
+	if !ok {
+		return nil
+	}
+	buys, sells := eng.Book.GetAllLevels()
+	for i := range buys {
+		if err := book.InsertPriceLevel(&buys[i], me.BUYSIDE); err != nil {
+			return err
+		}
+	}
+	for i := range sells {
+		if err := book.InsertPriceLevel(&sells[i], me.SELLSIDE); err != nil {
+			return err
+		}
+	}
+	eng.Book = book
+	return nil
+}
+
+func (kp *DexKeeper) SetPbsbServer(server *pubsub.Server) {
+	kp.PbsbServer = server
+}
+
+// GetTradingStatus returns the trading status of the pair
+func (kp *DexKeeper) GetTradingStatus(symbol string) dexTypes.TradingStatus {
+	return kp.tradingStatuses[strings.ToUpper(symbol)]
+}
+
+// SetTradingStatus changes the trading status of the pair, which is requested by the proposal
+func (kp *DexKeeper) SetTradingStatus(ctx sdk.Context, symbol string, status dexTypes.TradingStatus, proposalId int64) error {
+	symbol = strings.ToUpper(symbol)
+	if _, ok := kp.engines[symbol]; !ok {
+		return fmt.Errorf("trading pair %s does not exist", symbol)
+	}
+	baseAsset, quoteAsset, err := dexUtils.TradingPair2Assets(symbol)
+	if err != nil {
+		return err
+	}
+	pair, err := kp.PairMapper.GetTradingPair(ctx, baseAsset, quoteAsset)
+	if err != nil {
+		return err
+	}
+	pair.Status = status
+	if err := kp.PairMapper.AddTradingPair(ctx, pair); err != nil {
+		return err
+	}
+	kp.setTradingStatus(symbol, status)
+
+	if kp.PbsbServer != nil {
+		kp.PbsbServer.Publish(dexTypes.MarketStatusEvent{
+			Symbol:     symbol,
+			Status:     status,
+			ProposalId: proposalId,
+		})
+	}
+	return nil
+}
+
+func (kp *DexKeeper) setTradingStatus(symbol string, status dexTypes.TradingStatus) {
+	if status == dexTypes.TradingStatusTrading {
+		delete(kp.tradingStatuses, symbol)
+	} else {
+		kp.tradingStatuses[symbol] = status
+	}
+}
+
 func (kp *DexKeeper) EnablePublish() {
 	kp.CollectOrderInfoForPublish = true
 	for i := range kp.OrderKeepers {
@@ -261,7 +380,14 @@ func (kp *DexKeeper) UpdateLotSize(symbol string, lotSize int64) {
 func (kp *DexKeeper) AddEngine(pair dexTypes.TradingPair) *me.MatchEng {
 	symbol := strings.ToUpper(pair.GetSymbol())
 	eng := CreateMatchEng(symbol, pair.ListPrice.ToInt64(), pair.LotSize.ToInt64())
+	eng.SelfTradePrevention = kp.selfTradePrevention
+	eng.OrderSender = kp.orderSenderFunc(symbol)
+	if bookType, ok := kp.orderBookTypes[symbol]; ok {
+		// the type has been validated in SetOrderBookType
+		eng.Book, _ = me.NewOrderBook(bookType)
+	}
 	kp.engines[symbol] = eng
+	kp.setTradingStatus(symbol, pair.Status)
 	pairType := PairType.BEP2
 	if dexUtils.IsMiniTokenTradingPair(symbol) {
 		pairType = PairType.MINI
@@ -285,16 +411,54 @@ func (kp *DexKeeper) AddOrder(info OrderInfo, isRecovery bool) (err error) {
 		return
 	}
 
+	if info.OrderType == OrderType.STOP_LIMIT {
+		// stop orders are parked outside the order book until triggered
+		kp.mustGetOrderKeeper(symbol).addStopOrder(symbol, info, isRecovery)
+		kp.logger.Debug("Added stop order", "symbol", symbol, "id", info.Id)
+		return nil
+	}
+
 	_, err = eng.Book.InsertOrder(info.Id, info.Side, info.CreatedHeight, info.Price, info.Quantity)
 	if err != nil {
 		return err
 	}
+	if info.TimeInForce == TimeInForce.FOK {
+		eng.AddFOKOrder(info.Id)
+	}
 
 	kp.mustGetOrderKeeper(symbol).addOrder(symbol, info, isRecovery)
 	kp.logger.Debug("Added orders", "symbol", symbol, "id", info.Id)
 	return nil
 }
 
+// PriceMarketOrder sets the price of a market order to the worst price allowed by the match engine, rounded to
+// the tickSize of the pair. The balance is locked against this price, and the surplus is refunded when the order
+// is filled or expired.
+func (kp *DexKeeper) PriceMarketOrder(ctx sdk.Context, msg NewOrderMsg) (NewOrderMsg, error) {
+	if msg.OrderType != OrderType.MARKET {
+		return msg, nil
+	}
+	symbol := strings.ToUpper(msg.Symbol)
+	eng, ok := kp.engines[symbol]
+	if !ok {
+		return msg, fmt.Errorf("match engine of symbol %s doesn't exist", symbol)
+	}
+	baseAsset, quoteAsset, err := dexUtils.TradingPair2Assets(symbol)
+	if err != nil {
+		return msg, err
+	}
+	pair, err := kp.PairMapper.GetTradingPair(ctx, baseAsset, quoteAsset)
+	if err != nil {
+		return msg, err
+	}
+	price, err := eng.MarketOrderPrice(msg.Side, pair.TickSize.ToInt64())
+	if err != nil {
+		return msg, err
+	}
+	msg.Price = price
+	return msg, nil
+}
+
 func orderNotFound(symbol, id string) error {
 	return fmt.Errorf("Failed to find order [%v] on symbol [%v]", id, symbol)
 }
@@ -314,12 +478,30 @@ func (kp *DexKeeper) RemoveOrder(id string, symbol string, postCancelHandler fun
 	return orderNotFound(symbol, id)
 }
 
+// AmendOrder changes the price and quantity of an open order, and returns the order before the amendment
+func (kp *DexKeeper) AmendOrder(msg AmendOrderMsg, height, timestamp int64) (OrderInfo, error) {
+	symbol := strings.ToUpper(msg.Symbol)
+	dexOrderKeeper, err := kp.getOrderKeeper(symbol)
+	if err != nil {
+		return OrderInfo{}, orderNotFound(symbol, msg.RefId)
+	}
+	origOrd, err := dexOrderKeeper.amendOrder(kp, symbol, msg, height, timestamp)
+	if err != nil {
+		return OrderInfo{}, err
+	}
+	kp.logger.Debug("Amended order", "symbol", symbol, "id", msg.RefId, "price", msg.Price, "qty", msg.Quantity)
+	return origOrd, nil
+}
+
 func (kp *DexKeeper) GetOrder(id string, symbol string, side int8, price int64) (ord me.OrderPart, err error) {
 	symbol = strings.ToUpper(symbol)
-	_, ok := kp.OrderExists(symbol, id)
+	info, ok := kp.OrderExists(symbol, id)
 	if !ok {
 		return me.OrderPart{}, orderNotFound(symbol, id)
 	}
+	if info.OrderType == OrderType.STOP_LIMIT {
+		return stopOrderPart(&info), nil
+	}
 	eng, ok := kp.engines[symbol]
 	if !ok {
 		return me.OrderPart{}, orderNotFound(symbol, id)
@@ -334,6 +516,16 @@ func (kp *DexKeeper) OrderExists(symbol, id string) (OrderInfo, bool) {
 	return OrderInfo{}, false
 }
 
+// isUntriggeredStopOrder returns true if the order is a stop order waiting for its stop price
+func (kp *DexKeeper) isUntriggeredStopOrder(symbol, id string) bool {
+	symbol = strings.ToUpper(symbol)
+	if dexOrderKeeper, err := kp.getOrderKeeper(symbol); err == nil {
+		_, ok := dexOrderKeeper.getStopOrdersForPair(symbol)[id]
+		return ok
+	}
+	return false
+}
+
 // channelHash() will choose a channel for processing by moding
 // the sum of the last 7 bytes of address by bucketNumber.
 // It may not be fully even.
@@ -349,13 +541,17 @@ func channelHash(accAddress sdk.AccAddress, bucketNumber int) int {
 
 func (kp *DexKeeper) SubscribeParamChange(hub *paramhub.Keeper) {
 	hub.SubscribeParamChange(
-		func(_ sdk.Context, iChange interface{}) {
+		func(ctx sdk.Context, iChange interface{}) {
 			switch change := iChange.(type) {
 			case []paramTypes.FeeParam:
 				feeConfig := ParamToFeeConfig(change)
 				if feeConfig != nil {
 					kp.FeeManager.UpdateConfig(*feeConfig)
 				}
+				if sdk.IsUpgrade(upgrade.DexEnhancements) {
+					kp.updateDexParams(ctx, change)
+				}
+				registerOrderFeeCalculators()
 			default:
 				kp.logger.Debug("Receive param changes that not interested.")
 			}
@@ -370,6 +566,8 @@ func (kp *DexKeeper) SubscribeParamChange(hub *paramhub.Keeper) {
 				} else {
 					panic("Genesis with no dex fee config ")
 				}
+				kp.updateDexParams(context, state.FeeGenesis)
+				registerOrderFeeCalculators()
 			default:
 				kp.logger.Debug("Receive param genesis state that not interested.")
 			}
@@ -383,33 +581,66 @@ func (kp *DexKeeper) SubscribeParamChange(hub *paramhub.Keeper) {
 				} else {
 					panic("Load with no dex fee config ")
 				}
+				kp.loadDexParams(context)
+				registerOrderFeeCalculators()
 			default:
 				kp.logger.Debug("Receive param load that not interested.")
 			}
 		})
 }
 
+// registerOrderFeeCalculators makes the amend order and cancel all orders txs themselves free, as the fee
+// calculators are reset by the param hub on every fee param update. Like the cancel fee, the amend fee
+// and the cancel fees of the orders are charged by the handler according to FeeConfig.
+func registerOrderFeeCalculators() {
+	fees.RegisterCalculator(RouteAmendOrder, fees.FreeFeeCalculator())
+	fees.RegisterCalculator(RouteCancelAllOrders, fees.FreeFeeCalculator())
+}
+
 func (kp *DexKeeper) GetOrderBookLevels(pair string, maxLevels int) (orderbook []store.OrderBookLevel, pendingMatch bool) {
 	orderbook = make([]store.OrderBookLevel, maxLevels)
 
 	i, j := 0, 0
 	if eng, ok := kp.engines[pair]; ok {
+		orderKeeper := kp.mustGetOrderKeeper(pair)
+		orders := orderKeeper.getAllOrdersForPair(pair)
 		// TODO: check considered bucket splitting?
 		eng.Book.ShowDepth(maxLevels, func(p *me.PriceLevel, levelIndex int) {
 			orderbook[i].BuyPrice = utils.Fixed8(p.Price)
-			orderbook[i].BuyQty = utils.Fixed8(p.TotalLeavesQty())
+			orderbook[i].BuyQty = utils.Fixed8(visibleLeavesQty(p, orders))
 			i++
 		}, func(p *me.PriceLevel, levelIndex int) {
 			orderbook[j].SellPrice = utils.Fixed8(p.Price)
-			orderbook[j].SellQty = utils.Fixed8(p.TotalLeavesQty())
+			orderbook[j].SellQty = utils.Fixed8(visibleLeavesQty(p, orders))
 			j++
 		})
-		roundOrders := kp.mustGetOrderKeeper(pair).getRoundOrdersForPair(pair)
+		roundOrders := orderKeeper.getRoundOrdersForPair(pair)
 		pendingMatch = len(roundOrders) > 0
 	}
 	return orderbook, pendingMatch
 }
 
+// GetIndicativePrice returns where the next auction of the pair would conclude, without changing the order book
+func (kp *DexKeeper) GetIndicativePrice(pair string) (store.IndicativePrice, error) {
+	eng, ok := kp.engines[pair]
+	if !ok {
+		return store.IndicativePrice{}, fmt.Errorf("trading pair %s does not exist", pair)
+	}
+	var price store.IndicativePrice
+	if match, ok := eng.IndicativeMatch(); ok {
+		price.Price = utils.Fixed8(match.Price)
+		price.Quantity = utils.Fixed8(match.ExecQty)
+		if match.Surplus > 0 {
+			price.Surplus = utils.Fixed8(match.Surplus)
+			price.SurplusSide = "BUY"
+		} else if match.Surplus < 0 {
+			price.Surplus = utils.Fixed8(-match.Surplus)
+			price.SurplusSide = "SELL"
+		}
+	}
+	return price, nil
+}
+
 func (kp *DexKeeper) GetOpenOrders(pair string, addr sdk.AccAddress) []store.OpenOrder {
 	if dexOrderKeeper, err := kp.getOrderKeeper(pair); err == nil {
 		return dexOrderKeeper.getOpenOrders(pair, addr)
@@ -417,6 +648,72 @@ func (kp *DexKeeper) GetOpenOrders(pair string, addr sdk.AccAddress) []store.Ope
 	return make([]store.OpenOrder, 0)
 }
 
+// GetAllOpenOrders returns the open orders of addr in all the BEP2 and mini pairs, sorted by pair and then by id
+func (kp *DexKeeper) GetAllOpenOrders(addr sdk.AccAddress) []store.OpenOrder {
+	res := make([]store.OpenOrder, 0)
+	for _, orderKeeper := range kp.OrderKeepers {
+		if orderKeeper.supportUpgradeVersion() {
+			res = append(res, orderKeeper.getOpenOrdersOfSender(addr)...)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Symbol != res[j].Symbol {
+			return res[i].Symbol < res[j].Symbol
+		}
+		return res[i].Id < res[j].Id
+	})
+	return res
+}
+
+// GetOpenOrderInfos returns the open orders of addr in the pair, or in all the pairs if pair is empty,
+// and of the side, or of both sides if side is 0. The orders are sorted by pair and then by id,
+// so the result can be used to change the state deterministically.
+func (kp *DexKeeper) GetOpenOrderInfos(addr sdk.AccAddress, pair string, side int8) []OrderInfo {
+	var orders []OrderInfo
+	if pair != "" {
+		pair = strings.ToUpper(pair)
+		if dexOrderKeeper, err := kp.getOrderKeeper(pair); err == nil {
+			orders = dexOrderKeeper.getOrdersOfSender(pair, addr)
+		}
+	} else {
+		for _, orderKeeper := range kp.OrderKeepers {
+			if orderKeeper.supportUpgradeVersion() {
+				orders = append(orders, orderKeeper.getOrdersOfSender("", addr)...)
+			}
+		}
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		si, sj := strings.ToUpper(orders[i].Symbol), strings.ToUpper(orders[j].Symbol)
+		if si != sj {
+			return si < sj
+		}
+		return orders[i].Id < orders[j].Id
+	})
+
+	res := make([]OrderInfo, 0)
+	for _, order := range orders {
+		if side == 0 || order.Side == side {
+			res = append(res, order)
+		}
+	}
+	return res
+}
+
+// getCancelableOrderInfos returns the open orders like GetOpenOrderInfos, except the ones of the halted pairs
+func (kp *DexKeeper) getCancelableOrderInfos(addr sdk.AccAddress, pair string, side int8) []OrderInfo {
+	orders := kp.GetOpenOrderInfos(addr, pair, side)
+	if len(kp.tradingStatuses) == 0 {
+		return orders
+	}
+	res := orders[:0]
+	for _, order := range orders {
+		if kp.tradingStatuses[strings.ToUpper(order.Symbol)] != dexTypes.TradingStatusHalted {
+			res = append(res, order)
+		}
+	}
+	return res
+}
+
 func (kp *DexKeeper) GetOrderBooks(maxLevels int) ChangedPriceLevelsMap {
 	var res = make(ChangedPriceLevelsMap)
 	for pair, eng := range kp.engines {
@@ -424,17 +721,67 @@ func (kp *DexKeeper) GetOrderBooks(maxLevels int) ChangedPriceLevelsMap {
 		sells := make(map[int64]int64)
 		res[pair] = ChangedPriceLevelsPerSymbol{buys, sells}
 
+		orders := kp.mustGetOrderKeeper(pair).getAllOrdersForPair(pair)
 		// TODO: check considered bucket splitting?
 		eng.Book.ShowDepth(maxLevels, func(p *me.PriceLevel, levelIndex int) {
-			buys[p.Price] = p.TotalLeavesQty()
+			buys[p.Price] = visibleLeavesQty(p, orders)
 		}, func(p *me.PriceLevel, levelIndex int) {
-			sells[p.Price] = p.TotalLeavesQty()
+			sells[p.Price] = visibleLeavesQty(p, orders)
 		})
 	}
 
 	return res
 }
 
+// GetOrderBookL3 returns the resting orders of the price levels in [offset, offset+limit) of each side,
+// in the time priority of each price level.
+func (kp *DexKeeper) GetOrderBookL3(pair string, offset, limit int) (buys, sells []store.OrderBookL3Level) {
+	buys, sells = make([]store.OrderBookL3Level, 0), make([]store.OrderBookL3Level, 0)
+	eng, ok := kp.engines[pair]
+	if !ok {
+		return
+	}
+	orders := kp.mustGetOrderKeeper(pair).getAllOrdersForPair(pair)
+	toLevel := func(p *me.PriceLevel) store.OrderBookL3Level {
+		level := store.OrderBookL3Level{
+			Price:  utils.Fixed8(p.Price),
+			Orders: make([]store.OrderBookL3Order, len(p.Orders)),
+		}
+		for i, o := range p.Orders {
+			level.Orders[i] = store.OrderBookL3Order{Id: o.Id, Quantity: utils.Fixed8(o.LeavesQty()), CreatedHeight: o.Time}
+			if ord, ok := orders[o.Id]; ok {
+				level.Orders[i].Quantity = utils.Fixed8(ord.VisibleQty(o.CumQty))
+				level.Orders[i].CreatedHeight = ord.CreatedHeight
+			}
+		}
+		return level
+	}
+	eng.Book.ShowDepth(offset+limit, func(p *me.PriceLevel, levelIndex int) {
+		if levelIndex >= offset {
+			buys = append(buys, toLevel(p))
+		}
+	}, func(p *me.PriceLevel, levelIndex int) {
+		if levelIndex >= offset {
+			sells = append(sells, toLevel(p))
+		}
+	})
+	return
+}
+
+// visibleLeavesQty returns the leaves quantity of the price level shown to the public,
+// in which the hidden reserve of the iceberg orders is left out.
+func visibleLeavesQty(p *me.PriceLevel, orders map[string]*OrderInfo) int64 {
+	var total int64
+	for _, o := range p.Orders {
+		if ord, ok := orders[o.Id]; ok {
+			total += ord.VisibleQty(o.CumQty)
+		} else {
+			total += o.LeavesQty()
+		}
+	}
+	return total
+}
+
 func (kp *DexKeeper) GetPriceLevel(pair string, side int8, price int64) *me.PriceLevel {
 	if eng, ok := kp.engines[pair]; ok {
 		return eng.Book.GetPriceLevel(price, side)
@@ -526,9 +873,10 @@ func (kp *DexKeeper) StoreTradePrices(ctx sdk.Context) {
 }
 
 func (kp *DexKeeper) allocate(ctx sdk.Context, tranCh <-chan Transfer, postAllocateHandler func(tran Transfer)) (
-	sdk.Fee, map[string]*sdk.Fee) {
+	sdk.Fee, map[string]*sdk.Fee, map[string]int, TradeTransfers) {
 	if !sdk.IsUpgrade(upgrade.BEP19) {
-		return kp.allocateBeforeGalileo(ctx, tranCh, postAllocateHandler)
+		totalFee, feesPerAcc := kp.allocateBeforeGalileo(ctx, tranCh, postAllocateHandler)
+		return totalFee, feesPerAcc, nil, nil
 	}
 
 	// use string of the addr as the key since map makes a fast path for string key.
@@ -536,8 +884,6 @@ func (kp *DexKeeper) allocate(ctx sdk.Context, tranCh <-chan Transfer, postAlloc
 	tradeTransfers := make(map[string]TradeTransfers)
 	// expire fee is fixed, so we count by numbers.
 	expireTransfers := make(map[string]ExpireTransfers)
-	// we need to distinguish different expire event, IOCExpire or Expire. only one of the two will exist.
-	var expireEventType transferEventType
 	var totalFee sdk.Fee
 	for tran := range tranCh {
 		kp.doTransfer(ctx, &tran)
@@ -546,7 +892,6 @@ func (kp *DexKeeper) allocate(ctx sdk.Context, tranCh <-chan Transfer, postAlloc
 			// need a copy of tran as it is reused
 			tranCp := tran
 			if tran.IsExpiredWithFee() {
-				expireEventType = tran.eventType
 				if _, ok := expireTransfers[addrStr]; !ok {
 					expireTransfers[addrStr] = ExpireTransfers{&tranCp}
 				} else {
@@ -559,7 +904,7 @@ func (kp *DexKeeper) allocate(ctx sdk.Context, tranCh <-chan Transfer, postAlloc
 					tradeTransfers[addrStr] = append(tradeTransfers[addrStr], &tranCp)
 				}
 			}
-		} else if tran.IsExpire() {
+		} else if tran.IsExpire() || tran.IsSelfTradePrevented() {
 			if postAllocateHandler != nil {
 				postAllocateHandler(tran)
 			}
@@ -567,9 +912,25 @@ func (kp *DexKeeper) allocate(ctx sdk.Context, tranCh <-chan Transfer, postAlloc
 	}
 
 	feesPerAcc := make(map[string]*sdk.Fee)
+	var feeTiers map[string]int
+	// the rebates are paid once the taker fees of all the channels are calculated
+	var makers TradeTransfers
 	for addrStr, trans := range tradeTransfers {
 		addr := sdk.AccAddress(addrStr)
 		acc := kp.am.GetAccount(ctx, addr)
+		// the volume is only tracked for the fee tiers, so the state is not changed without them
+		if kp.FeeManager.HasFeeTiers() {
+			if feeTiers == nil {
+				feeTiers = make(map[string]int)
+			}
+			volume := kp.getTradingVolume(ctx, addr)
+			tier := kp.FeeManager.FeeTier(volume.total())
+			for _, tran := range trans {
+				tran.feeTier = tier
+				feeTiers[tran.Oid] = tier
+			}
+			kp.addTradingVolume(ctx, addr, volume, kp.FeeManager.CalcTradesVolume(trans, kp.engines))
+		}
 		fees := kp.FeeManager.CalcTradesFee(acc.GetCoins(), trans, kp.engines)
 		if !fees.IsEmpty() {
 			feesPerAcc[addrStr] = &fees
@@ -577,13 +938,20 @@ func (kp *DexKeeper) allocate(ctx sdk.Context, tranCh <-chan Transfer, postAlloc
 			kp.am.SetAccount(ctx, acc)
 			totalFee.AddFee(fees)
 		}
+		if kp.FeeManager.HasMakerRebate() {
+			for _, tran := range trans {
+				if tran.isMaker() {
+					makers = append(makers, tran)
+				}
+			}
+		}
 	}
 
 	for addrStr, trans := range expireTransfers {
 		addr := sdk.AccAddress(addrStr)
 		acc := kp.am.GetAccount(ctx, addr)
 
-		fees := kp.FeeManager.CalcExpiresFee(acc.GetCoins(), expireEventType, trans, kp.engines, postAllocateHandler)
+		fees := kp.FeeManager.CalcExpiresFee(acc.GetCoins(), trans, kp.engines, postAllocateHandler)
 		if !fees.IsEmpty() {
 			if _, ok := feesPerAcc[addrStr]; ok {
 				feesPerAcc[addrStr].AddFee(fees)
@@ -595,7 +963,7 @@ func (kp *DexKeeper) allocate(ctx sdk.Context, tranCh <-chan Transfer, postAlloc
 			totalFee.AddFee(fees)
 		}
 	}
-	return totalFee, feesPerAcc
+	return totalFee, feesPerAcc, feeTiers, makers
 }
 
 // DEPRECATED
@@ -689,11 +1057,15 @@ func (kp *DexKeeper) allocateAndCalcFee(
 	wg.Add(concurrency)
 	feesPerCh := make([]sdk.Fee, concurrency)
 	feesPerAcc := make([]map[string]*sdk.Fee, concurrency)
+	feeTiersPerCh := make([]map[string]int, concurrency)
+	makersPerCh := make([]TradeTransfers, concurrency)
 	allocatePerCh := func(index int, tranCh <-chan Transfer) {
 		defer wg.Done()
-		fee, feeByAcc := kp.allocate(ctx, tranCh, postAlloTransHandler)
+		fee, feeByAcc, feeTiers, makers := kp.allocate(ctx, tranCh, postAlloTransHandler)
 		feesPerCh[index].AddFee(fee)
 		feesPerAcc[index] = feeByAcc
+		feeTiersPerCh[index] = feeTiers
+		makersPerCh[index] = makers
 	}
 
 	for i, tradeTranCh := range tradeOuts {
@@ -703,6 +1075,17 @@ func (kp *DexKeeper) allocateAndCalcFee(
 	totalFee := sdk.Fee{}
 	for i := 0; i < concurrency; i++ {
 		totalFee.AddFee(feesPerCh[i])
+		if feeTiersPerCh[i] != nil && kp.tradeFeeTiers == nil {
+			kp.tradeFeeTiers = make(map[string]int)
+		}
+		for id, tier := range feeTiersPerCh[i] {
+			kp.tradeFeeTiers[id] = tier
+		}
+	}
+	// the rebates are paid out of the collected taker fees, so they are not distributed to the validators
+	for _, makers := range makersPerCh {
+		rebates := kp.payMakerRebates(ctx, makers)
+		totalFee.Tokens = totalFee.Tokens.Minus(rebates)
 	}
 	if kp.CollectOrderInfoForPublish {
 		for _, m := range feesPerAcc {
@@ -716,9 +1099,11 @@ func (kp *DexKeeper) allocateAndCalcFee(
 
 func (kp *DexKeeper) expireOrders(ctx sdk.Context, blockTime time.Time) []chan Transfer {
 	allOrders := make(map[string]map[string]*OrderInfo) //TODO replace by iterator
+	stopOrders := make(map[string]map[string]*OrderInfo)
 	for _, orderKeeper := range kp.OrderKeepers {
 		if orderKeeper.supportUpgradeVersion() {
 			allOrders = appendAllOrdersMap(allOrders, orderKeeper.getAllOrders())
+			stopOrders = appendAllOrdersMap(stopOrders, orderKeeper.getAllStopOrders())
 		}
 	}
 	size := len(allOrders)
@@ -744,7 +1129,7 @@ func (kp *DexKeeper) expireOrders(ctx sdk.Context, blockTime time.Time) []chan T
 		transferChs[i] = make(chan Transfer, channelSize*2)
 	}
 
-	expire := func(orders map[string]*OrderInfo, engine *me.MatchEng, side int8) {
+	expire := func(orderKeeper DexOrderKeeper, orders map[string]*OrderInfo, engine *me.MatchEng, side int8) {
 		removeCallback := func(ord me.OrderPart) {
 			// gen transfer
 			if ordMsg, ok := orders[ord.Id]; ok && ordMsg != nil {
@@ -752,6 +1137,7 @@ func (kp *DexKeeper) expireOrders(ctx sdk.Context, blockTime time.Time) []chan T
 				transferChs[h] <- TransferFromExpired(ord, *ordMsg)
 				// delete from allOrders
 				delete(orders, ord.Id)
+				orderKeeper.removeSenderOrder(ordMsg.Sender, ord.Id)
 			} else {
 				kp.logger.Error("failed to locate order to remove in order book", "oid", ord.Id)
 			}
@@ -763,6 +1149,18 @@ func (kp *DexKeeper) expireOrders(ctx sdk.Context, blockTime time.Time) []chan T
 		}
 	}
 
+	// untriggered stop orders are not in the order book, they expire by their created height
+	expireStopOrders := func(orderKeeper DexOrderKeeper, orders map[string]*OrderInfo) {
+		for id, ordMsg := range orders {
+			if ordMsg.CreatedHeight < expireHeight {
+				h := channelHash(ordMsg.Sender, concurrency)
+				transferChs[h] <- TransferFromExpired(stopOrderPart(ordMsg), *ordMsg)
+				delete(orders, id)
+				orderKeeper.removeSenderOrder(ordMsg.Sender, id)
+			}
+		}
+	}
+
 	symbolCh := make(chan string, concurrency)
 	utils.ConcurrentExecuteAsync(concurrency,
 		func() {
@@ -773,9 +1171,11 @@ func (kp *DexKeeper) expireOrders(ctx sdk.Context, blockTime time.Time) []chan T
 		}, func() {
 			for symbol := range symbolCh {
 				engine := kp.engines[symbol]
+				orderKeeper := kp.mustGetOrderKeeper(symbol)
 				orders := allOrders[symbol]
-				expire(orders, engine, me.BUYSIDE)
-				expire(orders, engine, me.SELLSIDE)
+				expire(orderKeeper, orders, engine, me.BUYSIDE)
+				expire(orderKeeper, orders, engine, me.SELLSIDE)
+				expireStopOrders(orderKeeper, stopOrders[symbol])
 			}
 		}, func() {
 			for _, transferCh := range transferChs {
@@ -824,6 +1224,73 @@ func (kp *DexKeeper) ExpireOrders(
 	fees.Pool.AddAndCommitFee("EXPIRE", totalFee)
 }
 
+// removeGoodTillOrders removes the orders whose good-till height or time is reached by the block,
+// and calls the callback for each of them.
+func (kp *DexKeeper) removeGoodTillOrders(height int64, blockTime time.Time, callback func(ord me.OrderPart, ordMsg *OrderInfo)) {
+	t := blockTime.Unix()
+	for symbol, engine := range kp.engines {
+		orderKeeper, err := kp.getOrderKeeper(symbol)
+		if err != nil || !orderKeeper.supportUpgradeVersion() {
+			continue
+		}
+		ids := orderKeeper.getGoodTillOrdersForPair(symbol)
+		orders := orderKeeper.getAllOrdersForPair(symbol)
+		stopOrders := orderKeeper.getStopOrdersForPair(symbol)
+		for id := range ids {
+			if ordMsg, ok := orders[id]; ok {
+				if !ordMsg.IsGoodTillExpired(height, t) {
+					continue
+				}
+				ord, err := engine.Book.RemoveOrder(id, ordMsg.Side, ordMsg.Price)
+				if err != nil {
+					kp.logger.Error("Failed to remove good-till order, may be fatal!", "orderID", id, "err", err)
+					continue
+				}
+				delete(orders, id)
+				orderKeeper.removeSenderOrder(ordMsg.Sender, id)
+				callback(ord, ordMsg)
+			} else if ordMsg, ok := stopOrders[id]; ok {
+				if !ordMsg.IsGoodTillExpired(height, t) {
+					continue
+				}
+				delete(stopOrders, id)
+				orderKeeper.removeSenderOrder(ordMsg.Sender, id)
+				callback(stopOrderPart(ordMsg), ordMsg)
+			}
+			// the order is either expired, or already filled or canceled
+			delete(ids, id)
+		}
+	}
+}
+
+// ExpireGoodTillOrders expires the orders whose good-till height or time is reached by the block.
+// Unlike ExpireOrders which only runs at breathe blocks, it runs at every block after the match.
+func (kp *DexKeeper) ExpireGoodTillOrders(ctx sdk.Context, postAlloTransHandler TransferHandler) {
+	blockHeader := ctx.BlockHeader()
+	var expired []Transfer
+	kp.removeGoodTillOrders(blockHeader.Height, blockHeader.Time, func(ord me.OrderPart, ordMsg *OrderInfo) {
+		expired = append(expired, TransferFromExpired(ord, *ordMsg))
+	})
+	if len(expired) == 0 {
+		return
+	}
+
+	concurrency := 1 << kp.poolSize
+	transferChs := make([]chan Transfer, concurrency)
+	for i := range transferChs {
+		transferChs[i] = make(chan Transfer, len(expired))
+	}
+	for _, tran := range expired {
+		transferChs[channelHash(tran.accAddress, concurrency)] <- tran
+	}
+	for _, transferCh := range transferChs {
+		close(transferCh)
+	}
+
+	totalFee := kp.allocateAndCalcFee(ctx, transferChs, postAlloTransHandler)
+	fees.Pool.AddAndCommitFee("GOOD_TILL_EXPIRE", totalFee)
+}
+
 func (kp *DexKeeper) MarkBreatheBlock(ctx sdk.Context, height int64, blockTime time.Time) {
 	key := utils.Int642Bytes(blockTime.Unix() / utils.SecondsPerDay)
 	store := ctx.KVStore(kp.storeKey)
@@ -945,6 +1412,12 @@ func (kp *DexKeeper) DelistTradingPair(ctx sdk.Context, symbol string, postAlloc
 	}
 
 	delete(kp.engines, symbol)
+	delete(kp.tradingStatuses, symbol)
+	kp.deleteCircuitBreakerTrips(ctx, symbol)
+	kp.tickers.removePair(symbol)
+	if kp.recentTrades != nil {
+		kp.recentTrades.removePair(symbol)
+	}
 	kp.deleteRecentPrices(ctx, symbol)
 	kp.mustGetOrderKeeper(symbol).deleteOrdersForPair(symbol)
 
@@ -962,11 +1435,13 @@ func (kp *DexKeeper) deleteRecentPrices(ctx sdk.Context, symbol string) {
 
 func (kp *DexKeeper) expireAllOrders(ctx sdk.Context, symbol string) []chan Transfer {
 	ordersOfSymbol := make(map[string]*OrderInfo)
+	stopOrdersOfSymbol := make(map[string]*OrderInfo)
 	if dexOrderKeeper, err := kp.getOrderKeeper(symbol); err == nil {
 		ordersOfSymbol = dexOrderKeeper.getAllOrdersForPair(symbol)
+		stopOrdersOfSymbol = dexOrderKeeper.getStopOrdersForPair(symbol)
 	}
 
-	orderNum := len(ordersOfSymbol)
+	orderNum := len(ordersOfSymbol) + len(stopOrdersOfSymbol)
 	if orderNum == 0 {
 		kp.logger.Info("no orders to expire", "symbol", symbol)
 		return nil
@@ -997,6 +1472,10 @@ func (kp *DexKeeper) expireAllOrders(ctx sdk.Context, symbol string) []chan Tran
 		orders := ordersOfSymbol
 		expire(orders, engine, me.BUYSIDE)
 		expire(orders, engine, me.SELLSIDE)
+		for _, ordMsg := range stopOrdersOfSymbol {
+			h := channelHash(ordMsg.Sender, concurrency)
+			transferChs[h] <- TransferFromExpired(stopOrderPart(ordMsg), *ordMsg)
+		}
 
 		for _, transferCh := range transferChs {
 			close(transferCh)
@@ -1053,6 +1532,14 @@ func (kp *DexKeeper) GetAllOrders() map[string]map[string]*OrderInfo {
 	return allOrders
 }
 
+func (kp *DexKeeper) GetAllStopOrders() map[string]map[string]*OrderInfo {
+	stopOrders := make(map[string]map[string]*OrderInfo)
+	for _, orderKeeper := range kp.OrderKeepers {
+		stopOrders = appendAllOrdersMap(stopOrders, orderKeeper.getAllStopOrders())
+	}
+	return stopOrders
+}
+
 // ONLY FOR TEST USE
 func (kp *DexKeeper) GetAllOrdersForPair(symbol string) map[string]*OrderInfo {
 	return kp.mustGetOrderKeeper(symbol).getAllOrdersForPair(symbol)
@@ -6,6 +6,7 @@ import (
 
 	"github.com/bnb-chain/node/common/upgrade"
 	"github.com/bnb-chain/node/common/utils"
+	me "github.com/bnb-chain/node/plugins/dex/matcheng"
 )
 
 func (kp *DexKeeper) SelectSymbolsToMatch(height int64, matchAllSymbols bool) []string {
@@ -23,7 +24,20 @@ func (kp *DexKeeper) SelectSymbolsToMatch(height int64, matchAllSymbols bool) []
 			}
 		}
 	}
-	return symbolsToMatch
+	if len(kp.circuitBreakerTrips) != 0 {
+		symbolsToMatch = kp.selectResumedSymbols(symbolsToMatch, height)
+	}
+	if len(kp.tradingStatuses) == 0 {
+		return symbolsToMatch
+	}
+	// the pairs in cancel-only or halted status are not matched
+	tradingSymbols := symbolsToMatch[:0]
+	for _, symbol := range symbolsToMatch {
+		if _, notTrading := kp.tradingStatuses[symbol]; !notTrading {
+			tradingSymbols = append(tradingSymbols, symbol)
+		}
+	}
+	return tradingSymbols
 }
 
 func (kp *DexKeeper) MatchAndAllocateSymbols(ctx sdk.Context, postAlloTransHandler TransferHandler, matchAllSymbols bool) {
@@ -31,9 +45,12 @@ func (kp *DexKeeper) MatchAndAllocateSymbols(ctx sdk.Context, postAlloTransHandl
 	blockHeader := ctx.BlockHeader()
 	timestamp := blockHeader.Time.UnixNano()
 
+	kp.TriggerStopOrders(blockHeader.Height)
 	symbolsToMatch := kp.SelectSymbolsToMatch(blockHeader.Height, matchAllSymbols)
 
 	kp.logger.Info("symbols to match", "symbols", symbolsToMatch)
+	kp.resetTradeFeeTiers()
+	kp.resetMakerRebates()
 	var tradeOuts []chan Transfer
 	if len(symbolsToMatch) == 0 {
 		kp.logger.Info("No order comes in for the block")
@@ -43,9 +60,36 @@ func (kp *DexKeeper) MatchAndAllocateSymbols(ctx sdk.Context, postAlloTransHandl
 
 	totalFee := kp.allocateAndCalcFee(ctx, tradeOuts, postAlloTransHandler)
 	fees.Pool.AddAndCommitFee("MATCH", totalFee)
+	kp.checkCircuitBreakers(ctx, symbolsToMatch)
+	kp.tickers.setLastTime(timestamp)
+	kp.flushKlines()
 	kp.ClearAfterMatch()
 }
 
+// TriggerStopOrders injects the stop orders whose stop price has been reached by the last trade price
+// into the order book as limit orders of this round, so that they take part in the coming match.
+func (kp *DexKeeper) TriggerStopOrders(height int64) {
+	for symbol, engine := range kp.engines {
+		orderKeeper, err := kp.getOrderKeeper(symbol)
+		if err != nil || !orderKeeper.supportUpgradeVersion() {
+			continue
+		}
+		if _, notTrading := kp.tradingStatuses[symbol]; notTrading {
+			continue
+		}
+		for _, info := range orderKeeper.triggerStopOrders(symbol, engine.LastTradePrice) {
+			if _, err := engine.Book.InsertOrder(info.Id, info.Side, height, info.Price, info.Quantity); err != nil {
+				kp.logger.Error("Failed to insert triggered stop order, may be fatal!", "orderID", info.Id, "err", err)
+				continue
+			}
+			if info.TimeInForce == TimeInForce.FOK {
+				engine.AddFOKOrder(info.Id)
+			}
+			kp.logger.Debug("Triggered stop order", "symbol", symbol, "id", info.Id, "lastTradePrice", engine.LastTradePrice)
+		}
+	}
+}
+
 // please note if distributeTrade this method will work in async mode, otherwise in sync mode.
 // Always run kp.SelectSymbolsToMatch(ctx.BlockHeader().Height, matchAllSymbols) before matchAndDistributeTrades
 func (kp *DexKeeper) matchAndDistributeTrades(distributeTrade bool, height, timestamp int64, symbolsToMatch []string) []chan Transfer {
@@ -91,6 +135,7 @@ func (kp *DexKeeper) matchAndDistributeTrades(distributeTrade bool, height, time
 }
 
 func (kp *DexKeeper) MatchSymbols(height, timestamp int64, matchAllSymbols bool) {
+	kp.TriggerStopOrders(height)
 	symbolsToMatch := kp.SelectSymbolsToMatch(height, matchAllSymbols)
 	kp.logger.Debug("symbols to match", "symbols", symbolsToMatch)
 
@@ -100,6 +145,8 @@ func (kp *DexKeeper) MatchSymbols(height, timestamp int64, matchAllSymbols bool)
 		kp.matchAndDistributeTrades(false, height, timestamp, symbolsToMatch)
 	}
 
+	kp.tickers.setLastTime(timestamp)
+	kp.flushKlines()
 	kp.ClearAfterMatch()
 }
 
@@ -109,12 +156,33 @@ func (kp *DexKeeper) matchAndDistributeTradesForSymbol(symbol string, height, ti
 	concurrency := len(tradeOuts)
 	orderKeeper := kp.mustGetOrderKeeper(symbol)
 	orders := orderKeeper.getAllOrdersForPair(symbol)
+	kp.rejectPostOnlyTakers(symbol, engine, orderKeeper, orders, distributeTrade, tradeOuts)
+	if kp.IsMatchSuspended(symbol, height) {
+		// the orders are still accepted while the matching is suspended by the circuit breaker,
+		// the ones not allowed to rest on the book are removed as if they were not filled in a match
+		kp.expireRoundIOCOrders(symbol, engine, orderKeeper, orders, distributeTrade, tradeOuts)
+		return
+	}
 	// please note there is no logging in matching, expecting to see the order book details
 	// from the exchange's order book stream.
-	if engine.Match(height) {
+	success := engine.Match(height)
+	kp.expireKilledFOKOrders(engine, orderKeeper, orders, distributeTrade, tradeOuts)
+	kp.releaseSelfTradeOrders(engine, orderKeeper, orders, distributeTrade, tradeOuts)
+	if success {
 		kp.logger.Debug("Match finish:", "symbol", symbol, "lastTradePrice", engine.LastTradePrice)
+		kp.tickers.addTrades(symbol, timestamp, engine.Trades)
+		if kp.recentTrades != nil {
+			kp.recentTrades.addTrades(symbol, height, timestamp, engine.Trades)
+		}
+		if kp.klines != nil {
+			kp.klines.addTrades(symbol, height, timestamp, engine.Trades)
+		}
+		// the iceberg orders traded in this match, with the filled quantity before the match
+		var icebergs []icebergFill
 		for i := range engine.Trades {
 			t := &engine.Trades[i]
+			icebergs = appendIcebergFill(icebergs, orders[t.Bid])
+			icebergs = appendIcebergFill(icebergs, orders[t.Sid])
 			updateOrderMsg(orders[t.Bid], t.BuyCumQty, height, timestamp)
 			updateOrderMsg(orders[t.Sid], t.SellCumQty, height, timestamp)
 			if distributeTrade {
@@ -127,9 +195,13 @@ func (kp *DexKeeper) matchAndDistributeTradesForSymbol(symbol string, height, ti
 		}
 		droppedIds := engine.DropFilledOrder() //delete from order books
 		for _, id := range droppedIds {
+			if msg, ok := orders[id]; ok {
+				orderKeeper.removeSenderOrder(msg.Sender, id)
+			}
 			delete(orders, id) //delete from order cache
 		}
 		kp.logger.Debug("Drop filled orders", "total", droppedIds)
+		kp.refreshIcebergOrders(engine, orders, icebergs, height)
 	} else {
 		// FUTURE-TODO:
 		// when Match() failed, have to unsolicited cancel all the new orders
@@ -140,8 +212,13 @@ func (kp *DexKeeper) matchAndDistributeTradesForSymbol(symbol string, height, ti
 			"symbol", symbol)
 		thisRoundIds := orderKeeper.getRoundOrdersForPair(symbol)
 		for _, id := range thisRoundIds {
-			msg := orders[id]
+			msg, ok := orders[id]
+			if !ok {
+				// already killed or rejected before the match
+				continue
+			}
 			delete(orders, id)
+			orderKeeper.removeSenderOrder(msg.Sender, id)
 			if ord, err := engine.Book.RemoveOrder(id, msg.Side, msg.Price); err == nil {
 				kp.logger.Info("Removed due to match failure", "ordID", msg.Id)
 				if distributeTrade {
@@ -160,10 +237,18 @@ func (kp *DexKeeper) matchAndDistributeTradesForSymbol(symbol string, height, ti
 		}
 		return // no need to handle IOC
 	}
+	kp.expireRoundIOCOrders(symbol, engine, orderKeeper, orders, distributeTrade, tradeOuts)
+}
+
+// expireRoundIOCOrders releases the IOC and FOK orders of this round which are left in the book.
+func (kp *DexKeeper) expireRoundIOCOrders(symbol string, engine *me.MatchEng, orderKeeper DexOrderKeeper,
+	orders map[string]*OrderInfo, distributeTrade bool, tradeOuts []chan Transfer) {
+	concurrency := len(tradeOuts)
 	iocIDs := orderKeeper.getRoundIOCOrdersForPair(symbol)
 	for _, id := range iocIDs {
 		if msg, ok := orders[id]; ok {
 			delete(orders, id)
+			orderKeeper.removeSenderOrder(msg.Sender, id)
 			if ord, err := engine.Book.RemoveOrder(id, msg.Side, msg.Price); err == nil {
 				kp.logger.Debug("Removed unclosed IOC order", "ordID", msg.Id)
 				if distributeTrade {
@@ -177,6 +262,149 @@ func (kp *DexKeeper) matchAndDistributeTradesForSymbol(symbol string, height, ti
 	}
 }
 
+// expireKilledFOKOrders releases the FOK orders which are killed by the match engine as they cannot be fully filled.
+func (kp *DexKeeper) expireKilledFOKOrders(engine *me.MatchEng, orderKeeper DexOrderKeeper,
+	orders map[string]*OrderInfo, distributeTrade bool, tradeOuts []chan Transfer) {
+	concurrency := len(tradeOuts)
+	for _, ord := range engine.KilledFOKOrders {
+		msg, ok := orders[ord.Id]
+		if !ok {
+			kp.logger.Error("Failed to find killed FOK order, may be fatal!", "orderID", ord.Id)
+			continue
+		}
+		delete(orders, ord.Id)
+		orderKeeper.removeSenderOrder(msg.Sender, ord.Id)
+		kp.logger.Debug("Removed killed FOK order", "ordID", ord.Id)
+		if distributeTrade {
+			c := channelHash(msg.Sender, concurrency)
+			tradeOuts[c] <- TransferFromExpired(ord, *msg)
+		}
+	}
+}
+
+// releaseSelfTradeOrders releases the orders which are decremented or canceled by the self-trade prevention
+// of the match engine. The decrements are released first, as a canceled order may have been decremented before.
+func (kp *DexKeeper) releaseSelfTradeOrders(engine *me.MatchEng, orderKeeper DexOrderKeeper,
+	orders map[string]*OrderInfo, distributeTrade bool, tradeOuts []chan Transfer) {
+	concurrency := len(tradeOuts)
+	for _, ord := range engine.SelfTradeDecremented {
+		msg, ok := orders[ord.Id]
+		if !ok {
+			kp.logger.Error("Failed to find decremented self-trade order, may be fatal!", "orderID", ord.Id)
+			continue
+		}
+		origOrd := *msg
+		if distributeTrade {
+			c := channelHash(msg.Sender, concurrency)
+			tradeOuts[c] <- TransferFromSelfTradePrevented(me.OrderPart{Id: ord.Id, Qty: msg.Quantity - ord.Qty}, origOrd)
+		}
+		msg.Quantity = ord.Qty
+		kp.logger.Debug("Decremented self-trade order", "ordID", ord.Id, "qty", ord.Qty)
+		if kp.CollectOrderInfoForPublish {
+			orderKeeper.appendOrderChangeSync(OrderChange{ord.Id, Amended, "", origOrd})
+		}
+	}
+	for _, ord := range engine.SelfTradeCanceled {
+		msg, ok := orders[ord.Id]
+		if !ok {
+			kp.logger.Error("Failed to find canceled self-trade order, may be fatal!", "orderID", ord.Id)
+			continue
+		}
+		delete(orders, ord.Id)
+		orderKeeper.removeSenderOrder(msg.Sender, ord.Id)
+		kp.logger.Debug("Canceled self-trade order", "ordID", ord.Id)
+		if distributeTrade {
+			c := channelHash(msg.Sender, concurrency)
+			tradeOuts[c] <- TransferFromSelfTradePrevented(ord, *msg)
+		}
+		if kp.CollectOrderInfoForPublish {
+			orderKeeper.appendOrderChangeSync(OrderChange{ord.Id, SelfTradePrevented, "", nil})
+		}
+	}
+}
+
+type icebergFill struct {
+	id     string
+	cumQty int64
+}
+
+func appendIcebergFill(icebergs []icebergFill, ord *OrderInfo) []icebergFill {
+	if !ord.IsIceberg() {
+		return icebergs
+	}
+	for _, f := range icebergs {
+		if f.id == ord.Id {
+			return icebergs
+		}
+	}
+	return append(icebergs, icebergFill{ord.Id, ord.CumQty})
+}
+
+// refreshIcebergOrders replenishes the display slice of the iceberg orders which have used up
+// their slice in this match. The refreshed orders lose time priority and are moved to the back of their price level.
+func (kp *DexKeeper) refreshIcebergOrders(engine *me.MatchEng, orders map[string]*OrderInfo,
+	icebergs []icebergFill, height int64) {
+	for _, f := range icebergs {
+		msg, ok := orders[f.id]
+		if !ok || f.cumQty/msg.DisplayQty == msg.CumQty/msg.DisplayQty {
+			// fully filled, or still in the same slice
+			continue
+		}
+		if err := engine.RequeueOrder(f.id, msg.Side, msg.Price, height); err != nil {
+			kp.logger.Error("Failed to refresh iceberg order, may be fatal!", "orderID", f.id, "err", err)
+			continue
+		}
+		kp.logger.Debug("Refreshed iceberg order", "ordID", f.id, "cumQty", msg.CumQty)
+	}
+}
+
+// rejectPostOnlyTakers removes the post-only orders of this round which would be executed as takers.
+// Removing an order may change the concluded price and the taker side, so it repeats until
+// no post-only order is left on the taker side.
+func (kp *DexKeeper) rejectPostOnlyTakers(symbol string, engine *me.MatchEng, orderKeeper DexOrderKeeper,
+	orders map[string]*OrderInfo, distributeTrade bool, tradeOuts []chan Transfer) {
+	hasPostOnly := false
+	for _, id := range orderKeeper.getRoundOrdersForPair(symbol) {
+		if msg, ok := orders[id]; ok && msg.TimeInForce == TimeInForce.POST_ONLY {
+			hasPostOnly = true
+			break
+		}
+	}
+	if !hasPostOnly {
+		return
+	}
+
+	concurrency := len(tradeOuts)
+	for {
+		rejected := false
+		for _, taker := range engine.TakerOrders() {
+			msg, ok := orders[taker.Id]
+			if !ok || msg.TimeInForce != TimeInForce.POST_ONLY {
+				continue
+			}
+			delete(orders, taker.Id)
+			orderKeeper.removeSenderOrder(msg.Sender, taker.Id)
+			ord, err := engine.Book.RemoveOrder(taker.Id, msg.Side, msg.Price)
+			if err != nil {
+				kp.logger.Error("Failed to remove post-only order, may be fatal!", "orderID", taker.Id)
+				continue
+			}
+			rejected = true
+			kp.logger.Debug("Rejected post-only order", "ordID", taker.Id)
+			if distributeTrade {
+				c := channelHash(msg.Sender, concurrency)
+				tradeOuts[c] <- TransferFromCanceled(ord, *msg, true)
+			}
+			if kp.CollectOrderInfoForPublish {
+				orderKeeper.appendOrderChangeSync(OrderChange{taker.Id, PostOnlyRejected, "", nil})
+			}
+		}
+		if !rejected {
+			return
+		}
+	}
+}
+
 // Run as postConsume procedure of async, no concurrent updates of orders map
 func updateOrderMsg(order *OrderInfo, cumQty, height, timestamp int64) {
 	order.CumQty = cumQty
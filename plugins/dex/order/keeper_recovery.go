@@ -12,7 +12,6 @@ import (
 	"github.com/tendermint/tendermint/crypto/tmhash"
 	cmn "github.com/tendermint/tendermint/libs/common"
 	dbm "github.com/tendermint/tendermint/libs/db"
-	"github.com/tendermint/tendermint/libs/log"
 	"github.com/tendermint/tendermint/state"
 	tmstore "github.com/tendermint/tendermint/store"
 	tmtypes "github.com/tendermint/tendermint/types"
@@ -89,10 +88,22 @@ func (kp *DexKeeper) SnapShotOrderBook(ctx sdk.Context, height int64) (effectedS
 			msgKeys = append(msgKeys, id)
 		}
 	}
+	// untriggered stop orders are saved along with the active orders and parked again when reloaded
+	stopOrders := kp.GetAllStopOrders()
+	for symbol, orderMap := range stopOrders {
+		for id := range orderMap {
+			idSymbolMap[id] = symbol
+			msgKeys = append(msgKeys, id)
+		}
+	}
 	sort.Strings(msgKeys)
 	msgs := make([]OrderInfo, len(msgKeys))
 	for i, key := range msgKeys {
-		msgs[i] = *allOrders[idSymbolMap[key]][key]
+		if ord, ok := allOrders[idSymbolMap[key]][key]; ok {
+			msgs[i] = *ord
+		} else {
+			msgs[i] = *stopOrders[idSymbolMap[key]][key]
+		}
 	}
 
 	snapshot := ActiveOrders{Orders: msgs}
@@ -102,6 +113,26 @@ func (kp *DexKeeper) SnapShotOrderBook(ctx sdk.Context, height int64) (effectedS
 	return effectedStoreKeys, compressAndSave(snapshot, kp.cdc, key, kvstore)
 }
 
+// ReadOrderBookSnapshot reads the order book snapshot of the pair saved by SnapShotOrderBook at the breathe block height
+func ReadOrderBookSnapshot(kvStore sdk.KVStore, cdc *wire.Codec, height int64, pair string) (OrderBookSnapshot, error) {
+	var ob OrderBookSnapshot
+	key := genOrderBookSnapshotKey(height, strings.ToUpper(pair))
+	bz := kvStore.Get([]byte(key))
+	if bz == nil {
+		return ob, fmt.Errorf("no order book snapshot [%s] is saved", key)
+	}
+	r, err := zlib.NewReader(bytes.NewBuffer(bz))
+	if err != nil {
+		return ob, fmt.Errorf("failed to unzip snapshot for orderbook [%s], err: %v", key, err)
+	}
+	var bw bytes.Buffer
+	_, _ = io.Copy(&bw, r)
+	if err = cdc.UnmarshalBinaryLengthPrefixed(bw.Bytes(), &ob); err != nil {
+		return ob, fmt.Errorf("failed to unmarshal snapshot for orderbook [%s], err: %v", key, err)
+	}
+	return ob, nil
+}
+
 func (kp *DexKeeper) LoadOrderBookSnapshot(ctx sdk.Context, latestBlockHeight int64, timeOfLatestBlock time.Time, blockInterval, daysBack int) (int64, error) {
 	height := kp.GetLastBreatheBlockHeight(ctx, latestBlockHeight, timeOfLatestBlock, blockInterval, daysBack)
 	ctx.Logger().Info("Loading order book snapshot from last breathe block", "blockHeight", height)
@@ -193,8 +224,9 @@ func (kp *DexKeeper) LoadOrderBookSnapshot(ctx sdk.Context, latestBlockHeight in
 	return height, nil
 }
 
-func (kp *DexKeeper) replayOneBlocks(logger log.Logger, block *tmtypes.Block, stateDB dbm.DB, txDecoder sdk.TxDecoder,
+func (kp *DexKeeper) replayOneBlocks(ctx sdk.Context, block *tmtypes.Block, stateDB dbm.DB, txDecoder sdk.TxDecoder,
 	height int64, timestamp time.Time) {
+	logger := ctx.Logger()
 	if block == nil {
 		logger.Error("No block is loaded. Ignore replay for orderbook")
 		return
@@ -222,6 +254,10 @@ func (kp *DexKeeper) replayOneBlocks(logger log.Logger, block *tmtypes.Block, st
 		for _, m := range msgs {
 			switch msg := m.(type) {
 			case NewOrderMsg:
+				msg, err := kp.PriceMarketOrder(ctx, msg)
+				if err != nil {
+					logger.Error("Failed to price market order", "err", err)
+				}
 				var txSource int64
 				upgrade.UpgradeBEP10(nil, func() {
 					if stdTx, ok := tx.(auth.StdTx); ok {
@@ -235,7 +271,7 @@ func (kp *DexKeeper) replayOneBlocks(logger log.Logger, block *tmtypes.Block, st
 					height, t,
 					height, t,
 					0, txHash.String(), txSource}
-				err := kp.AddOrder(orderInfo, true)
+				err = kp.AddOrder(orderInfo, true)
 				if err != nil {
 					logger.Error("Failed to replay NreOrderMsg", "err", err)
 				}
@@ -251,6 +287,26 @@ func (kp *DexKeeper) replayOneBlocks(logger log.Logger, block *tmtypes.Block, st
 					logger.Error("Failed to replay cancel msg", "err", err)
 				}
 				logger.Info("Canceled Order", "order", msg)
+			case AmendOrderMsg:
+				_, err := kp.AmendOrder(msg, height, t)
+				if err != nil {
+					logger.Error("Failed to replay amend msg", "err", err)
+				}
+				logger.Info("Amended Order", "order", msg)
+			case CancelAllOrdersMsg:
+				// the open orders are the same as when the tx was delivered, as all the txs before it are replayed
+				for _, origOrd := range kp.getCancelableOrderInfos(msg.Sender, msg.Symbol, msg.Side) {
+					err := kp.RemoveOrder(origOrd.Id, origOrd.Symbol, func(ord me.OrderPart) {
+						if kp.CollectOrderInfoForPublish {
+							bnclog.Debug("deleted order from order changes map", "orderId", origOrd.Id, "isRecovery", true)
+							kp.RemoveOrderInfosForPub(origOrd.Symbol, origOrd.Id)
+						}
+					})
+					if err != nil {
+						logger.Error("Failed to replay cancel all msg", "err", err)
+					}
+				}
+				logger.Info("Canceled All Orders", "msg", msg)
 			case dextypes.ListMiniMsg:
 				kp.engines[dexutils.Assets2TradingPair(msg.BaseAssetSymbol, msg.QuoteAssetSymbol)].LastMatchHeight = 0
 			case dextypes.ListMsg:
@@ -260,6 +316,12 @@ func (kp *DexKeeper) replayOneBlocks(logger log.Logger, block *tmtypes.Block, st
 	}
 	logger.Info("replayed all tx. Starting match", "height", height)
 	kp.MatchSymbols(height, t, false) //no need to check result
+	// the balances are already settled in the state, only need to remove the expired orders from memory
+	kp.removeGoodTillOrders(height, timestamp, func(ord me.OrderPart, ordMsg *OrderInfo) {
+		if kp.CollectOrderInfoForPublish {
+			kp.RemoveOrderInfosForPub(ordMsg.Symbol, ordMsg.Id)
+		}
+	})
 }
 
 func (kp *DexKeeper) ReplayOrdersFromBlock(ctx sdk.Context, bc *tmstore.BlockStore, stateDb dbm.DB, lastHeight, breatheHeight int64,
@@ -268,7 +330,7 @@ func (kp *DexKeeper) ReplayOrdersFromBlock(ctx sdk.Context, bc *tmstore.BlockSto
 		block := bc.LoadBlock(i)
 		ctx.Logger().Info("Relaying block for order book", "height", i)
 		upgrade.Mgr.SetHeight(i)
-		kp.replayOneBlocks(ctx.Logger(), block, stateDb, txDecoder, i, block.Time)
+		kp.replayOneBlocks(ctx, block, stateDb, txDecoder, i, block.Time)
 	}
 	return nil
 }
@@ -285,6 +347,9 @@ func (kp *DexKeeper) initOrderBook(ctx sdk.Context, blockInterval, daysBack int,
 	if err != nil {
 		panic(err)
 	}
+	if kp.recentTrades != nil {
+		kp.recentTrades.load(height)
+	}
 	logger := ctx.Logger().With("module", "dex")
 	logger.Info("Initialized Block Store for replay", "fromHeight", height, "toHeight", lastHeight)
 	err = kp.ReplayOrdersFromBlock(ctx.WithLogger(logger), blockStore, stateDB, lastHeight, height, txDecoder)
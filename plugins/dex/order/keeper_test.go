@@ -2,6 +2,7 @@ package order
 
 import (
 	"os"
+	"strconv"
 	"testing"
 	"time"
 
@@ -15,6 +16,7 @@ import (
 	"github.com/cosmos/cosmos-sdk/x/auth"
 	txbuilder "github.com/cosmos/cosmos-sdk/x/auth/client/txbuilder"
 	"github.com/cosmos/cosmos-sdk/x/bank"
+	paramTypes "github.com/cosmos/cosmos-sdk/x/paramHub/types"
 
 	abci "github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/crypto/secp256k1"
@@ -46,10 +48,15 @@ func MakeCodec() *wire.Codec {
 	types.RegisterWire(cdc)
 	cdc.RegisterConcrete(NewOrderMsg{}, "dex/NewOrder", nil)
 	cdc.RegisterConcrete(CancelOrderMsg{}, "dex/CancelOrder", nil)
+	cdc.RegisterConcrete(AmendOrderMsg{}, "dex/AmendOrder", nil)
+	cdc.RegisterConcrete(CancelAllOrdersMsg{}, "dex/CancelAllOrders", nil)
 
 	cdc.RegisterConcrete(OrderBookSnapshot{}, "dex/OrderBookSnapshot", nil)
 	cdc.RegisterConcrete(ActiveOrders{}, "dex/ActiveOrders", nil)
 	cdc.RegisterConcrete(store.RecentPrice{}, "dex/RecentPrice", nil)
+	cdc.RegisterConcrete(&SelfTradePreventionParam{}, "dex/SelfTradePreventionParam", nil)
+	cdc.RegisterConcrete(&CircuitBreakerParam{}, "dex/CircuitBreakerParam", nil)
+	cdc.RegisterConcrete(&OrderLimitsParam{}, "dex/OrderLimitsParam", nil)
 
 	return cdc
 }
@@ -536,6 +543,105 @@ func TestKeeper_InitOrderBookDay1(t *testing.T) {
 	assert.Equal(int64(96000), buys[1].Price)
 }
 
+func TestKeeper_InitTickers(t *testing.T) {
+	assert := assert.New(t)
+	cdc := MakeCodec()
+	memDB := db.NewMemDB()
+	blockStore, stateDB := GenerateBlocksAndSave(memDB, false, cdc)
+	logger := log.NewTMLogger(os.Stdout)
+	cms := MakeCMS(memDB)
+	ctx := sdk.NewContext(cms, abci.Header{}, sdk.RunTxModeCheck, logger)
+	tradingPair := dextypes.NewTradingPair("XYZ-000", "BNB", 1e8)
+	keeper := MakeKeeper(cdc)
+	keeper.PairMapper.AddTradingPair(ctx, tradingPair)
+	trip := CircuitBreakerTrip{Symbol: "XYZ-000_BNB", TripHeight: 3, ResumeHeight: 6, TripPrice: 1e8}
+	keeper.circuitBreakerTrips["XYZ-000_BNB"] = []*CircuitBreakerTrip{&trip}
+	tickers := keeper.tickers
+
+	// the breathe blocks are at height 0 and 2, the trades in block 1 and 2 are rebuilt by a separate replay
+	keeper.initTickers(ctx, 2, 7, blockStore, stateDB, 3, auth.DefaultTxDecoder(cdc))
+	// the replay keeps its own trips and tickers
+	assert.Equal([]*CircuitBreakerTrip{&trip}, keeper.circuitBreakerTrips["XYZ-000_BNB"])
+	assert.True(keeper.circuitBreakerTrips["XYZ-000_BNB"][0] == &trip)
+	assert.False(keeper.tickers == tickers)
+	assert.Empty(tickers.buckets)
+	keeper.tickers.setLastTime(blockStore.LoadBlock(3).Time.UnixNano())
+	ticker := keeper.tickers.getTicker("XYZ-000_BNB")
+	assert.Equal(int64(4), ticker.Count)
+	assert.Equal(utils.Fixed8(97000), ticker.OpenPrice)
+	assert.Equal(utils.Fixed8(9000000), ticker.Volume)
+	_, ok := keeper.engines["XYZ-000_BNB"]
+	assert.False(ok)
+}
+
+func generateSelfTradeBlocksAndSave(storedb db.DB, cdc *wire.Codec) (*tmstore.BlockStore, db.DB) {
+	blockStore := tmstore.NewBlockStore(storedb)
+	statedb := db.NewMemDB()
+	lastCommit := &tmtypes.Commit{}
+	traderAdd, traderPrivKey := MakeAddress()
+	height := int64(1)
+	block := NewMockBlock([]auth.StdTx{{Msgs: []sdk.Msg{bank.MsgSend{}}}}, height, lastCommit, cdc)
+	deliverRes := state.ABCIResponses{DeliverTx: []*abci.ResponseDeliverTx{{Code: 0, Log: "ok"}}}
+	state.SaveABCIResponses(statedb, height, &deliverRes)
+	blockStore.SaveBlock(block, block.MakePartSet(BlockPartSize), &tmtypes.Commit{})
+	height++
+	msgs01 := []sdk.Msg{NewNewOrderMsg(traderAdd, GenerateOrderID(1, traderAdd), Side.BUY, "XYZ-000_BNB", 100000, 1000000)}
+	msgs02 := []sdk.Msg{NewNewOrderMsg(traderAdd, GenerateOrderID(2, traderAdd), Side.SELL, "XYZ-000_BNB", 100000, 1000000)}
+	txs := []auth.StdTx{
+		MakeTxFromMsg(msgs01, int64(100), int64(9001), traderPrivKey),
+		MakeTxFromMsg(msgs02, int64(100), int64(9002), traderPrivKey),
+	}
+	block = NewMockBlock(txs, height, lastCommit, cdc)
+	deliverRes = state.ABCIResponses{DeliverTx: []*abci.ResponseDeliverTx{{Code: 0, Log: "ok"}, {Code: 0, Log: "ok"}}}
+	state.SaveABCIResponses(statedb, height, &deliverRes)
+	blockStore.SaveBlock(block, block.MakePartSet(BlockPartSize), &tmtypes.Commit{})
+	return blockStore, statedb
+}
+
+func TestKeeper_InitWithSelfTradePrevention(t *testing.T) {
+	upgrade.Mgr.AddUpgradeHeight(upgrade.BEP19, -1)
+	defer resetChainVersion()
+	cdc := MakeCodec()
+	tradingPair := dextypes.NewTradingPair("XYZ-000", "BNB", 1e8)
+	for _, c := range []struct {
+		policy string
+		buys   int
+		sells  int
+	}{
+		{"none", 0, 0},
+		{"cancel_oldest", 0, 1},
+		{"cancel_newest", 1, 0},
+	} {
+		memDB := db.NewMemDB()
+		blockStore, stateDB := generateSelfTradeBlocksAndSave(memDB, cdc)
+		cms := MakeCMS(memDB)
+		ctx := sdk.NewContext(cms, abci.Header{}, sdk.RunTxModeCheck, log.NewNopLogger())
+		keeper := MakeKeeper(cdc)
+		keeper.PairMapper.AddTradingPair(ctx, tradingPair)
+		keeper.updateDexParams(ctx, []paramTypes.FeeParam{&SelfTradePreventionParam{Policy: c.policy}})
+
+		// the governed policy is applied before the blocks are replayed after a restart
+		keeper = MakeKeeper(cdc)
+		keeper.Init(ctx, 0, 7, blockStore, stateDB, 2, auth.DefaultTxDecoder(cdc))
+		buys, sells := keeper.engines["XYZ-000_BNB"].Book.GetAllLevels()
+		require.Len(t, buys, c.buys, c.policy)
+		require.Len(t, sells, c.sells, c.policy)
+	}
+}
+
+func TestKeeper_CopyCircuitBreakerTrips(t *testing.T) {
+	trips := map[string][]*CircuitBreakerTrip{
+		"XYZ-000_BNB": {{Symbol: "XYZ-000_BNB", TripHeight: 3, ResumeHeight: 6, TripPrice: 1e8}},
+	}
+	copied := copyCircuitBreakerTrips(trips)
+	require.Equal(t, trips, copied)
+	copied["XYZ-000_BNB"][0].ResumePrice = 1.1e8
+	copied["XYZ-000_BNB"] = append(copied["XYZ-000_BNB"], &CircuitBreakerTrip{Symbol: "XYZ-000_BNB", TripHeight: 7})
+	delete(copied, "XYZ-000_BNB")
+	require.Len(t, trips["XYZ-000_BNB"], 1)
+	require.Equal(t, int64(0), trips["XYZ-000_BNB"][0].ResumePrice)
+}
+
 func getAccountCache(cdc *codec.Codec, ms sdk.MultiStore, accountKey *sdk.KVStoreKey) sdk.AccountCache {
 	accountStore := ms.GetKVStore(accountKey)
 	accountStoreCache := auth.NewAccountStoreCache(cdc, accountStore, 10)
@@ -593,6 +699,7 @@ func TestKeeper_ExpireOrders(t *testing.T) {
 	require.Len(t, buys[0].Orders, 1)
 	require.Equal(t, int64(2e6), buys[0].TotalLeavesQty())
 	require.Len(t, keeper.GetAllOrdersForPair("XYZ-000_BNB"), 1)
+	require.Len(t, keeper.OrderKeepers[0].(*BEP2OrderKeeper).senderOrders[string(addr.Bytes())], 2)
 	expectFees := sdk.NewFee(sdk.Coins{
 		sdk.NewCoin("BNB", 6e4),
 		sdk.NewCoin("ABC-000", 1e7),
@@ -610,6 +717,164 @@ func TestKeeper_ExpireOrders(t *testing.T) {
 	fees.Pool.Clear()
 }
 
+func TestKeeper_ExpireGoodTillOrders(t *testing.T) {
+	ctx, am, keeper := setup()
+	keeper.FeeManager.UpdateConfig(NewTestFeeConfig())
+	_, acc := testutils.NewAccount(ctx, am, 1e6)
+	addr := acc.GetAddress()
+	keeper.AddEngine(dextypes.NewTradingPair("ABC-000", "BNB", 1e6))
+	blockTime, _ := time.Parse(time.RFC3339, "2018-01-02T00:00:01Z")
+
+	msg := NewNewOrderMsg(addr, "1", Side.BUY, "ABC-000_BNB", 1e6, 1e6)
+	msg.GoodTillHeight = 20
+	keeper.AddOrder(OrderInfo{msg, 10, 0, 10, 0, 0, "", 0}, false)
+	msg = NewNewOrderMsg(addr, "2", Side.BUY, "ABC-000_BNB", 2e6, 2e6)
+	msg.GoodTillTime = blockTime.Unix() + 60
+	keeper.AddOrder(OrderInfo{msg, 10, 0, 10, 0, 0, "", 0}, false)
+	msg = NewNewOrderMsg(addr, "3", Side.BUY, "ABC-000_BNB", 1e6, 1e6)
+	keeper.AddOrder(OrderInfo{msg, 10, 0, 10, 0, 0, "", 0}, false)
+	msg = NewStopLimitOrderMsg(addr, "4", Side.SELL, "ABC-000_BNB", 5e5, 5e5, 1e8)
+	msg.GoodTillHeight = 20
+	keeper.AddOrder(OrderInfo{msg, 10, 0, 10, 0, 0, "", 0}, false)
+	acc.(types.NamedAccount).SetLockedCoins(sdk.Coins{
+		sdk.NewCoin("ABC-000", 1e8),
+		sdk.NewCoin("BNB", 6e4),
+	}.Sort())
+	am.SetAccount(ctx, acc)
+
+	keeper.ExpireGoodTillOrders(ctx.WithBlockHeader(abci.Header{Height: 19, Time: blockTime}), nil)
+	require.Len(t, keeper.GetAllOrdersForPair("ABC-000_BNB"), 3)
+	require.Len(t, keeper.GetAllStopOrders()["ABC-000_BNB"], 1)
+
+	// expired by height
+	keeper.ExpireGoodTillOrders(ctx.WithBlockHeader(abci.Header{Height: 20, Time: blockTime}), nil)
+	require.Len(t, keeper.GetAllOrdersForPair("ABC-000_BNB"), 2)
+	require.Len(t, keeper.GetAllStopOrders()["ABC-000_BNB"], 0)
+	_, ok := keeper.OrderExists("ABC-000_BNB", "1")
+	require.False(t, ok)
+
+	// expired by time
+	keeper.ExpireGoodTillOrders(ctx.WithBlockHeader(abci.Header{Height: 21, Time: blockTime.Add(time.Minute)}), nil)
+	require.Len(t, keeper.GetAllOrdersForPair("ABC-000_BNB"), 1)
+	_, ok = keeper.OrderExists("ABC-000_BNB", "3")
+	require.True(t, ok)
+	buys, _ := keeper.engines["ABC-000_BNB"].Book.GetAllLevels()
+	require.Len(t, buys, 1)
+	require.Equal(t, int64(1e6), buys[0].TotalLeavesQty())
+
+	require.Equal(t, sdk.NewFee(sdk.Coins{sdk.NewCoin("BNB", 6e4)}, sdk.FeeForProposer), fees.Pool.BlockFees())
+	acc = am.GetAccount(ctx, addr)
+	require.Equal(t, sdk.Coins{sdk.NewCoin("BNB", 1e4)}, acc.(types.NamedAccount).GetLockedCoins())
+	require.Equal(t, sdk.Coins{
+		sdk.NewCoin("ABC-000", 1e8),
+		sdk.NewCoin("BNB", 1e6+5e4-6e4),
+	}.Sort(), acc.GetCoins())
+	fees.Pool.Clear()
+}
+
+func TestKeeper_IcebergOrder(t *testing.T) {
+	upgrade.Mgr.AddUpgradeHeight(upgrade.BEP19, -1)
+	defer resetChainVersion()
+	ctx, am, keeper := setup()
+	_, acc := testutils.NewAccount(ctx, am, 0)
+	addr := acc.GetAddress()
+	keeper.AddEngine(dextypes.NewTradingPair("XYZ-000", "BNB", 1e8))
+
+	msg := NewNewOrderMsg(addr, "1", Side.SELL, "XYZ-000_BNB", 1e8, 10e8)
+	msg.DisplayQty = 2e8
+	keeper.AddOrder(OrderInfo{msg, 10, 0, 10, 0, 0, "", 0}, false)
+	keeper.AddOrder(OrderInfo{NewNewOrderMsg(addr, "2", Side.SELL, "XYZ-000_BNB", 1e8, 1e8), 10, 0, 10, 0, 0, "", 0}, false)
+	keeper.MatchSymbols(10, 0, true)
+
+	// only the display slice of the iceberg order is visible
+	levels, _ := keeper.GetOrderBookLevels("XYZ-000_BNB", 5)
+	require.Equal(t, utils.Fixed8(3e8), levels[0].SellQty)
+	require.Equal(t, int64(3e8), keeper.GetOrderBooks(5)["XYZ-000_BNB"].Sells[1e8])
+
+	// the buy order uses up the slice, the iceberg order is refreshed and moved to the back
+	keeper.AddOrder(OrderInfo{NewNewOrderMsg(addr, "3", Side.BUY, "XYZ-000_BNB", 1e8, 5e8), 11, 0, 11, 0, 0, "", 0}, false)
+	keeper.MatchSymbols(11, 0, true)
+	iceberg := keeper.GetAllOrdersForPair("XYZ-000_BNB")["1"]
+	require.True(t, iceberg.CumQty > 2e8)
+	_, sells := keeper.engines["XYZ-000_BNB"].Book.GetAllLevels()
+	require.Len(t, sells, 1)
+	require.Equal(t, "2", sells[0].Orders[0].Id)
+	require.Equal(t, int64(10), sells[0].Orders[0].Time)
+	require.Equal(t, "1", sells[0].Orders[1].Id)
+	require.Equal(t, int64(11), sells[0].Orders[1].Time)
+	levels, _ = keeper.GetOrderBookLevels("XYZ-000_BNB", 5)
+	require.Equal(t, utils.Fixed8(iceberg.VisibleQty(iceberg.CumQty)+sells[0].Orders[0].LeavesQty()), levels[0].SellQty)
+	require.True(t, int64(levels[0].SellQty) < sells[0].TotalLeavesQty())
+}
+
+func TestKeeper_StopLimitOrder(t *testing.T) {
+	ctx, am, keeper := setup()
+	keeper.EnablePublish()
+	keeper.FeeManager.UpdateConfig(NewTestFeeConfig())
+	_, acc := testutils.NewAccount(ctx, am, 0)
+	addr := acc.GetAddress()
+	pair := dextypes.NewTradingPair("XYZ-000", "BNB", 1e8)
+	keeper.PairMapper.AddTradingPair(ctx, pair)
+	keeper.AddEngine(pair)
+	keeper.AddOrder(OrderInfo{NewStopLimitOrderMsg(addr, "1", Side.BUY, "XYZ-000_BNB", 1.1e8, 1.2e8, 1e8), 10000, 0, 10000, 0, 0, "", 0}, false)
+	keeper.AddOrder(OrderInfo{NewStopLimitOrderMsg(addr, "2", Side.SELL, "XYZ-000_BNB", 0.9e8, 0.9e8, 1e8), 10000, 0, 10000, 0, 0, "", 0}, false)
+	acc.(types.NamedAccount).SetLockedCoins(sdk.Coins{
+		sdk.NewCoin("BNB", 1.2e8),
+		sdk.NewCoin("XYZ-000", 1e8),
+	}.Sort())
+	am.SetAccount(ctx, acc)
+
+	// stop orders are parked outside the order book
+	buys, sells := keeper.engines["XYZ-000_BNB"].Book.GetAllLevels()
+	require.Len(t, buys, 0)
+	require.Len(t, sells, 0)
+	require.Len(t, keeper.GetAllOrdersForPair("XYZ-000_BNB"), 0)
+	require.Len(t, keeper.GetOpenOrders("XYZ-000_BNB", addr), 2)
+	_, ok := keeper.OrderExists("XYZ-000_BNB", "1")
+	require.True(t, ok)
+
+	keeper.TriggerStopOrders(10001)
+	buys, _ = keeper.engines["XYZ-000_BNB"].Book.GetAllLevels()
+	require.Len(t, buys, 0)
+
+	keeper.engines["XYZ-000_BNB"].LastTradePrice = 1.1e8
+	keeper.TriggerStopOrders(10002)
+	buys, sells = keeper.engines["XYZ-000_BNB"].Book.GetAllLevels()
+	require.Len(t, buys, 1)
+	require.Len(t, sells, 0)
+	require.Equal(t, int64(1.2e8), buys[0].Price)
+	require.Equal(t, int64(10002), buys[0].Orders[0].Time)
+	require.Len(t, keeper.GetAllOrdersForPair("XYZ-000_BNB"), 1)
+	require.Equal(t, OrderType.LIMIT, keeper.GetAllOrdersForPair("XYZ-000_BNB")["1"].OrderType)
+	require.Len(t, keeper.GetAllStopOrders()["XYZ-000_BNB"], 1)
+	require.Equal(t, OrderChanges{
+		{"1", StopPlaced, "", nil},
+		{"2", StopPlaced, "", nil},
+		{"1", StopTriggered, "", nil},
+	}, keeper.GetAllOrderChanges())
+
+	// the untriggered stop order is kept in the snapshot
+	_, err := keeper.SnapShotOrderBook(ctx, 10003)
+	require.Nil(t, err)
+	breathTime, _ := time.Parse(time.RFC3339, "2018-01-02T00:00:01Z")
+	keeper.MarkBreatheBlock(ctx, 10003, breathTime)
+	keeper2 := NewDexKeeper(keeper.storeKey, am, keeper.PairMapper, keeper.codespace, 2, keeper.cdc, false)
+	_, err = keeper2.LoadOrderBookSnapshot(ctx, 10003, breathTime, 0, 1)
+	require.Nil(t, err)
+	require.Len(t, keeper2.GetAllOrdersForPair("XYZ-000_BNB"), 1)
+	require.Len(t, keeper2.GetAllStopOrders()["XYZ-000_BNB"], 1)
+	require.Equal(t, OrderType.STOP_LIMIT, keeper2.GetAllStopOrders()["XYZ-000_BNB"]["2"].OrderType)
+
+	// both the triggered and the untriggered stop orders expire
+	keeper.MarkBreatheBlock(ctx, 15000, breathTime)
+	keeper.ExpireOrders(ctx, breathTime.AddDate(0, 0, 3), nil)
+	require.Len(t, keeper.GetAllOrdersForPair("XYZ-000_BNB"), 0)
+	require.Len(t, keeper.GetAllStopOrders()["XYZ-000_BNB"], 0)
+	acc = am.GetAccount(ctx, addr)
+	require.Equal(t, sdk.Coins(nil), acc.(types.NamedAccount).GetLockedCoins())
+	fees.Pool.Clear()
+}
+
 func TestKeeper_ExpireOrdersBasedOnPrice(t *testing.T) {
 	setChainVersion()
 	defer resetChainVersion()
@@ -788,6 +1053,122 @@ func updateLotSize(t *testing.T, symbol string) {
 	assert.Equal(int64(1e3), keeper.engines[tradingPair.GetSymbol()].LotSize)
 }
 
+func TestKeeper_SetOrderBookType(t *testing.T) {
+	assert := assert.New(t)
+	cdc := MakeCodec()
+	keeper := MakeKeeper(cdc)
+	abcPair := dextypes.NewTradingPair("ABC-000", "BNB", 1e8)
+	xyzPair := dextypes.NewTradingPair("XYZ-000", "BNB", 1e8)
+	keeper.AddEngine(abcPair)
+	keeper.engines[abcPair.GetSymbol()].Book.InsertOrder("1", me.BUYSIDE, 100, 99e6, 1e6)
+	keeper.engines[abcPair.GetSymbol()].Book.InsertOrder("2", me.SELLSIDE, 100, 101e6, 1e6)
+
+	assert.EqualError(keeper.SetOrderBookType(abcPair.GetSymbol(), "skiplist"), "unknown order book type skiplist")
+	assert.IsType(&me.OrderBookOnULList{}, keeper.engines[abcPair.GetSymbol()].Book)
+
+	// the orders of the existing engine are moved to the new order book
+	assert.NoError(keeper.SetOrderBookType("abc-000_bnb", me.OrderBookTypeBTree))
+	book := keeper.engines[abcPair.GetSymbol()].Book
+	assert.IsType(&me.OrderBookOnBTree{}, book)
+	buys, sells := book.GetAllLevels()
+	assert.Equal([]me.PriceLevel{{Price: 99e6, Orders: []me.OrderPart{{Id: "1", Time: 100, Qty: 1e6}}}}, buys)
+	assert.Equal([]me.PriceLevel{{Price: 101e6, Orders: []me.OrderPart{{Id: "2", Time: 100, Qty: 1e6}}}}, sells)
+
+	// the engine added later uses the order book type
+	assert.NoError(keeper.SetOrderBookType(xyzPair.GetSymbol(), me.OrderBookTypeBTree))
+	keeper.AddEngine(xyzPair)
+	assert.IsType(&me.OrderBookOnBTree{}, keeper.engines[xyzPair.GetSymbol()].Book)
+}
+
+func TestKeeper_TradingStatus(t *testing.T) {
+	assert := assert.New(t)
+	cdc := MakeCodec()
+	keeper := MakeKeeper(cdc)
+	cms := MakeCMS(nil)
+	ctx := sdk.NewContext(cms, abci.Header{}, sdk.RunTxModeDeliver, log.NewNopLogger())
+	_, acc := testutils.PrivAndAddr()
+	abcPair := dextypes.NewTradingPair("ABC-000", "BNB", 1e8)
+	abcPair.Status = dextypes.TradingStatusHalted
+	xyzPair := dextypes.NewTradingPair("XYZ-000", "BNB", 1e8)
+	for _, pair := range []dextypes.TradingPair{abcPair, xyzPair} {
+		assert.NoError(keeper.PairMapper.AddTradingPair(ctx, pair))
+		keeper.AddEngine(pair)
+		msg := NewNewOrderMsg(acc, GenerateOrderID(1, acc), Side.BUY, pair.GetSymbol(), 1e8, 1e8)
+		assert.NoError(keeper.AddOrder(OrderInfo{msg, 42, 0, 42, 0, 0, "", 0}, false))
+	}
+
+	// the status of the pair is loaded with its engine, and only the trading pairs are matched
+	assert.Equal(dextypes.TradingStatusHalted, keeper.GetTradingStatus("abc-000_bnb"))
+	assert.Equal([]string{xyzPair.GetSymbol()}, keeper.SelectSymbolsToMatch(42, false))
+
+	assert.NoError(keeper.SetTradingStatus(ctx, abcPair.GetSymbol(), dextypes.TradingStatusTrading, 1))
+	assert.NoError(keeper.SetTradingStatus(ctx, xyzPair.GetSymbol(), dextypes.TradingStatusCancelOnly, 2))
+	assert.Equal([]string{abcPair.GetSymbol()}, keeper.SelectSymbolsToMatch(42, false))
+	pairs := keeper.PairMapper.ListAllTradingPairs(ctx)
+	assert.Equal(dextypes.TradingStatusTrading, pairs[0].Status)
+	assert.Equal(dextypes.TradingStatusCancelOnly, pairs[1].Status)
+}
+
+func TestKeeper_GetIndicativePrice(t *testing.T) {
+	assert := assert.New(t)
+	keeper := initKeeper()
+	keeper.AddEngine(dextypes.NewTradingPair("XYZ-000", "BNB", 1e8))
+	_, err := keeper.GetIndicativePrice("ABC-000_BNB")
+	assert.EqualError(err, "trading pair ABC-000_BNB does not exist")
+	price, err := keeper.GetIndicativePrice("XYZ-000_BNB")
+	assert.NoError(err)
+	assert.Equal(store.IndicativePrice{}, price)
+
+	msg := NewNewOrderMsg(zc, ZcAddr+"-0", Side.BUY, "XYZ-000_BNB", 1e8, 1e8)
+	assert.NoError(keeper.AddOrder(OrderInfo{msg, 42, 84, 42, 84, 0, "", 0}, false))
+	msg = NewNewOrderMsg(zz, ZzAddr+"-0", Side.SELL, "XYZ-000_BNB", 1e8, 3e8)
+	assert.NoError(keeper.AddOrder(OrderInfo{msg, 42, 84, 42, 84, 0, "", 0}, false))
+	price, err = keeper.GetIndicativePrice("XYZ-000_BNB")
+	assert.NoError(err)
+	assert.Equal(store.IndicativePrice{Price: 1e8, Quantity: 1e8, Surplus: 2e8, SurplusSide: "SELL"}, price)
+	assert.Len(keeper.GetOpenOrders("XYZ-000_BNB", zz), 1)
+}
+
+func TestKeeper_GetOrderBookL3(t *testing.T) {
+	assert := assert.New(t)
+	keeper := initKeeper()
+	keeper.AddEngine(dextypes.NewTradingPair("XYZ-000", "BNB", 1e8))
+	pair := "XYZ-000_BNB"
+	buys, sells := keeper.GetOrderBookL3(pair, 0, 10)
+	assert.Empty(buys)
+	assert.Empty(sells)
+
+	addOrder := func(id string, side int8, price, qty, displayQty, height int64) {
+		msg := NewNewOrderMsg(zc, id, side, pair, price, qty)
+		msg.DisplayQty = displayQty
+		assert.NoError(keeper.AddOrder(OrderInfo{msg, height, 0, height, 0, 0, "", 0}, false))
+	}
+	addOrder(ZcAddr+"-1", Side.BUY, 1e8, 5e8, 0, 42)
+	addOrder(ZcAddr+"-2", Side.BUY, 1e8, 3e8, 1e8, 43)
+	addOrder(ZcAddr+"-3", Side.BUY, 0.9e8, 1e8, 0, 44)
+	addOrder(ZcAddr+"-4", Side.BUY, 0.8e8, 1e8, 0, 45)
+	addOrder(ZcAddr+"-5", Side.SELL, 1.1e8, 2e8, 0, 46)
+
+	// the orders are in time priority, and only the display slice of the iceberg order is shown
+	buys, sells = keeper.GetOrderBookL3(pair, 0, 2)
+	assert.Equal([]store.OrderBookL3Level{
+		{Price: 1e8, Orders: []store.OrderBookL3Order{
+			{Id: ZcAddr + "-1", Quantity: 5e8, CreatedHeight: 42},
+			{Id: ZcAddr + "-2", Quantity: 1e8, CreatedHeight: 43},
+		}},
+		{Price: 0.9e8, Orders: []store.OrderBookL3Order{{Id: ZcAddr + "-3", Quantity: 1e8, CreatedHeight: 44}}},
+	}, buys)
+	assert.Equal([]store.OrderBookL3Level{
+		{Price: 1.1e8, Orders: []store.OrderBookL3Order{{Id: ZcAddr + "-5", Quantity: 2e8, CreatedHeight: 46}}},
+	}, sells)
+
+	buys, sells = keeper.GetOrderBookL3(pair, 2, 2)
+	assert.Equal([]store.OrderBookL3Level{
+		{Price: 0.8e8, Orders: []store.OrderBookL3Order{{Id: ZcAddr + "-4", Quantity: 1e8, CreatedHeight: 45}}},
+	}, buys)
+	assert.Empty(sells)
+}
+
 func TestOpenOrders_AfterMatch(t *testing.T) {
 	addOrderAfterMatch(t, "NNB-123")
 }
@@ -919,6 +1300,7 @@ func TestKeeper_DelistTradingPair(t *testing.T) {
 	keeper.DelistTradingPair(ctx, "XYZ-000_BNB", nil)
 	assert.Equal(0, len(keeper.GetAllOrders()))
 	assert.Equal(0, len(keeper.engines))
+	assert.Empty(keeper.OrderKeepers[0].(*BEP2OrderKeeper).senderOrders)
 	assert.Equal(0, len(keeper.PairMapper.GetRecentPrices(ctx, pricesStoreEvery, numPricesStored)))
 
 	expectFees := sdk.NewFee(sdk.Coins{
@@ -1172,3 +1554,305 @@ func setChainVersion() {
 func resetChainVersion() {
 	upgrade.Mgr.Config.HeightMap = nil
 }
+
+func TestKeeper_Klines(t *testing.T) {
+	assert := assert.New(t)
+	keeper := initKeeper()
+	keeper.AddEngine(dextypes.NewTradingPair("XYZ-000", "BNB", 1e8))
+	pair := "XYZ-000_BNB"
+	_, err := keeper.GetKlines(pair, KlineInterval1m, 10)
+	assert.EqualError(err, "klines are not maintained by this node")
+	keeper.SetKlineStore(db.NewMemDB())
+	_, err = keeper.GetKlines(pair, "2m", 10)
+	assert.EqualError(err, "invalid kline interval 2m, supported intervals: 1m,5m,1h,1d")
+	_, err = keeper.GetKlines("ABC-000_BNB", KlineInterval1m, 10)
+	assert.EqualError(err, "trading pair ABC-000_BNB does not exist")
+
+	seq := 0
+	trade := func(height, seconds, price, qty int64) {
+		seq++
+		msg := NewNewOrderMsg(zc, ZcAddr+"-"+strconv.Itoa(seq), Side.BUY, pair, price, qty)
+		assert.NoError(keeper.AddOrder(OrderInfo{msg, height, 0, height, 0, 0, "", 0}, false))
+		msg = NewNewOrderMsg(zz, ZzAddr+"-"+strconv.Itoa(seq), Side.SELL, pair, price, qty)
+		assert.NoError(keeper.AddOrder(OrderInfo{msg, height, 0, height, 0, 0, "", 0}, false))
+		keeper.MatchSymbols(height, seconds*1e9, false)
+	}
+	trade(42, 60010, 1e8, 1e8)
+	trade(43, 60050, 1.2e8, 2e8)
+	klines, err := keeper.GetKlines(pair, KlineInterval1m, 10)
+	assert.NoError(err)
+	assert.Equal([]store.Kline{{
+		OpenTime: 60000, CloseTime: 60059, Open: 1e8, High: 1.2e8, Low: 1e8, Close: 1.2e8,
+		Volume: 3e8, QuoteVolume: 3.4e8, NumOfTrades: 2,
+	}}, klines)
+	assert.Len(keeper.GetKlineUpdates(), len(KlineIntervals))
+
+	trade(44, 60070, 0.9e8, 1e8)
+	klines, err = keeper.GetKlines(pair, KlineInterval1m, 10)
+	assert.NoError(err)
+	assert.Len(klines, 2)
+	assert.Equal(int64(60000), klines[0].OpenTime)
+	klines, err = keeper.GetKlines(pair, KlineInterval1m, 1)
+	assert.NoError(err)
+	assert.Equal([]store.Kline{{
+		OpenTime: 60060, CloseTime: 60119, Open: 0.9e8, High: 0.9e8, Low: 0.9e8, Close: 0.9e8,
+		Volume: 1e8, QuoteVolume: 0.9e8, NumOfTrades: 1,
+	}}, klines)
+	fiveMinutes := store.Kline{
+		OpenTime: 60000, CloseTime: 60299, Open: 1e8, High: 1.2e8, Low: 0.9e8, Close: 0.9e8,
+		Volume: 4e8, QuoteVolume: 4.3e8, NumOfTrades: 3,
+	}
+	klines, err = keeper.GetKlines(pair, KlineInterval5m, 10)
+	assert.NoError(err)
+	assert.Equal([]store.Kline{fiveMinutes}, klines)
+
+	// the trades of a replayed block are not counted twice
+	trade(44, 60070, 0.9e8, 1e8)
+	klines, err = keeper.GetKlines(pair, KlineInterval5m, 10)
+	assert.NoError(err)
+	assert.Equal([]store.Kline{fiveMinutes}, klines)
+	assert.Empty(keeper.GetKlineUpdates())
+}
+
+func TestKeeper_GetTicker(t *testing.T) {
+	assert := assert.New(t)
+	keeper := initKeeper()
+	keeper.AddEngine(dextypes.NewTradingPair("XYZ-000", "BNB", 1e8))
+	pair := "XYZ-000_BNB"
+	_, err := keeper.GetTicker("ABC-000_BNB")
+	assert.EqualError(err, "trading pair ABC-000_BNB does not exist")
+
+	seq := 0
+	addOrder := func(sender sdk.AccAddress, addr string, side int8, height, price, qty int64) {
+		seq++
+		msg := NewNewOrderMsg(sender, addr+"-"+strconv.Itoa(seq), side, pair, price, qty)
+		assert.NoError(keeper.AddOrder(OrderInfo{msg, height, 0, height, 0, 0, "", 0}, false))
+	}
+	trade := func(height, seconds, price, qty int64) {
+		addOrder(zc, ZcAddr, Side.BUY, height, price, qty)
+		addOrder(zz, ZzAddr, Side.SELL, height, price, qty)
+		keeper.MatchSymbols(height, seconds*1e9, false)
+	}
+	trade(42, 120010, 1e8, 1e8)
+	trade(43, 120070, 1.2e8, 2e8)
+	trade(44, 120130, 0.9e8, 1e8)
+	addOrder(zc, ZcAddr, Side.BUY, 45, 0.8e8, 5e8)
+	addOrder(zz, ZzAddr, Side.SELL, 45, 1.5e8, 1e8)
+	ticker, err := keeper.GetTicker(pair)
+	assert.NoError(err)
+	assert.Equal(store.Ticker{
+		Symbol: pair, OpenTime: 120130 - 86400, CloseTime: 120130,
+		LastPrice: 0.9e8, OpenPrice: 1e8, HighPrice: 1.2e8, LowPrice: 0.9e8, Volume: 4e8, QuoteVolume: 4.3e8, Count: 3,
+		BidPrice: 0.8e8, BidQuantity: 5e8, AskPrice: 1.5e8, AskQuantity: 1e8,
+	}, ticker)
+
+	// the trades of the first minute are out of the 24 hours
+	trade(46, 120060+86400, 1.1e8, 1e8)
+	assert.Equal([]store.Ticker{{
+		Symbol: pair, OpenTime: 120060, CloseTime: 120060 + 86400,
+		LastPrice: 1.1e8, OpenPrice: 1.2e8, HighPrice: 1.2e8, LowPrice: 0.9e8, Volume: 4e8, QuoteVolume: 4.4e8, Count: 3,
+		BidPrice: 0.8e8, BidQuantity: 5e8, AskPrice: 1.5e8, AskQuantity: 1e8,
+	}}, keeper.GetTickers())
+}
+
+func TestKeeper_GetRecentTrades(t *testing.T) {
+	assert := assert.New(t)
+	upgrade.Mgr.AddUpgradeHeight(upgrade.BEP19, -1)
+	defer resetChainVersion()
+	keeper := initKeeper()
+	keeper.AddEngine(dextypes.NewTradingPair("XYZ-000", "BNB", 1e8))
+	pair := "XYZ-000_BNB"
+	_, err := keeper.GetRecentTrades(pair, 10)
+	assert.EqualError(err, "recent trades are not maintained by this node")
+	memDB := db.NewMemDB()
+	keeper.SetRecentTradesStore(func() db.DB { return memDB })
+	_, err = keeper.GetRecentTrades("ABC-000_BNB", 10)
+	assert.EqualError(err, "trading pair ABC-000_BNB does not exist")
+	trades, err := keeper.GetRecentTrades(pair, 10)
+	assert.NoError(err)
+	assert.Empty(trades)
+
+	addOrder := func(sender sdk.AccAddress, id string, side int8, height, price, qty int64) {
+		msg := NewNewOrderMsg(sender, id, side, pair, price, qty)
+		assert.NoError(keeper.AddOrder(OrderInfo{msg, height, 0, height, 0, 0, "", 0}, false))
+	}
+	addOrder(zz, ZzAddr+"-1", Side.SELL, 42, 1e8, 3e8)
+	keeper.MatchSymbols(42, 42e9, false)
+	addOrder(zc, ZcAddr+"-1", Side.BUY, 43, 1e8, 1e8)
+	keeper.MatchSymbols(43, 43e9, false)
+	addOrder(zc, ZcAddr+"-2", Side.BUY, 44, 1e8, 2e8)
+	keeper.MatchSymbols(44, 44e9, false)
+	trades, err = keeper.GetRecentTrades(pair, 10)
+	assert.NoError(err)
+	assert.Equal([]store.RecentTrade{
+		{Price: 1e8, Quantity: 1e8, BuyerOrderId: ZcAddr + "-1", SellerOrderId: ZzAddr + "-1", MakerSide: "SELL", Height: 43, Time: 43e9},
+		{Price: 1e8, Quantity: 2e8, BuyerOrderId: ZcAddr + "-2", SellerOrderId: ZzAddr + "-1", MakerSide: "SELL", Height: 44, Time: 44e9},
+	}, trades)
+	trades, err = keeper.GetRecentTrades(pair, 1)
+	assert.NoError(err)
+	assert.Equal(int64(44), trades[0].Height)
+
+	// the trades after the breathe block are rebuilt by replaying the blocks
+	keeper.SnapshotRecentTrades(44)
+	keeper2 := initKeeper()
+	keeper2.AddEngine(dextypes.NewTradingPair("XYZ-000", "BNB", 1e8))
+	keeper2.SetRecentTradesStore(func() db.DB { return memDB })
+	keeper2.recentTrades.load(43)
+	trades, err = keeper2.GetRecentTrades(pair, 10)
+	assert.NoError(err)
+	assert.Len(trades, 1)
+	assert.Equal(ZcAddr+"-1", trades[0].BuyerOrderId)
+}
+
+func TestKeeper_GetClosedOrders(t *testing.T) {
+	assert := assert.New(t)
+	keeper := initKeeper()
+	keeper.AddEngine(dextypes.NewTradingPair("XYZ-000", "BNB", 1e8))
+	pair := "XYZ-000_BNB"
+	params := store.ClosedOrdersQueryParams{Address: ZzAddr, Limit: 10}
+	_, err := keeper.GetClosedOrders(params)
+	assert.EqualError(err, "order history is not maintained by this node")
+	keeper.EnablePublish()
+	keeper.SetOrderHistoryStore(db.NewMemDB())
+
+	addOrder := func(sender sdk.AccAddress, id string, side int8, height, price, qty int64) {
+		msg := NewNewOrderMsg(sender, id, side, pair, price, qty)
+		assert.NoError(keeper.AddOrder(OrderInfo{msg, height, 0, height, 0, 0, "", 0}, false))
+	}
+	addOrder(zz, ZzAddr+"-1", Side.SELL, 42, 1e8, 1e8)
+	addOrder(zz, ZzAddr+"-2", Side.SELL, 42, 2e8, 1e8)
+	addOrder(zc, ZcAddr+"-1", Side.BUY, 42, 1e8, 1e8)
+	keeper.MatchSymbols(42, 42e9, false)
+	closed := keeper.RecordClosedOrders(42, 42e9)
+	assert.Len(closed, 2)
+
+	keeper.ClearOrderChanges()
+	keeper.UpdateOrderChangeSync(OrderChange{ZzAddr + "-2", Canceled, "", nil}, pair)
+	closed = keeper.RecordClosedOrders(43, 43e9)
+	assert.Equal([]store.ClosedOrder{{
+		Id: ZzAddr + "-2", Symbol: pair, Owner: ZzAddr, Side: Side.SELL, OrderType: OrderType.LIMIT,
+		TimeInForce: TimeInForce.GTE, Price: 2e8, Quantity: 1e8, Status: "Canceled",
+		CreatedHeight: 42, ClosedHeight: 43, ClosedTimestamp: 43e9,
+	}}, closed)
+
+	orders, err := keeper.GetClosedOrders(params)
+	assert.NoError(err)
+	assert.Equal(2, orders.Total)
+	assert.Equal(ZzAddr+"-2", orders.Orders[0].Id)
+	assert.Equal(ZzAddr+"-1", orders.Orders[1].Id)
+	assert.Equal("FullyFill", orders.Orders[1].Status)
+	assert.Equal(utils.Fixed8(1e8), orders.Orders[1].CumQty)
+
+	orders, err = keeper.GetClosedOrders(store.ClosedOrdersQueryParams{Address: ZzAddr, Status: "FullyFill", Limit: 10})
+	assert.NoError(err)
+	assert.Equal(1, orders.Total)
+	orders, err = keeper.GetClosedOrders(store.ClosedOrdersQueryParams{Address: ZzAddr, ToHeight: 42, Limit: 10})
+	assert.NoError(err)
+	assert.Equal(1, orders.Total)
+	orders, err = keeper.GetClosedOrders(store.ClosedOrdersQueryParams{Address: ZzAddr, Symbol: "abc-000_bnb", Limit: 10})
+	assert.NoError(err)
+	assert.Equal(0, orders.Total)
+	orders, err = keeper.GetClosedOrders(store.ClosedOrdersQueryParams{Address: ZzAddr, Offset: 1, Limit: 10})
+	assert.NoError(err)
+	assert.Equal(2, orders.Total)
+	assert.Len(orders.Orders, 1)
+	assert.Equal(ZzAddr+"-1", orders.Orders[0].Id)
+
+	_, err = keeper.GetClosedOrders(store.ClosedOrdersQueryParams{Address: ZzAddr, Status: "Ack", Limit: 10})
+	assert.EqualError(err, "invalid order status Ack")
+	_, err = keeper.GetClosedOrders(store.ClosedOrdersQueryParams{Address: ZzAddr, FromHeight: 43, ToHeight: 42, Limit: 10})
+	assert.EqualError(err, "invalid height range [43, 42]")
+}
+
+func TestKeeper_InitOrderSizeLimits(t *testing.T) {
+	ms, capKey, capKey2 := testutils.SetupMultiStoreForUnitTest()
+	cdc := wire.NewCodec()
+	types.RegisterWire(cdc)
+	cdc.RegisterConcrete(dextypes.TradingPair{}, "dex/TradingPair", nil)
+	cdc.RegisterConcrete(store.RecentPrice{}, "dex/RecentPrice", nil)
+	accountKeeper := auth.NewAccountKeeper(cdc, capKey, types.ProtoAppAccount)
+	pairMapper := store.NewTradingPairMapper(cdc, common.PairStoreKey)
+	ctx := sdk.NewContext(ms, abci.Header{}, sdk.RunTxModeDeliver, log.NewNopLogger())
+	keeper := NewDexKeeper(capKey2, accountKeeper, pairMapper, sdk.NewCodespacer().RegisterNext(dextypes.DefaultCodespace), 2, cdc, false)
+
+	for _, pair := range []dextypes.TradingPair{
+		dextypes.NewTradingPair("XYZ-000", "BNB", 1e8),
+		dextypes.NewTradingPair("BNB", "BUSD-BD1", 300e8),
+		dextypes.NewTradingPair("XYZ-000", "BUSD-BD1", 1e8),
+		dextypes.NewTradingPair("XYZ-000", "ABC-000", 1e8),
+	} {
+		require.NoError(t, pairMapper.AddTradingPair(ctx, pair))
+		keeper.AddEngine(pair)
+	}
+	// the limits already set are kept
+	minNotional, maxQuantity := int64(1e6), int64(1e10)
+	require.NoError(t, keeper.SetOrderSizeLimits(ctx, "XYZ-000_BUSD-BD1", &minNotional, &maxQuantity))
+
+	// the maximum quantity is unlimited unless set by governance
+	keeper.InitOrderSizeLimits(ctx)
+	for _, c := range []struct {
+		base, quote              string
+		minNotional, maxQuantity int64
+	}{
+		{"XYZ-000", "BNB", 1e5, 0},
+		{"BNB", "BUSD-BD1", 3e7, 0},
+		{"XYZ-000", "BUSD-BD1", 1e6, 1e10},
+		{"XYZ-000", "ABC-000", 0, 0},
+	} {
+		pair, err := pairMapper.GetTradingPair(ctx, c.base, c.quote)
+		require.NoError(t, err)
+		require.Equal(t, utils.Fixed8(c.minNotional), pair.MinNotional, pair.GetSymbol())
+		require.Equal(t, utils.Fixed8(c.maxQuantity), pair.MaxQuantity, pair.GetSymbol())
+	}
+}
+
+func TestKeeper_DexParams(t *testing.T) {
+	cdc := MakeCodec()
+	keeper := MakeKeeper(cdc)
+	cms := MakeCMS(nil)
+	ctx := sdk.NewContext(cms, abci.Header{}, sdk.RunTxModeDeliver, log.NewNopLogger())
+	eng := keeper.AddEngine(dextypes.NewTradingPair("XYZ-000", "BNB", 1e8))
+	require.Equal(t, me.STPNone, eng.SelfTradePrevention)
+	require.NotNil(t, eng.OrderSender)
+
+	require.True(t, IsDexParam(&SelfTradePreventionParam{}))
+	require.False(t, IsDexParam(&paramTypes.DexFeeParam{}))
+	require.Error(t, (&SelfTradePreventionParam{Policy: "cancel_all"}).Check())
+
+	require.Error(t, (&CircuitBreakerParam{Band: 3e4, Window: numPricesStored + 1, HaltBlocks: 2}).Check())
+	require.Error(t, (&CircuitBreakerParam{Band: -1}).Check())
+	require.Error(t, (&OrderLimitsParam{MaxOpenOrders: -1}).Check())
+
+	// the invalid params are ignored
+	keeper.updateDexParams(ctx, []paramTypes.FeeParam{&paramTypes.DexFeeParam{}, &SelfTradePreventionParam{Policy: "cancel_oldest"},
+		&CircuitBreakerParam{Band: 3e4, Window: 1, HaltBlocks: 2}, &OrderLimitsParam{MaxOpenOrders: 100}})
+	keeper.updateDexParams(ctx, []paramTypes.FeeParam{&SelfTradePreventionParam{Policy: "cancel_all"}, &CircuitBreakerParam{Band: -1},
+		&OrderLimitsParam{MaxNewOrdersPerBlock: -1}})
+	require.Equal(t, me.STPCancelOldest, eng.SelfTradePrevention)
+	require.Equal(t, CircuitBreakerConfig{Band: 3e4, Window: 1, HaltBlocks: 2}, keeper.GetCircuitBreakerConfig())
+	require.Equal(t, OrderLimitsConfig{MaxOpenOrders: 100}, keeper.GetOrderLimitsConfig())
+
+	// the params are loaded from the store after a restart
+	keeper = MakeKeeper(cdc)
+	keeper.loadDexParams(ctx)
+	eng = keeper.AddEngine(dextypes.NewTradingPair("XYZ-000", "BNB", 1e8))
+	require.Equal(t, me.STPCancelOldest, eng.SelfTradePrevention)
+	require.Equal(t, CircuitBreakerConfig{Band: 3e4, Window: 1, HaltBlocks: 2}, keeper.GetCircuitBreakerConfig())
+	require.Equal(t, OrderLimitsConfig{MaxOpenOrders: 100}, keeper.GetOrderLimitsConfig())
+}
+
+func TestKeeper_SelectResumedSymbols(t *testing.T) {
+	keeper := MakeKeeper(MakeCodec())
+	for _, symbol := range []string{"XYZ-000_BNB", "ABC-000_BNB", "XYZ-000_BTC-000", "ABC-000_BTC-000"} {
+		keeper.circuitBreakerTrips[symbol] = []*CircuitBreakerTrip{{Symbol: symbol, TripHeight: 10, ResumeHeight: 13}}
+	}
+	keeper.circuitBreakerTrips["DEF-000_BNB"] = []*CircuitBreakerTrip{{Symbol: "DEF-000_BNB", TripHeight: 10, ResumeHeight: 14}}
+
+	// the resumed pairs are appended in order
+	for i := 0; i < 10; i++ {
+		require.Equal(t, []string{"XYZ-000_BNB", "ABC-000_BNB", "ABC-000_BTC-000", "XYZ-000_BTC-000"},
+			keeper.selectResumedSymbols([]string{"XYZ-000_BNB"}, 13))
+		require.Equal(t, []string{"ABC-000_BNB", "ABC-000_BTC-000", "XYZ-000_BNB", "XYZ-000_BTC-000"},
+			keeper.selectResumedSymbols(nil, 13))
+	}
+}
@@ -0,0 +1,231 @@
+package order
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	dbm "github.com/tendermint/tendermint/libs/db"
+
+	"github.com/bnb-chain/node/common/utils"
+	me "github.com/bnb-chain/node/plugins/dex/matcheng"
+	"github.com/bnb-chain/node/plugins/dex/store"
+	dexUtils "github.com/bnb-chain/node/plugins/dex/utils"
+	"github.com/bnb-chain/node/wire"
+)
+
+const (
+	KlineInterval1m = "1m"
+	KlineInterval5m = "5m"
+	KlineInterval1h = "1h"
+	KlineInterval1d = "1d"
+
+	maxKlinesKept = 1000 // the number of the latest klines kept for every pair and interval
+)
+
+// KlineIntervals are the intervals of the klines maintained for every trading pair
+var KlineIntervals = []string{KlineInterval1m, KlineInterval5m, KlineInterval1h, KlineInterval1d}
+
+var klineIntervalSeconds = map[string]int64{
+	KlineInterval1m: 60,
+	KlineInterval5m: 5 * 60,
+	KlineInterval1h: 60 * 60,
+	KlineInterval1d: 24 * 60 * 60,
+}
+
+type klineKey struct {
+	symbol   string
+	interval string
+}
+
+// KlineUpdate is a kline changed by the trades of a block
+type KlineUpdate struct {
+	Symbol   string
+	Interval string
+	store.Kline
+}
+
+// KlineStore maintains the klines of the trading pairs from the trades of every match. The klines are kept
+// in a database out of the consensus state, so the app hash is not affected by them.
+type KlineStore struct {
+	db  dbm.DB
+	cdc *wire.Codec
+
+	mtx         sync.Mutex
+	lastHeights map[string]int64          // symbol -> the last height whose trades are added to the klines
+	latest      map[klineKey]*store.Kline // the latest kline of every pair and interval
+	dirty       map[klineKey]*store.Kline // the klines changed since the last flush
+	updates     []KlineUpdate             // the klines changed by the last flushed block
+}
+
+func NewKlineStore(db dbm.DB, cdc *wire.Codec) *KlineStore {
+	return &KlineStore{
+		db:          db,
+		cdc:         cdc,
+		lastHeights: make(map[string]int64),
+		latest:      make(map[klineKey]*store.Kline),
+		dirty:       make(map[klineKey]*store.Kline),
+	}
+}
+
+func klinePrefix(symbol, interval string) []byte {
+	return []byte(fmt.Sprintf("kline_%s_%s_", symbol, interval))
+}
+
+func klineDBKey(symbol, interval string, openTime int64) []byte {
+	return append(klinePrefix(symbol, interval), []byte(fmt.Sprintf("%020d", openTime))...)
+}
+
+func klineLastHeightKey(symbol string) []byte {
+	return []byte(fmt.Sprintf("lastheight_%s", symbol))
+}
+
+// addTrades adds the trades of the pair concluded at the height and the timestamp in nanoseconds to the klines.
+// The trades of a height already added are ignored, as the blocks are replayed when the order book is recovered.
+func (ks *KlineStore) addTrades(symbol string, height, timestamp int64, trades []me.Trade) {
+	if len(trades) == 0 {
+		return
+	}
+	ks.mtx.Lock()
+	defer ks.mtx.Unlock()
+	lastHeight, ok := ks.lastHeights[symbol]
+	if !ok {
+		if bz := ks.db.Get(klineLastHeightKey(symbol)); bz != nil {
+			ks.cdc.MustUnmarshalBinaryBare(bz, &lastHeight)
+		}
+	}
+	if height <= lastHeight {
+		return
+	}
+	ks.lastHeights[symbol] = height
+
+	seconds := timestamp / 1e9
+	for _, interval := range KlineIntervals {
+		key := klineKey{symbol, interval}
+		openTime := seconds - seconds%klineIntervalSeconds[interval]
+		kline := ks.getLatest(key)
+		if kline == nil || kline.OpenTime != openTime {
+			kline = &store.Kline{
+				OpenTime:  openTime,
+				CloseTime: openTime + klineIntervalSeconds[interval] - 1,
+				Open:      utils.Fixed8(trades[0].LastPx),
+				High:      utils.Fixed8(trades[0].LastPx),
+				Low:       utils.Fixed8(trades[0].LastPx),
+			}
+			ks.latest[key] = kline
+		}
+		for _, t := range trades {
+			price := utils.Fixed8(t.LastPx)
+			if price > kline.High {
+				kline.High = price
+			}
+			if price < kline.Low {
+				kline.Low = price
+			}
+			kline.Close = price
+			kline.Volume += utils.Fixed8(t.LastQty)
+			kline.QuoteVolume += utils.Fixed8(dexUtils.CalBigNotionalInt64(t.LastPx, t.LastQty))
+			kline.NumOfTrades++
+		}
+		ks.dirty[key] = kline
+	}
+}
+
+func (ks *KlineStore) getLatest(key klineKey) *store.Kline {
+	if kline, ok := ks.latest[key]; ok {
+		return kline
+	}
+	prefix := klinePrefix(key.symbol, key.interval)
+	iter := ks.db.ReverseIterator(prefix, sdk.PrefixEndBytes(prefix))
+	defer iter.Close()
+	if !iter.Valid() {
+		return nil
+	}
+	var kline store.Kline
+	ks.cdc.MustUnmarshalBinaryBare(iter.Value(), &kline)
+	ks.latest[key] = &kline
+	return &kline
+}
+
+// flush writes the klines changed since the last flush into the database, and drops the klines out of the
+// latest maxKlinesKept ones.
+func (ks *KlineStore) flush() {
+	ks.mtx.Lock()
+	defer ks.mtx.Unlock()
+	ks.updates = make([]KlineUpdate, 0, len(ks.dirty))
+	if len(ks.dirty) == 0 {
+		return
+	}
+	batch := ks.db.NewBatch()
+	defer batch.Close()
+	for key, kline := range ks.dirty {
+		batch.Set(klineDBKey(key.symbol, key.interval, kline.OpenTime), ks.cdc.MustMarshalBinaryBare(*kline))
+		prefix := klinePrefix(key.symbol, key.interval)
+		expired := klineDBKey(key.symbol, key.interval, kline.OpenTime-maxKlinesKept*klineIntervalSeconds[key.interval])
+		iter := ks.db.Iterator(prefix, expired)
+		for ; iter.Valid(); iter.Next() {
+			batch.Delete(iter.Key())
+		}
+		iter.Close()
+		ks.updates = append(ks.updates, KlineUpdate{key.symbol, key.interval, *kline})
+	}
+	for symbol, height := range ks.lastHeights {
+		batch.Set(klineLastHeightKey(symbol), ks.cdc.MustMarshalBinaryBare(height))
+	}
+	batch.Write()
+	ks.dirty = make(map[klineKey]*store.Kline)
+}
+
+// getKlines returns the latest klines of the pair and the interval, in the ascending order of the open time
+func (ks *KlineStore) getKlines(symbol, interval string, limit int) []store.Kline {
+	klines := make([]store.Kline, 0, limit)
+	prefix := klinePrefix(symbol, interval)
+	iter := ks.db.ReverseIterator(prefix, sdk.PrefixEndBytes(prefix))
+	defer iter.Close()
+	for ; iter.Valid() && len(klines) < limit; iter.Next() {
+		var kline store.Kline
+		ks.cdc.MustUnmarshalBinaryBare(iter.Value(), &kline)
+		klines = append(klines, kline)
+	}
+	for i, j := 0, len(klines)-1; i < j; i, j = i+1, j-1 {
+		klines[i], klines[j] = klines[j], klines[i]
+	}
+	return klines
+}
+
+// SetKlineStore enables the klines, which are saved in the db
+func (kp *DexKeeper) SetKlineStore(db dbm.DB) {
+	kp.klines = NewKlineStore(db, kp.cdc)
+}
+
+func (kp *DexKeeper) flushKlines() {
+	if kp.klines != nil {
+		kp.klines.flush()
+	}
+}
+
+// GetKlines returns at most limit latest klines of the pair in the interval
+func (kp *DexKeeper) GetKlines(pair, interval string, limit int) ([]store.Kline, error) {
+	if kp.klines == nil {
+		return nil, fmt.Errorf("klines are not maintained by this node")
+	}
+	if _, ok := klineIntervalSeconds[interval]; !ok {
+		return nil, fmt.Errorf("invalid kline interval %s, supported intervals: %s", interval, strings.Join(KlineIntervals, ","))
+	}
+	pair = strings.ToUpper(pair)
+	if _, ok := kp.engines[pair]; !ok {
+		return nil, fmt.Errorf("trading pair %s does not exist", pair)
+	}
+	return kp.klines.getKlines(pair, interval, limit), nil
+}
+
+// GetKlineUpdates returns the klines changed by the trades of the last matched block
+func (kp *DexKeeper) GetKlineUpdates() []KlineUpdate {
+	if kp.klines == nil {
+		return nil
+	}
+	kp.klines.mtx.Lock()
+	defer kp.klines.mtx.Unlock()
+	return kp.klines.updates
+}
@@ -0,0 +1,50 @@
+package order
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	me "github.com/bnb-chain/node/plugins/dex/matcheng"
+)
+
+func genTradeKey(trade *me.Trade) string {
+	return trade.Bid + "_" + trade.Sid
+}
+
+func (kp *DexKeeper) resetMakerRebates() {
+	kp.tradeRebates = nil
+	kp.roundRebates = nil
+}
+
+// payMakerRebates credits the makers with their shares of the taker fees of the trades, which must have been
+// calculated, and returns the total rebates
+func (kp *DexKeeper) payMakerRebates(ctx sdk.Context, makers TradeTransfers) sdk.Coins {
+	var total sdk.Coins
+	for _, tran := range makers {
+		takerFee := tran.Trade.BuyerFee
+		if tran.IsBuyer() {
+			takerFee = tran.Trade.SellerFee
+		}
+		if takerFee == nil {
+			continue
+		}
+		rebate := kp.FeeManager.CalcMakerRebate(*takerFee)
+		if rebate.IsZero() {
+			continue
+		}
+		acc := kp.am.GetAccount(ctx, tran.accAddress)
+		_ = acc.SetCoins(acc.GetCoins().Plus(rebate))
+		kp.am.SetAccount(ctx, acc)
+		if kp.tradeRebates == nil {
+			kp.tradeRebates = make(map[string]sdk.Coins)
+		}
+		kp.tradeRebates[genTradeKey(tran.Trade)] = rebate
+		total = total.Plus(rebate)
+	}
+	kp.roundRebates = kp.roundRebates.Plus(total)
+	return total
+}
+
+// GetRoundRebates returns the rebates paid to the makers in the last match
+func (kp *DexKeeper) GetRoundRebates() sdk.Coins {
+	return kp.roundRebates
+}
@@ -52,6 +52,7 @@ func (kp *MiniOrderKeeper) supportPairType(pairType SymbolPairType) bool {
 // override
 func (kp *MiniOrderKeeper) initOrders(symbol string) {
 	kp.allOrders[symbol] = map[string]*OrderInfo{}
+	kp.stopOrders[symbol] = map[string]*OrderInfo{}
 	kp.symbolSelector.addSymbolHash(symbol)
 }
 
@@ -79,7 +80,13 @@ func (kp *MiniOrderKeeper) getRoundOrdersNum() int {
 }
 
 func (kp *MiniOrderKeeper) reloadOrder(symbol string, orderInfo *OrderInfo, height int64) {
+	if orderInfo.OrderType == OrderType.STOP_LIMIT {
+		kp.reloadStopOrder(symbol, orderInfo)
+		return
+	}
 	kp.allOrders[symbol][orderInfo.Id] = orderInfo
+	kp.addGoodTillOrder(symbol, orderInfo)
+	kp.addSenderOrder(symbol, orderInfo)
 	//TODO confirm no round orders for mini symbol
 	if kp.collectOrderInfoForPublish {
 		if _, exists := kp.orderInfosForPub[orderInfo.Id]; !exists {
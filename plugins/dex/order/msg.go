@@ -9,6 +9,7 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	txbuilder "github.com/cosmos/cosmos-sdk/x/auth/client/txbuilder"
 
+	"github.com/bnb-chain/node/common/upgrade"
 	"github.com/bnb-chain/node/plugins/dex/matcheng"
 	"github.com/bnb-chain/node/plugins/dex/types"
 )
@@ -16,6 +17,9 @@ import (
 const (
 	RouteNewOrder    = "orderNew"
 	RouteCancelOrder = "orderCancel"
+	RouteAmendOrder  = "orderAmend"
+
+	RouteCancelAllOrders = "orderCancelAll"
 )
 
 // Side/TimeInForce/OrderType are const, following FIX protocol convention
@@ -56,55 +60,101 @@ func SideStringToSideCode(side string) (int8, error) {
 }
 
 const (
-	_           int8 = iota
-	orderMarket int8 = iota
-	orderLimit  int8 = iota
+	_              int8 = iota
+	orderMarket    int8 = iota
+	orderLimit     int8 = iota
+	orderStopLimit int8 = iota
 )
 
 // OrderType is an enum of order type options supported by the matching engine
 var OrderType = struct {
-	LIMIT  int8
-	MARKET int8
-}{orderLimit, orderMarket}
+	LIMIT      int8
+	MARKET     int8
+	STOP_LIMIT int8
+}{orderLimit, orderMarket, orderStopLimit}
+
+var orderTypeNames = map[string]int8{
+	"LIMIT":      orderLimit,
+	"MARKET":     orderMarket,
+	"STOP_LIMIT": orderStopLimit,
+}
 
 // IsValidOrderType validates that an order type is valid and supported by the matching engine
 func IsValidOrderType(ot int8) bool {
 	switch ot {
-	case OrderType.LIMIT: // only allow LIMIT for now.
+	case OrderType.LIMIT, OrderType.MARKET, OrderType.STOP_LIMIT:
 		return true
 	default:
 		return false
 	}
 }
 
+// isOrderTypeEnabled returns false for the order types introduced by the DexEnhancements upgrade until it is active
+func isOrderTypeEnabled(ot int8) bool {
+	switch ot {
+	case OrderType.MARKET, OrderType.STOP_LIMIT:
+		return sdk.IsUpgrade(upgrade.DexEnhancements)
+	default:
+		return true
+	}
+}
+
+// OrderTypeStringToOrderTypeCode converts a string like "LIMIT" to its internal order type code
+func OrderTypeStringToOrderTypeCode(ot string) (int8, error) {
+	upperOt := strings.ToUpper(ot)
+	if val, ok := orderTypeNames[upperOt]; ok {
+		return val, nil
+	}
+	return -1, errors.New("order type `" + upperOt + "` not found or supported")
+}
+
 const (
-	_      int8 = iota
-	tifGTE int8 = iota
-	_      int8 = iota
-	tifIOC int8 = iota
+	_           int8 = iota
+	tifGTE      int8 = iota
+	_           int8 = iota
+	tifIOC      int8 = iota
+	tifFOK      int8 = iota
+	tifPostOnly int8 = iota
 )
 
-// TimeInForce is an enum of TIF (Time in Force) options supported by the matching engine
+// TimeInForce is an enum of TIF (Time in Force) options supported by the matching engine.
+// FOK orders are either fully filled in the match round they enter or killed without any fill.
+// POST_ONLY orders are only allowed to rest in the book as makers, they are rejected
+// instead of being executed as takers.
 var TimeInForce = struct {
-	GTE int8
-	IOC int8
-}{tifGTE, tifIOC}
+	GTE       int8
+	IOC       int8
+	FOK       int8
+	POST_ONLY int8
+}{tifGTE, tifIOC, tifFOK, tifPostOnly}
 
 var timeInForceNames = map[string]int8{
-	"GTE": tifGTE,
-	"IOC": tifIOC,
+	"GTE":       tifGTE,
+	"IOC":       tifIOC,
+	"FOK":       tifFOK,
+	"POST_ONLY": tifPostOnly,
 }
 
 // IsValidTimeInForce validates that a tif code is correct
 func IsValidTimeInForce(tif int8) bool {
 	switch tif {
-	case TimeInForce.GTE, TimeInForce.IOC:
+	case TimeInForce.GTE, TimeInForce.IOC, TimeInForce.FOK, TimeInForce.POST_ONLY:
 		return true
 	default:
 		return false
 	}
 }
 
+// isTimeInForceEnabled returns false for the time in force introduced by the DexEnhancements upgrade until it is active
+func isTimeInForceEnabled(tif int8) bool {
+	switch tif {
+	case TimeInForce.FOK, TimeInForce.POST_ONLY:
+		return sdk.IsUpgrade(upgrade.DexEnhancements)
+	default:
+		return true
+	}
+}
+
 // TifStringToTifCode converts a string like "GTE" to its internal tif code
 func TifStringToTifCode(tif string) (int8, error) {
 	upperTif := strings.ToUpper(tif)
@@ -117,14 +167,18 @@ func TifStringToTifCode(tif string) (int8, error) {
 var _ sdk.Msg = NewOrderMsg{}
 
 type NewOrderMsg struct {
-	Sender      sdk.AccAddress `json:"sender"`
-	Id          string         `json:"id"`
-	Symbol      string         `json:"symbol"`
-	OrderType   int8           `json:"ordertype"`
-	Side        int8           `json:"side"`
-	Price       int64          `json:"price"`
-	Quantity    int64          `json:"quantity"`
-	TimeInForce int8           `json:"timeinforce"`
+	Sender         sdk.AccAddress `json:"sender"`
+	Id             string         `json:"id"`
+	Symbol         string         `json:"symbol"`
+	OrderType      int8           `json:"ordertype"`
+	Side           int8           `json:"side"`
+	Price          int64          `json:"price"`
+	Quantity       int64          `json:"quantity"`
+	TimeInForce    int8           `json:"timeinforce"`
+	StopPrice      int64          `json:"stopprice,omitempty"`      // trigger price, only for stop-limit orders
+	GoodTillHeight int64          `json:"goodtillheight,omitempty"` // the order expires at the end of this block
+	GoodTillTime   int64          `json:"goodtilltime,omitempty"`   // the order expires at the end of the first block at or after this unix time
+	DisplayQty     int64          `json:"displayqty,omitempty"`     // visible slice of an iceberg order, the rest is hidden
 }
 
 // NewNewOrderMsg constructs a new NewOrderMsg
@@ -158,6 +212,45 @@ func NewNewOrderMsgAuto(txBuilder txbuilder.TxBuilder, sender sdk.AccAddress, si
 	}, nil
 }
 
+// NewMarketOrderMsg constructs a new market NewOrderMsg. The price is decided by the match engine
+// when the order is placed, and the unfilled quantity expires after the match like an IOC order.
+func NewMarketOrderMsg(sender sdk.AccAddress, id string, side int8, symbol string, qty int64) NewOrderMsg {
+	msg := NewNewOrderMsg(sender, id, side, symbol, 0, qty)
+	msg.OrderType = OrderType.MARKET
+	msg.TimeInForce = TimeInForce.IOC
+	return msg
+}
+
+// NewStopLimitOrderMsg constructs a new stop-limit NewOrderMsg, which is parked until
+// the last trade price of the pair reaches stopPrice and then enters the book at price
+func NewStopLimitOrderMsg(sender sdk.AccAddress, id string, side int8,
+	symbol string, stopPrice, price int64, qty int64) NewOrderMsg {
+	msg := NewNewOrderMsg(sender, id, side, symbol, price, qty)
+	msg.OrderType = OrderType.STOP_LIMIT
+	msg.StopPrice = stopPrice
+	return msg
+}
+
+// IsGoodTillExpired returns true if the good-till height or time of the order
+// is reached by the block of the given height and unix time
+func (msg NewOrderMsg) IsGoodTillExpired(height, blockTime int64) bool {
+	return (msg.GoodTillHeight > 0 && height >= msg.GoodTillHeight) ||
+		(msg.GoodTillTime > 0 && blockTime >= msg.GoodTillTime)
+}
+
+// IsStopTriggered returns true if a stop order with this side and stop price
+// should be triggered by the given last trade price.
+// buy stops trigger when the price rises to the stop price, sell stops when it falls to it.
+func IsStopTriggered(side int8, stopPrice, lastTradePrice int64) bool {
+	if lastTradePrice <= 0 {
+		return false
+	}
+	if side == Side.BUY {
+		return lastTradePrice >= stopPrice
+	}
+	return lastTradePrice <= stopPrice
+}
+
 // nolint
 func (msg NewOrderMsg) Route() string                { return RouteNewOrder }
 func (msg NewOrderMsg) Type() string                 { return RouteNewOrder }
@@ -229,6 +322,26 @@ func (msg CancelOrderMsg) GetInvolvedAddresses() []sdk.AccAddress {
 	return msg.GetSigners()
 }
 
+// IsIceberg returns true if only a slice of DisplayQty of the order is shown in the order book
+func (msg NewOrderMsg) IsIceberg() bool {
+	return msg.DisplayQty > 0
+}
+
+// VisibleQty returns the quantity of the order shown in the order book after cumQty has been filled.
+// An iceberg order shows what is left of its current display slice, and the slice is replenished
+// from the hidden reserve once it is used up.
+func (msg NewOrderMsg) VisibleQty(cumQty int64) int64 {
+	leavesQty := msg.Quantity - cumQty
+	if !msg.IsIceberg() {
+		return leavesQty
+	}
+	sliceQty := msg.DisplayQty - cumQty%msg.DisplayQty
+	if sliceQty < leavesQty {
+		return sliceQty
+	}
+	return leavesQty
+}
+
 // ValidateBasic is used to quickly disqualify obviously invalid messages quickly
 func (msg NewOrderMsg) ValidateBasic() sdk.Error {
 	// `-` is required in the compound order id: <address>-<sequence>
@@ -242,12 +355,52 @@ func (msg NewOrderMsg) ValidateBasic() sdk.Error {
 	if msg.Quantity <= 0 {
 		return types.ErrInvalidOrderParam("Quantity", fmt.Sprintf("Zero/Negative Number:%d", msg.Quantity))
 	}
-	if msg.Price <= 0 {
+	if !isOrderTypeEnabled(msg.OrderType) {
+		return types.ErrInvalidOrderParam("OrderType", fmt.Sprintf("Order type is not supported yet:%d", msg.OrderType))
+	}
+	if !isTimeInForceEnabled(msg.TimeInForce) {
+		return types.ErrInvalidOrderParam("TimeInForce", fmt.Sprintf("TimeInForce is not supported yet:%d", msg.TimeInForce))
+	}
+	if msg.OrderType == OrderType.MARKET {
+		if msg.Price != 0 {
+			return types.ErrInvalidOrderParam("Price", fmt.Sprintf("Price is not allowed for market orders:%d", msg.Price))
+		}
+		if msg.TimeInForce != TimeInForce.IOC && msg.TimeInForce != TimeInForce.FOK {
+			return types.ErrInvalidOrderParam("TimeInForce", fmt.Sprintf("Market orders must be IOC or FOK:%d", msg.TimeInForce))
+		}
+	} else if msg.Price <= 0 {
 		return types.ErrInvalidOrderParam("Price", fmt.Sprintf("Zero/Negative Number:%d", msg.Price))
 	}
 	if !IsValidOrderType(msg.OrderType) {
 		return types.ErrInvalidOrderParam("OrderType", fmt.Sprintf("Invalid order type:%d", msg.OrderType))
 	}
+	if msg.OrderType == OrderType.STOP_LIMIT {
+		if msg.StopPrice <= 0 {
+			return types.ErrInvalidOrderParam("StopPrice", fmt.Sprintf("Zero/Negative Number:%d", msg.StopPrice))
+		}
+	} else if msg.StopPrice != 0 {
+		return types.ErrInvalidOrderParam("StopPrice", fmt.Sprintf("StopPrice is only allowed for stop-limit orders:%d", msg.StopPrice))
+	}
+	if msg.GoodTillHeight < 0 {
+		return types.ErrInvalidOrderParam("GoodTillHeight", fmt.Sprintf("Negative Number:%d", msg.GoodTillHeight))
+	}
+	if msg.GoodTillTime < 0 {
+		return types.ErrInvalidOrderParam("GoodTillTime", fmt.Sprintf("Negative Number:%d", msg.GoodTillTime))
+	}
+	if (msg.GoodTillHeight != 0 || msg.GoodTillTime != 0) && !sdk.IsUpgrade(upgrade.DexEnhancements) {
+		return types.ErrInvalidOrderParam("GoodTill", "GoodTillHeight and GoodTillTime are not supported yet")
+	}
+	if msg.DisplayQty < 0 || msg.DisplayQty > msg.Quantity {
+		return types.ErrInvalidOrderParam("DisplayQty", fmt.Sprintf("Negative Number or larger than Quantity:%d", msg.DisplayQty))
+	}
+	if msg.DisplayQty != 0 && !sdk.IsUpgrade(upgrade.DexEnhancements) {
+		return types.ErrInvalidOrderParam("DisplayQty", fmt.Sprintf("DisplayQty is not supported yet:%d", msg.DisplayQty))
+	}
+	// only the orders resting in the order book can hide their quantity
+	if msg.IsIceberg() && (msg.OrderType == OrderType.MARKET ||
+		(msg.TimeInForce != TimeInForce.GTE && msg.TimeInForce != TimeInForce.POST_ONLY)) {
+		return types.ErrInvalidOrderParam("DisplayQty", fmt.Sprintf("DisplayQty is only allowed for GTE and POST_ONLY limit orders:%d", msg.DisplayQty))
+	}
 	if !IsValidSide(msg.Side) {
 		return types.ErrInvalidOrderParam("Side", fmt.Sprintf("Invalid side:%d", msg.Side))
 	}
@@ -268,3 +421,121 @@ func (msg CancelOrderMsg) ValidateBasic() sdk.Error {
 	}
 	return nil
 }
+
+var _ sdk.Msg = AmendOrderMsg{}
+
+// AmendOrderMsg represents a message to change the price and/or quantity of an open order.
+// Quantity is the new total quantity of the order, including the part already filled.
+type AmendOrderMsg struct {
+	Sender   sdk.AccAddress `json:"sender"`
+	Symbol   string         `json:"symbol"`
+	RefId    string         `json:"refid"`
+	Price    int64          `json:"price"`
+	Quantity int64          `json:"quantity"`
+}
+
+// NewAmendOrderMsg constructs a new AmendOrderMsg
+func NewAmendOrderMsg(sender sdk.AccAddress, symbol, refId string, price, qty int64) AmendOrderMsg {
+	return AmendOrderMsg{
+		Sender:   sender,
+		Symbol:   symbol,
+		RefId:    refId,
+		Price:    price,
+		Quantity: qty,
+	}
+}
+
+// nolint
+func (msg AmendOrderMsg) Route() string                { return RouteAmendOrder }
+func (msg AmendOrderMsg) Type() string                 { return RouteAmendOrder }
+func (msg AmendOrderMsg) GetSigners() []sdk.AccAddress { return []sdk.AccAddress{msg.Sender} }
+func (msg AmendOrderMsg) String() string {
+	return fmt.Sprintf("AmendOrderMsg{Sender:%v, RefId: %s, Price: %d, Quantity: %d}", msg.Sender, msg.RefId, msg.Price, msg.Quantity)
+}
+
+// GetSignBytes - Get the bytes for the message signer to sign on
+func (msg AmendOrderMsg) GetSignBytes() []byte {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func (msg AmendOrderMsg) GetInvolvedAddresses() []sdk.AccAddress {
+	return msg.GetSigners()
+}
+
+// ValidateBasic is used to quickly disqualify obviously invalid messages quickly
+func (msg AmendOrderMsg) ValidateBasic() sdk.Error {
+	if len(msg.Sender) == 0 {
+		return sdk.ErrUnknownAddress(msg.Sender.String()).TraceSDK("")
+	}
+	if len(msg.RefId) == 0 || !strings.Contains(msg.RefId, "-") {
+		return types.ErrInvalidOrderParam("RefId", fmt.Sprintf("Invalid ref ID:%s", msg.RefId))
+	}
+	if msg.Price <= 0 {
+		return types.ErrInvalidOrderParam("Price", fmt.Sprintf("Zero/Negative Number:%d", msg.Price))
+	}
+	if msg.Quantity <= 0 {
+		return types.ErrInvalidOrderParam("Quantity", fmt.Sprintf("Zero/Negative Number:%d", msg.Quantity))
+	}
+	return nil
+}
+
+// IsSizeReduction returns true if the amendment only reduces the quantity of the order, which keeps
+// the time priority of the order in its price level
+func (msg AmendOrderMsg) IsSizeReduction(orig NewOrderMsg) bool {
+	return msg.Price == orig.Price && msg.Quantity < orig.Quantity
+}
+
+var _ sdk.Msg = CancelAllOrdersMsg{}
+
+// CancelAllOrdersMsg represents a message to cancel all the open orders of the sender,
+// optionally only the ones of a trading pair and/or a side
+type CancelAllOrdersMsg struct {
+	Sender sdk.AccAddress `json:"sender"`
+	Symbol string         `json:"symbol,omitempty"` // all trading pairs if empty
+	Side   int8           `json:"side,omitempty"`   // both sides if 0
+}
+
+// NewCancelAllOrdersMsg constructs a new CancelAllOrdersMsg
+func NewCancelAllOrdersMsg(sender sdk.AccAddress, symbol string, side int8) CancelAllOrdersMsg {
+	return CancelAllOrdersMsg{
+		Sender: sender,
+		Symbol: symbol,
+		Side:   side,
+	}
+}
+
+// nolint
+func (msg CancelAllOrdersMsg) Route() string                { return RouteCancelAllOrders }
+func (msg CancelAllOrdersMsg) Type() string                 { return RouteCancelAllOrders }
+func (msg CancelAllOrdersMsg) GetSigners() []sdk.AccAddress { return []sdk.AccAddress{msg.Sender} }
+func (msg CancelAllOrdersMsg) String() string {
+	return fmt.Sprintf("CancelAllOrdersMsg{Sender:%v, Symbol: %s, Side: %d}", msg.Sender, msg.Symbol, msg.Side)
+}
+
+// GetSignBytes - Get the bytes for the message signer to sign on
+func (msg CancelAllOrdersMsg) GetSignBytes() []byte {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func (msg CancelAllOrdersMsg) GetInvolvedAddresses() []sdk.AccAddress {
+	return msg.GetSigners()
+}
+
+// ValidateBasic is used to quickly disqualify obviously invalid messages quickly
+func (msg CancelAllOrdersMsg) ValidateBasic() sdk.Error {
+	if len(msg.Sender) == 0 {
+		return sdk.ErrUnknownAddress(msg.Sender.String()).TraceSDK("")
+	}
+	if msg.Side != 0 && !IsValidSide(msg.Side) {
+		return types.ErrInvalidOrderParam("Side", fmt.Sprintf("Invalid side:%d", msg.Side))
+	}
+	return nil
+}
@@ -17,6 +17,7 @@ import (
 
 	cmn "github.com/bnb-chain/node/common"
 	"github.com/bnb-chain/node/common/testutils"
+	"github.com/bnb-chain/node/common/upgrade"
 )
 
 func newCLIContext() context.CLIContext {
@@ -40,10 +41,11 @@ func TestIsValidSide(t *testing.T) {
 
 func TestIsValidOrderType(t *testing.T) {
 	assert := assert.New(t)
-	assert.False(IsValidOrderType(1))
+	assert.True(IsValidOrderType(1))
 	assert.True(IsValidOrderType(2))
 	assert.False(IsValidOrderType(0))
-	assert.False(IsValidOrderType(3))
+	assert.True(IsValidOrderType(3))
+	assert.False(IsValidOrderType(4))
 }
 
 func TestIsValidTimeInForce(t *testing.T) {
@@ -52,9 +54,13 @@ func TestIsValidTimeInForce(t *testing.T) {
 	assert.False(IsValidTimeInForce(2))
 	assert.False(IsValidTimeInForce(0))
 	assert.True(IsValidTimeInForce(3))
+	assert.True(IsValidTimeInForce(4))
+	assert.True(IsValidTimeInForce(5))
 }
 
 func TestNewOrderMsg_ValidateBasic(t *testing.T) {
+	upgrade.Mgr.AddUpgradeHeight(upgrade.DexEnhancements, -1)
+	defer resetChainVersion()
 	assert := assert.New(t)
 	_, acct := testutils.PrivAndAddr()
 	msg := NewNewOrderMsg(acct, "addr-1", 1, "BTC.B_BNB", 355, 100)
@@ -66,8 +72,94 @@ func TestNewOrderMsg_ValidateBasic(t *testing.T) {
 	msg = NewNewOrderMsg(acct, "addr-1", 2, "BTC.B_BNB", 355, 0)
 	assert.Regexp(regexp.MustCompile(".*Zero/Negative Number.*"), msg.ValidateBasic().Error())
 	msg = NewNewOrderMsg(acct, "addr-1", 2, "BTC.B_BNB", 355, 10)
-	msg.TimeInForce = 5
+	msg.TimeInForce = 9
 	assert.Regexp(regexp.MustCompile(".*Invalid TimeInForce.*"), msg.ValidateBasic().Error())
+	msg = NewStopLimitOrderMsg(acct, "addr-1", 1, "BTC.B_BNB", 400, 355, 100)
+	assert.Nil(msg.ValidateBasic())
+	msg = NewStopLimitOrderMsg(acct, "addr-1", 1, "BTC.B_BNB", 0, 355, 100)
+	assert.Regexp(regexp.MustCompile(".*StopPrice.*Zero/Negative Number.*"), msg.ValidateBasic().Error())
+	msg = NewNewOrderMsg(acct, "addr-1", 1, "BTC.B_BNB", 355, 100)
+	msg.StopPrice = 400
+	assert.Regexp(regexp.MustCompile(".*only allowed for stop-limit orders.*"), msg.ValidateBasic().Error())
+	msg = NewNewOrderMsg(acct, "addr-1", 1, "BTC.B_BNB", 355, 100)
+	msg.GoodTillHeight = -1
+	assert.Regexp(regexp.MustCompile(".*GoodTillHeight.*Negative Number.*"), msg.ValidateBasic().Error())
+	msg = NewNewOrderMsg(acct, "addr-1", 1, "BTC.B_BNB", 355, 100)
+	msg.GoodTillTime = -1
+	assert.Regexp(regexp.MustCompile(".*GoodTillTime.*Negative Number.*"), msg.ValidateBasic().Error())
+	msg = NewNewOrderMsg(acct, "addr-1", 1, "BTC.B_BNB", 355, 100)
+	msg.DisplayQty = 101
+	assert.Regexp(regexp.MustCompile(".*DisplayQty.*larger than Quantity.*"), msg.ValidateBasic().Error())
+	msg.DisplayQty = 10
+	assert.Nil(msg.ValidateBasic())
+	msg.TimeInForce = TimeInForce.IOC
+	assert.Regexp(regexp.MustCompile(".*DisplayQty is only allowed for GTE and POST_ONLY.*"), msg.ValidateBasic().Error())
+	msg = NewMarketOrderMsg(acct, "addr-1", 1, "BTC.B_BNB", 100)
+	assert.Nil(msg.ValidateBasic())
+	msg.Price = 355
+	assert.Regexp(regexp.MustCompile(".*Price is not allowed for market orders.*"), msg.ValidateBasic().Error())
+	msg = NewMarketOrderMsg(acct, "addr-1", 1, "BTC.B_BNB", 100)
+	msg.TimeInForce = TimeInForce.GTE
+	assert.Regexp(regexp.MustCompile(".*Market orders must be IOC.*"), msg.ValidateBasic().Error())
+}
+
+func TestNewOrderMsg_ValidateBasicBeforeUpgrade(t *testing.T) {
+	assert := assert.New(t)
+	_, acct := testutils.PrivAndAddr()
+	msg := NewNewOrderMsg(acct, "addr-1", 1, "BTC.B_BNB", 355, 100)
+	assert.Nil(msg.ValidateBasic())
+	msg = NewStopLimitOrderMsg(acct, "addr-1", 1, "BTC.B_BNB", 400, 355, 100)
+	assert.Regexp(regexp.MustCompile(".*Order type is not supported yet:3.*"), msg.ValidateBasic().Error())
+	msg = NewMarketOrderMsg(acct, "addr-1", 1, "BTC.B_BNB", 100)
+	assert.Regexp(regexp.MustCompile(".*Order type is not supported yet:1.*"), msg.ValidateBasic().Error())
+	msg = NewNewOrderMsg(acct, "addr-1", 1, "BTC.B_BNB", 355, 100)
+	msg.TimeInForce = TimeInForce.POST_ONLY
+	assert.Regexp(regexp.MustCompile(".*TimeInForce is not supported yet:5.*"), msg.ValidateBasic().Error())
+	msg.TimeInForce = TimeInForce.FOK
+	assert.Regexp(regexp.MustCompile(".*TimeInForce is not supported yet:4.*"), msg.ValidateBasic().Error())
+	msg = NewNewOrderMsg(acct, "addr-1", 1, "BTC.B_BNB", 355, 100)
+	msg.GoodTillTime = 1
+	assert.Regexp(regexp.MustCompile(".*GoodTillHeight and GoodTillTime are not supported yet.*"), msg.ValidateBasic().Error())
+	msg = NewNewOrderMsg(acct, "addr-1", 1, "BTC.B_BNB", 355, 100)
+	msg.DisplayQty = 10
+	assert.Regexp(regexp.MustCompile(".*DisplayQty is not supported yet:10.*"), msg.ValidateBasic().Error())
+}
+
+func TestNewOrderMsg_IsGoodTillExpired(t *testing.T) {
+	assert := assert.New(t)
+	_, acct := testutils.PrivAndAddr()
+	msg := NewNewOrderMsg(acct, "addr-1", 1, "BTC.B_BNB", 355, 100)
+	assert.False(msg.IsGoodTillExpired(100, 1e9))
+	msg.GoodTillHeight = 100
+	assert.False(msg.IsGoodTillExpired(99, 1e9))
+	assert.True(msg.IsGoodTillExpired(100, 1e9))
+	msg.GoodTillHeight = 0
+	msg.GoodTillTime = 1e9
+	assert.False(msg.IsGoodTillExpired(100, 1e9-1))
+	assert.True(msg.IsGoodTillExpired(100, 1e9))
+}
+
+func TestNewOrderMsg_VisibleQty(t *testing.T) {
+	assert := assert.New(t)
+	_, acct := testutils.PrivAndAddr()
+	msg := NewNewOrderMsg(acct, "addr-1", 1, "BTC.B_BNB", 355, 100)
+	assert.Equal(int64(70), msg.VisibleQty(30))
+	msg.DisplayQty = 40
+	assert.Equal(int64(40), msg.VisibleQty(0))
+	assert.Equal(int64(10), msg.VisibleQty(30))
+	assert.Equal(int64(40), msg.VisibleQty(40))
+	assert.Equal(int64(20), msg.VisibleQty(80))
+}
+
+func TestIsStopTriggered(t *testing.T) {
+	assert := assert.New(t)
+	assert.True(IsStopTriggered(Side.BUY, 100, 100))
+	assert.True(IsStopTriggered(Side.BUY, 100, 101))
+	assert.False(IsStopTriggered(Side.BUY, 100, 99))
+	assert.True(IsStopTriggered(Side.SELL, 100, 100))
+	assert.True(IsStopTriggered(Side.SELL, 100, 99))
+	assert.False(IsStopTriggered(Side.SELL, 100, 101))
+	assert.False(IsStopTriggered(Side.SELL, 100, 0))
 }
 
 func TestCancelOrderMsg_ValidateBasic(t *testing.T) {
@@ -76,6 +168,33 @@ func TestCancelOrderMsg_ValidateBasic(t *testing.T) {
 	assert.NotNil(msg.ValidateBasic())
 }
 
+func TestAmendOrderMsg_ValidateBasic(t *testing.T) {
+	assert := assert.New(t)
+	addr := sdk.AccAddress("addr")
+	assert.NotNil(NewAmendOrderMsg(sdk.AccAddress{}, "XYZ_BNB", "order-1", 1e8, 1e8).ValidateBasic())
+	assert.NotNil(NewAmendOrderMsg(addr, "XYZ_BNB", "order1", 1e8, 1e8).ValidateBasic())
+	assert.NotNil(NewAmendOrderMsg(addr, "XYZ_BNB", "order-1", 0, 1e8).ValidateBasic())
+	assert.NotNil(NewAmendOrderMsg(addr, "XYZ_BNB", "order-1", 1e8, 0).ValidateBasic())
+	assert.Nil(NewAmendOrderMsg(addr, "XYZ_BNB", "order-1", 1e8, 1e8).ValidateBasic())
+}
+
+func TestAmendOrderMsg_IsSizeReduction(t *testing.T) {
+	assert := assert.New(t)
+	orig := NewNewOrderMsg(sdk.AccAddress("addr"), "order-1", Side.BUY, "XYZ_BNB", 1e8, 2e8)
+	assert.True(NewAmendOrderMsg(orig.Sender, orig.Symbol, orig.Id, 1e8, 1e8).IsSizeReduction(orig))
+	assert.False(NewAmendOrderMsg(orig.Sender, orig.Symbol, orig.Id, 1e8, 3e8).IsSizeReduction(orig))
+	assert.False(NewAmendOrderMsg(orig.Sender, orig.Symbol, orig.Id, 0.9e8, 1e8).IsSizeReduction(orig))
+}
+
+func TestCancelAllOrdersMsg_ValidateBasic(t *testing.T) {
+	assert := assert.New(t)
+	addr := sdk.AccAddress("addr")
+	assert.NotNil(NewCancelAllOrdersMsg(sdk.AccAddress{}, "", 0).ValidateBasic())
+	assert.NotNil(NewCancelAllOrdersMsg(addr, "XYZ_BNB", 3).ValidateBasic())
+	assert.Nil(NewCancelAllOrdersMsg(addr, "", 0).ValidateBasic())
+	assert.Nil(NewCancelAllOrdersMsg(addr, "XYZ_BNB", Side.SELL).ValidateBasic())
+}
+
 func TestGenerateOrderId(t *testing.T) {
 	viper.SetDefault(client.FlagSequence, "5")
 	viper.SetDefault(client.FlagChainID, "mychaindid")
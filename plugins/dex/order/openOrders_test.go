@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
 
 	"github.com/bnb-chain/node/plugins/dex/types"
 )
@@ -44,3 +45,41 @@ func TestOpenOrders_NoAddr(t *testing.T) {
 		t.Log("Get expected empty result for a non-existing addr")
 	}
 }
+
+func TestOpenOrders_AllPairs(t *testing.T) {
+	assert := assert.New(t)
+	keeper := initKeeper()
+	keeper.AddEngine(types.NewTradingPair("NNB", "BNB", 1e8))
+	keeper.AddEngine(types.NewTradingPair("XYZ-000", "BNB", 1e8))
+	assert.Empty(keeper.GetAllOpenOrders(zz))
+
+	addOrder := func(sender sdk.AccAddress, id, pair string, side int8) {
+		msg := NewNewOrderMsg(sender, id, side, pair, 1e8, 1e8)
+		assert.NoError(keeper.AddOrder(OrderInfo{msg, 42, 0, 42, 0, 0, "", 0}, false))
+	}
+	addOrder(zz, ZzAddr+"-1", "XYZ-000_BNB", Side.SELL)
+	addOrder(zz, ZzAddr+"-2", "NNB_BNB", Side.SELL)
+	addOrder(zz, ZzAddr+"-3", "NNB_BNB", Side.BUY)
+	addOrder(zc, ZcAddr+"-1", "XYZ-000_BNB", Side.BUY)
+	orders := keeper.GetAllOpenOrders(zz)
+	assert.Len(orders, 3)
+	assert.Equal("NNB_BNB", orders[0].Symbol)
+	assert.Equal(ZzAddr+"-2", orders[0].Id)
+	assert.Equal(ZzAddr+"-3", orders[1].Id)
+	assert.Equal("XYZ-000_BNB", orders[2].Symbol)
+
+	// the canceled and filled orders are not shown
+	assert.NoError(keeper.RemoveOrder(ZzAddr+"-3", "NNB_BNB", nil))
+	keeper.MatchSymbols(42, 42e9, false)
+	orders = keeper.GetAllOpenOrders(zz)
+	assert.Len(orders, 1)
+	assert.Equal(ZzAddr+"-2", orders[0].Id)
+	assert.Empty(keeper.GetAllOpenOrders(zc))
+
+	// the closed orders are pruned from the index of senders right away
+	assert.Len(keeper.OrderKeepers[0].(*BEP2OrderKeeper).senderOrders, 1)
+	assert.Len(keeper.GetOpenOrderInfos(zz, "", 0), 1)
+	assert.Len(keeper.GetOpenOrderInfos(zz, "NNB_BNB", Side.SELL), 1)
+	assert.Empty(keeper.GetOpenOrderInfos(zz, "NNB_BNB", Side.BUY))
+	assert.Empty(keeper.GetOpenOrderInfos(zz, "XYZ-000_BNB", 0))
+}
@@ -0,0 +1,174 @@
+package order
+
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	dbm "github.com/tendermint/tendermint/libs/db"
+
+	"github.com/bnb-chain/node/common/utils"
+	"github.com/bnb-chain/node/plugins/dex/store"
+	"github.com/bnb-chain/node/wire"
+)
+
+// OrderHistoryStore keeps the closed orders of every address in a database out of the consensus state,
+// so the app hash is not affected by them.
+type OrderHistoryStore struct {
+	db  dbm.DB
+	cdc *wire.Codec
+}
+
+func NewOrderHistoryStore(db dbm.DB, cdc *wire.Codec) *OrderHistoryStore {
+	return &OrderHistoryStore{
+		db:  db,
+		cdc: cdc,
+	}
+}
+
+func closedOrderPrefix(owner string) []byte {
+	return []byte(fmt.Sprintf("closed_%s_", owner))
+}
+
+func closedOrderHeightKey(owner string, height int64) []byte {
+	return append(closedOrderPrefix(owner), []byte(fmt.Sprintf("%020d", height))...)
+}
+
+func closedOrderDBKey(owner string, height int64, id string) []byte {
+	return append(closedOrderHeightKey(owner, height), []byte("_"+id)...)
+}
+
+func (hs *OrderHistoryStore) save(orders []store.ClosedOrder) {
+	if len(orders) == 0 {
+		return
+	}
+	batch := hs.db.NewBatch()
+	defer batch.Close()
+	for _, o := range orders {
+		batch.Set(closedOrderDBKey(o.Owner, o.ClosedHeight, o.Id), hs.cdc.MustMarshalBinaryBare(o))
+	}
+	batch.Write()
+}
+
+// query returns the page of the closed orders matching the params, the latest closed first
+func (hs *OrderHistoryStore) query(params store.ClosedOrdersQueryParams) store.ClosedOrders {
+	prefix := closedOrderPrefix(params.Address)
+	start, end := prefix, sdk.PrefixEndBytes(prefix)
+	if params.FromHeight > 0 {
+		start = closedOrderHeightKey(params.Address, params.FromHeight)
+	}
+	if params.ToHeight > 0 {
+		end = closedOrderHeightKey(params.Address, params.ToHeight+1)
+	}
+	res := store.ClosedOrders{Orders: make([]store.ClosedOrder, 0, params.Limit)}
+	iter := hs.db.ReverseIterator(start, end)
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		var o store.ClosedOrder
+		hs.cdc.MustUnmarshalBinaryBare(iter.Value(), &o)
+		if (params.Symbol != "" && o.Symbol != params.Symbol) || (params.Status != "" && o.Status != params.Status) {
+			continue
+		}
+		if res.Total >= params.Offset && len(res.Orders) < params.Limit {
+			res.Orders = append(res.Orders, o)
+		}
+		res.Total++
+	}
+	return res
+}
+
+func toClosedOrder(info *OrderInfo, status ChangeType, height, timestamp int64) store.ClosedOrder {
+	return store.ClosedOrder{
+		Id:               info.Id,
+		Symbol:           info.Symbol,
+		Owner:            info.Sender.String(),
+		Side:             info.Side,
+		OrderType:        info.OrderType,
+		TimeInForce:      info.TimeInForce,
+		Price:            utils.Fixed8(info.Price),
+		Quantity:         utils.Fixed8(info.Quantity),
+		CumQty:           utils.Fixed8(info.CumQty),
+		Status:           status.String(),
+		CreatedHeight:    info.CreatedHeight,
+		CreatedTimestamp: info.CreatedTimestamp,
+		ClosedHeight:     height,
+		ClosedTimestamp:  timestamp,
+		TxHash:           info.TxHash,
+	}
+}
+
+func isClosedStatus(status string) bool {
+	for tpe := Ack; tpe <= SelfTradePrevented; tpe++ {
+		if tpe.String() == status {
+			return !tpe.IsOpen()
+		}
+	}
+	return false
+}
+
+// SetOrderHistoryStore enables the order history, which is saved in the db. The order history is resolved from
+// the order changes and infos collected for publication, so it's only recorded along with the publication.
+func (kp *DexKeeper) SetOrderHistoryStore(db dbm.DB) {
+	kp.orderHistory = NewOrderHistoryStore(db, kp.cdc)
+}
+
+func (kp *DexKeeper) OrderHistoryEnabled() bool {
+	return kp.orderHistory != nil
+}
+
+// RecordClosedOrders saves the orders closed in the block at the height into the order history. The orders are
+// resolved in the same way as the publisher does: the canceled, expired and rejected ones from the order changes,
+// and the fully filled ones from the trades. The closed orders are returned.
+func (kp *DexKeeper) RecordClosedOrders(height, timestamp int64) []store.ClosedOrder {
+	if kp.orderHistory == nil {
+		return nil
+	}
+	orderInfos := kp.GetAllOrderInfosForPub()
+	closed := make([]store.ClosedOrder, 0)
+	recorded := make(map[string]bool)
+	for _, change := range kp.GetAllOrderChanges() {
+		if change.Tpe.IsOpen() || recorded[change.Id] {
+			continue
+		}
+		info, ok := orderInfos[change.Id]
+		if !ok {
+			kp.logger.Error("Failed to locate closed order in order infos", "orderChange", change.String())
+			continue
+		}
+		recorded[change.Id] = true
+		closed = append(closed, toClosedOrder(info, change.Tpe, height, timestamp))
+	}
+	for symbol := range kp.engines {
+		trades, _ := kp.GetLastTrades(height, symbol)
+		for _, t := range trades {
+			for _, id := range []string{t.Bid, t.Sid} {
+				info, ok := orderInfos[id]
+				if !ok || recorded[id] || info.CumQty < info.Quantity {
+					continue
+				}
+				recorded[id] = true
+				closed = append(closed, toClosedOrder(info, FullyFill, height, timestamp))
+			}
+		}
+	}
+	kp.orderHistory.save(closed)
+	return closed
+}
+
+// GetClosedOrders returns the closed orders of the address matching the filters of the params, the latest closed first
+func (kp *DexKeeper) GetClosedOrders(params store.ClosedOrdersQueryParams) (store.ClosedOrders, error) {
+	if kp.orderHistory == nil {
+		return store.ClosedOrders{}, fmt.Errorf("order history is not maintained by this node")
+	}
+	if _, err := sdk.AccAddressFromBech32(params.Address); err != nil {
+		return store.ClosedOrders{}, fmt.Errorf("address is not valid")
+	}
+	if params.Status != "" && !isClosedStatus(params.Status) {
+		return store.ClosedOrders{}, fmt.Errorf("invalid order status %s", params.Status)
+	}
+	if params.FromHeight < 0 || params.ToHeight < 0 || (params.ToHeight > 0 && params.FromHeight > params.ToHeight) {
+		return store.ClosedOrders{}, fmt.Errorf("invalid height range [%d, %d]", params.FromHeight, params.ToHeight)
+	}
+	params.Symbol = strings.ToUpper(params.Symbol)
+	return kp.orderHistory.query(params), nil
+}
@@ -1,6 +1,7 @@
 package order
 
 import (
+	"sort"
 	"sync"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -22,18 +23,28 @@ const (
 type DexOrderKeeper interface {
 	initOrders(symbol string)
 	addOrder(symbol string, info OrderInfo, isRecovery bool)
+	addStopOrder(symbol string, info OrderInfo, isRecovery bool)
+	triggerStopOrders(symbol string, lastTradePrice int64) []*OrderInfo
 	reloadOrder(symbol string, orderInfo *OrderInfo, height int64)
 	removeOrder(dexKeeper *DexKeeper, id string, symbol string) (ord me.OrderPart, err error)
+	amendOrder(dexKeeper *DexKeeper, symbol string, msg AmendOrderMsg, height, timestamp int64) (OrderInfo, error)
 	orderExists(symbol, id string) (OrderInfo, bool)
 	getOpenOrders(pair string, addr sdk.AccAddress) []store.OpenOrder
+	getOpenOrdersOfSender(addr sdk.AccAddress) []store.OpenOrder
+	countOpenOrdersOfSender(addr sdk.AccAddress) map[string]int64
+	getOrdersOfSender(pair string, addr sdk.AccAddress) []OrderInfo
 	getAllOrders() map[string]map[string]*OrderInfo
+	getAllStopOrders() map[string]map[string]*OrderInfo
 	deleteOrdersForPair(pair string)
+	removeSenderOrder(sender sdk.AccAddress, id string)
 
 	iterateRoundSelectedPairs(func(string))
 	iterateAllOrders(func(symbol string, id string))
 
 	getRoundOrdersNum() int
 	getAllOrdersForPair(pair string) map[string]*OrderInfo
+	getStopOrdersForPair(pair string) map[string]*OrderInfo
+	getGoodTillOrdersForPair(pair string) map[string]struct{}
 	getRoundOrdersForPair(pair string) []string
 	getRoundIOCOrdersForPair(pair string) []string
 	clearAfterMatch()
@@ -55,9 +66,14 @@ type DexOrderKeeper interface {
 // in the future, this may be distributed via Sharding
 type BaseOrderKeeper struct {
 	allOrders      map[string]map[string]*OrderInfo // symbol -> order ID -> order
+	stopOrders     map[string]map[string]*OrderInfo // symbol -> order ID -> untriggered stop order, not in order book
+	goodTillOrders map[string]map[string]struct{}   // symbol -> IDs of orders with good-till height or time, may be stale
+	senderOrders   map[string]map[string]string     // sender address bytes -> order ID -> symbol
 	roundOrders    map[string][]string              // limit to the total tx number in a block
 	roundIOCOrders map[string][]string
 
+	senderOrdersMtx *sync.Mutex // guard senderOrders, as the orders of different pairs are closed concurrently in the match
+
 	collectOrderInfoForPublish bool
 	orderChangesMtx            *sync.Mutex         // guard orderChanges and orderInfosForPub during PreDevlierTx (which is async)
 	orderChanges               OrderChanges        // order changed in this block, will be cleaned before matching for new block
@@ -71,9 +87,14 @@ func NewBaseOrderKeeper(moduleName string) BaseOrderKeeper {
 	return BaseOrderKeeper{
 		// need to init the nested map when a new symbol added.
 		allOrders:      make(map[string]map[string]*OrderInfo, 256),
+		stopOrders:     make(map[string]map[string]*OrderInfo, 256),
+		goodTillOrders: make(map[string]map[string]struct{}, 256),
+		senderOrders:   make(map[string]map[string]string, 256),
 		roundOrders:    make(map[string][]string, 256),
 		roundIOCOrders: make(map[string][]string, 256),
 
+		senderOrdersMtx: &sync.Mutex{},
+
 		collectOrderInfoForPublish: false, // default to false, need a explicit set if needed
 		orderChangesMtx:            &sync.Mutex{},
 		orderChanges:               make(OrderChanges, 0),
@@ -95,6 +116,92 @@ func (kp *BaseOrderKeeper) addOrder(symbol string, info OrderInfo, isRecovery bo
 
 	kp.allOrders[symbol][info.Id] = &info
 	kp.addRoundOrders(symbol, info)
+	kp.addGoodTillOrder(symbol, &info)
+	kp.addSenderOrder(symbol, &info)
+}
+
+// addStopOrder parks a stop order outside the order book until it is triggered
+func (kp *BaseOrderKeeper) addStopOrder(symbol string, info OrderInfo, isRecovery bool) {
+	if kp.collectOrderInfoForPublish {
+		change := OrderChange{info.Id, StopPlaced, "", nil}
+		if !isRecovery {
+			kp.orderChanges = append(kp.orderChanges, change)
+		}
+		kp.logger.Debug("add stop order to order changes map", "orderId", info.Id, "isRecovery", isRecovery)
+		kp.orderInfosForPub[info.Id] = &info
+	}
+
+	if _, ok := kp.stopOrders[symbol]; !ok {
+		kp.stopOrders[symbol] = map[string]*OrderInfo{}
+	}
+	kp.stopOrders[symbol][info.Id] = &info
+	kp.addGoodTillOrder(symbol, &info)
+	kp.addSenderOrder(symbol, &info)
+}
+
+// addGoodTillOrder indexes the order if it has a good-till height or time, so that it can be
+// expired at every block without going through all the orders
+func (kp *BaseOrderKeeper) addGoodTillOrder(symbol string, info *OrderInfo) {
+	if info.GoodTillHeight == 0 && info.GoodTillTime == 0 {
+		return
+	}
+	if _, ok := kp.goodTillOrders[symbol]; !ok {
+		kp.goodTillOrders[symbol] = map[string]struct{}{}
+	}
+	kp.goodTillOrders[symbol][info.Id] = struct{}{}
+}
+
+// addSenderOrder indexes the order by its sender, so that the open orders of an address can be found
+// without going through all the orders. The order is dropped from the index by removeSenderOrder once it is closed.
+func (kp *BaseOrderKeeper) addSenderOrder(symbol string, info *OrderInfo) {
+	kp.senderOrdersMtx.Lock()
+	defer kp.senderOrdersMtx.Unlock()
+	sender := string(info.Sender.Bytes())
+	if _, ok := kp.senderOrders[sender]; !ok {
+		kp.senderOrders[sender] = map[string]string{}
+	}
+	kp.senderOrders[sender][info.Id] = symbol
+}
+
+// removeSenderOrder drops the order closed by a cancel, fill, expiry or delisting from the index of senders
+func (kp *BaseOrderKeeper) removeSenderOrder(sender sdk.AccAddress, id string) {
+	kp.senderOrdersMtx.Lock()
+	defer kp.senderOrdersMtx.Unlock()
+	if ids, ok := kp.senderOrders[string(sender.Bytes())]; ok {
+		delete(ids, id)
+		if len(ids) == 0 {
+			delete(kp.senderOrders, string(sender.Bytes()))
+		}
+	}
+}
+
+// triggerStopOrders moves the stop orders of the symbol triggered by lastTradePrice to the open orders
+// of this round as limit orders, and returns them sorted by created height and id, so that
+// they are injected into the order book in a deterministic order.
+func (kp *BaseOrderKeeper) triggerStopOrders(symbol string, lastTradePrice int64) []*OrderInfo {
+	triggered := make([]*OrderInfo, 0)
+	for id, info := range kp.stopOrders[symbol] {
+		if IsStopTriggered(info.Side, info.StopPrice, lastTradePrice) {
+			triggered = append(triggered, info)
+			delete(kp.stopOrders[symbol], id)
+		}
+	}
+	sort.Slice(triggered, func(i, j int) bool {
+		if triggered[i].CreatedHeight != triggered[j].CreatedHeight {
+			return triggered[i].CreatedHeight < triggered[j].CreatedHeight
+		}
+		return triggered[i].Id < triggered[j].Id
+	})
+	for _, info := range triggered {
+		info.OrderType = OrderType.LIMIT
+		kp.allOrders[symbol][info.Id] = info
+		kp.addRoundOrders(symbol, *info)
+		if kp.collectOrderInfoForPublish {
+			kp.orderChanges = append(kp.orderChanges, OrderChange{info.Id, StopTriggered, "", nil})
+			kp.orderInfosForPub[info.Id] = info
+		}
+	}
+	return triggered
 }
 
 func (kp *BaseOrderKeeper) addRoundOrders(symbol string, info OrderInfo) {
@@ -104,7 +211,8 @@ func (kp *BaseOrderKeeper) addRoundOrders(symbol string, info OrderInfo) {
 		newIds := make([]string, 0, 16)
 		kp.roundOrders[symbol] = append(newIds, info.Id)
 	}
-	if info.TimeInForce == TimeInForce.IOC {
+	// FOK orders left in the book after the match are removed in the same way as IOC orders
+	if info.TimeInForce == TimeInForce.IOC || info.TimeInForce == TimeInForce.FOK {
 		kp.roundIOCOrders[symbol] = append(kp.roundIOCOrders[symbol], info.Id)
 	}
 }
@@ -115,10 +223,20 @@ func (kp *BaseOrderKeeper) orderExists(symbol, id string) (OrderInfo, bool) {
 			return *msg, ok
 		}
 	}
+	if orders, ok := kp.stopOrders[symbol]; ok {
+		if msg, ok := orders[id]; ok {
+			return *msg, ok
+		}
+	}
 	return OrderInfo{}, false
 }
 
 func (kp *BaseOrderKeeper) removeOrder(dexKeeper *DexKeeper, id string, symbol string) (ord me.OrderPart, err error) {
+	if stopOrd, ok := kp.stopOrders[symbol][id]; ok {
+		delete(kp.stopOrders[symbol], id)
+		kp.removeSenderOrder(stopOrd.Sender, id)
+		return stopOrderPart(stopOrd), nil
+	}
 	ordMsg, ok := kp.orderExists(symbol, id)
 	if !ok {
 		return me.OrderPart{}, orderNotFound(symbol, id)
@@ -128,41 +246,135 @@ func (kp *BaseOrderKeeper) removeOrder(dexKeeper *DexKeeper, id string, symbol s
 		return me.OrderPart{}, orderNotFound(symbol, id)
 	}
 	delete(kp.allOrders[symbol], id)
+	kp.removeSenderOrder(ordMsg.Sender, id)
 	return eng.Book.RemoveOrder(id, ordMsg.Side, ordMsg.Price)
 }
 
+// amendOrder changes the price and quantity of an open order and returns the order before the amendment.
+// An order losing its time priority takes part in the coming match as an order of this round.
+func (kp *BaseOrderKeeper) amendOrder(dexKeeper *DexKeeper, symbol string, msg AmendOrderMsg,
+	height, timestamp int64) (OrderInfo, error) {
+	info, ok := kp.allOrders[symbol][msg.RefId]
+	if !ok {
+		return OrderInfo{}, orderNotFound(symbol, msg.RefId)
+	}
+	eng, ok := dexKeeper.engines[symbol]
+	if !ok {
+		return OrderInfo{}, orderNotFound(symbol, msg.RefId)
+	}
+	if err := eng.AmendOrder(msg.RefId, info.Side, info.Price, msg.Price, msg.Quantity, height); err != nil {
+		return OrderInfo{}, err
+	}
+	origOrd := *info
+	updateAmendedOrder(info, msg, height, timestamp)
+	if !msg.IsSizeReduction(origOrd.NewOrderMsg) && !kp.isRoundOrder(symbol, msg.RefId) {
+		kp.addRoundOrders(symbol, *info)
+	}
+	return origOrd, nil
+}
+
+func updateAmendedOrder(info *OrderInfo, msg AmendOrderMsg, height, timestamp int64) {
+	info.Price = msg.Price
+	info.Quantity = msg.Quantity
+	info.LastUpdatedHeight = height
+	info.LastUpdatedTimestamp = timestamp
+}
+
+func (kp *BaseOrderKeeper) isRoundOrder(symbol, id string) bool {
+	for _, roundId := range kp.roundOrders[symbol] {
+		if roundId == id {
+			return true
+		}
+	}
+	return false
+}
+
 func (kp *BaseOrderKeeper) deleteOrdersForPair(pair string) {
+	for _, m := range []map[string]*OrderInfo{kp.allOrders[pair], kp.stopOrders[pair]} {
+		for id, order := range m {
+			kp.removeSenderOrder(order.Sender, id)
+		}
+	}
 	delete(kp.allOrders, pair)
+	delete(kp.stopOrders, pair)
+	delete(kp.goodTillOrders, pair)
 }
 
 func (kp *BaseOrderKeeper) getOpenOrders(pair string, addr sdk.AccAddress) []store.OpenOrder {
 	openOrders := make([]store.OpenOrder, 0)
 
-	for _, order := range kp.allOrders[pair] {
-		if string(order.Sender.Bytes()) == string(addr.Bytes()) {
-			openOrders = append(
-				openOrders,
-				store.OpenOrder{
-					Id:                   order.Id,
-					Symbol:               pair,
-					Price:                utils.Fixed8(order.Price),
-					Quantity:             utils.Fixed8(order.Quantity),
-					CumQty:               utils.Fixed8(order.CumQty),
-					CreatedHeight:        order.CreatedHeight,
-					CreatedTimestamp:     order.CreatedTimestamp,
-					LastUpdatedHeight:    order.LastUpdatedHeight,
-					LastUpdatedTimestamp: order.LastUpdatedTimestamp,
-				})
-		}
+	for _, order := range kp.getOrdersOfSender(pair, addr) {
+		openOrders = append(openOrders, toOpenOrder(pair, order))
 	}
 
 	return openOrders
 }
 
+// getOpenOrdersOfSender returns the open orders of addr in all the pairs from the index of senders
+func (kp *BaseOrderKeeper) getOpenOrdersOfSender(addr sdk.AccAddress) []store.OpenOrder {
+	kp.senderOrdersMtx.Lock()
+	defer kp.senderOrdersMtx.Unlock()
+	openOrders := make([]store.OpenOrder, 0)
+	for id, symbol := range kp.senderOrders[string(addr.Bytes())] {
+		if order, ok := kp.orderExists(symbol, id); ok {
+			openOrders = append(openOrders, toOpenOrder(symbol, order))
+		}
+	}
+	return openOrders
+}
+
+// countOpenOrdersOfSender returns the number of the open orders of addr in each pair from the index of senders
+func (kp *BaseOrderKeeper) countOpenOrdersOfSender(addr sdk.AccAddress) map[string]int64 {
+	kp.senderOrdersMtx.Lock()
+	defer kp.senderOrdersMtx.Unlock()
+	counts := make(map[string]int64)
+	for id, symbol := range kp.senderOrders[string(addr.Bytes())] {
+		if _, ok := kp.orderExists(symbol, id); ok {
+			counts[symbol]++
+		}
+	}
+	return counts
+}
+
+func toOpenOrder(pair string, order OrderInfo) store.OpenOrder {
+	return store.OpenOrder{
+		Id:                   order.Id,
+		Symbol:               pair,
+		Price:                utils.Fixed8(order.Price),
+		Quantity:             utils.Fixed8(order.Quantity),
+		CumQty:               utils.Fixed8(order.CumQty),
+		CreatedHeight:        order.CreatedHeight,
+		CreatedTimestamp:     order.CreatedTimestamp,
+		LastUpdatedHeight:    order.LastUpdatedHeight,
+		LastUpdatedTimestamp: order.LastUpdatedTimestamp,
+	}
+}
+
+// getOrdersOfSender returns the open orders of addr in the pair, or in all the pairs if pair is empty,
+// including the untriggered stop orders, from the index of senders
+func (kp *BaseOrderKeeper) getOrdersOfSender(pair string, addr sdk.AccAddress) []OrderInfo {
+	kp.senderOrdersMtx.Lock()
+	defer kp.senderOrdersMtx.Unlock()
+	orders := make([]OrderInfo, 0)
+	for id, symbol := range kp.senderOrders[string(addr.Bytes())] {
+		if pair != "" && symbol != pair {
+			continue
+		}
+		if order, ok := kp.orderExists(symbol, id); ok {
+			orders = append(orders, order)
+		}
+	}
+	return orders
+}
+
 func (kp *BaseOrderKeeper) getAllOrders() map[string]map[string]*OrderInfo {
 	return kp.allOrders
 }
 
+func (kp *BaseOrderKeeper) getAllStopOrders() map[string]map[string]*OrderInfo {
+	return kp.stopOrders
+}
+
 func (kp *BaseOrderKeeper) clearOrderChanges() {
 	kp.orderChanges = kp.orderChanges[:0]
 }
@@ -201,6 +413,33 @@ func (kp *BaseOrderKeeper) getAllOrdersForPair(pair string) map[string]*OrderInf
 	return kp.allOrders[pair]
 }
 
+func (kp *BaseOrderKeeper) getStopOrdersForPair(pair string) map[string]*OrderInfo {
+	return kp.stopOrders[pair]
+}
+
+func (kp *BaseOrderKeeper) getGoodTillOrdersForPair(pair string) map[string]struct{} {
+	return kp.goodTillOrders[pair]
+}
+
+// stopOrderPart builds the order book view of an untriggered stop order, which has never been filled
+func stopOrderPart(info *OrderInfo) me.OrderPart {
+	return me.OrderPart{Id: info.Id, Time: info.CreatedHeight, Qty: info.Quantity}
+}
+
+func (kp *BaseOrderKeeper) reloadStopOrder(symbol string, orderInfo *OrderInfo) {
+	if _, ok := kp.stopOrders[symbol]; !ok {
+		kp.stopOrders[symbol] = map[string]*OrderInfo{}
+	}
+	kp.stopOrders[symbol][orderInfo.Id] = orderInfo
+	kp.addGoodTillOrder(symbol, orderInfo)
+	kp.addSenderOrder(symbol, orderInfo)
+	if kp.collectOrderInfoForPublish {
+		if _, exists := kp.orderInfosForPub[orderInfo.Id]; !exists {
+			kp.orderInfosForPub[orderInfo.Id] = orderInfo
+		}
+	}
+}
+
 func (kp *BaseOrderKeeper) iterateAllOrders(iter func(string, string)) {
 	for symbol, orders := range kp.allOrders {
 		for orderId := range orders {
@@ -242,6 +481,7 @@ func (kp *BEP2OrderKeeper) supportPairType(pairType SymbolPairType) bool {
 
 func (kp *BEP2OrderKeeper) initOrders(symbol string) {
 	kp.allOrders[symbol] = map[string]*OrderInfo{}
+	kp.stopOrders[symbol] = map[string]*OrderInfo{}
 }
 
 func (kp *BEP2OrderKeeper) clearAfterMatch() {
@@ -257,10 +497,16 @@ func (kp *BEP2OrderKeeper) iterateRoundSelectedPairs(iter func(string)) {
 }
 
 func (kp *BEP2OrderKeeper) reloadOrder(symbol string, orderInfo *OrderInfo, height int64) {
+	if orderInfo.OrderType == OrderType.STOP_LIMIT {
+		kp.reloadStopOrder(symbol, orderInfo)
+		return
+	}
 	kp.allOrders[symbol][orderInfo.Id] = orderInfo
+	kp.addGoodTillOrder(symbol, orderInfo)
+	kp.addSenderOrder(symbol, orderInfo)
 	if orderInfo.CreatedHeight == height {
 		kp.roundOrders[symbol] = append(kp.roundOrders[symbol], orderInfo.Id)
-		if orderInfo.TimeInForce == TimeInForce.IOC {
+		if orderInfo.TimeInForce == TimeInForce.IOC || orderInfo.TimeInForce == TimeInForce.FOK {
 			kp.roundIOCOrders[symbol] = append(kp.roundIOCOrders[symbol], orderInfo.Id)
 		}
 	}
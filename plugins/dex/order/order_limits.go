@@ -0,0 +1,131 @@
+package order
+
+import (
+	"fmt"
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/node/common/upgrade"
+	"github.com/bnb-chain/node/plugins/dex/store"
+)
+
+// OrderLimitsConfig is the limits of the orders an account can place, which are governed by OrderLimitsParam
+// after the DexEnhancements upgrade. Each limit is disabled if it is not set.
+type OrderLimitsConfig struct {
+	MaxOpenOrdersPerPair int64 // the max number of the open orders of an account in a pair
+	MaxOpenOrders        int64 // the max number of the open orders of an account in all the pairs
+	MaxNewOrdersPerBlock int64 // the max number of the new orders of an account in a block
+}
+
+func (kp *DexKeeper) SetOrderLimitsConfig(config OrderLimitsConfig) {
+	kp.orderLimits = config
+}
+
+func (kp *DexKeeper) GetOrderLimitsConfig() OrderLimitsConfig {
+	return kp.orderLimits
+}
+
+// newOrderCounter counts the new orders of the accounts in the block of the height
+type newOrderCounter struct {
+	height int64
+	counts map[string]int64 // sender address bytes -> number of new orders
+}
+
+func (c *newOrderCounter) count(height int64, sender sdk.AccAddress) int64 {
+	if c.height != height {
+		return 0
+	}
+	return c.counts[string(sender.Bytes())]
+}
+
+func (c *newOrderCounter) add(height int64, sender sdk.AccAddress) {
+	if c.height != height || c.counts == nil {
+		c.height = height
+		c.counts = make(map[string]int64)
+	}
+	c.counts[string(sender.Bytes())]++
+}
+
+// newOrderCounter returns the counter of the mode of ctx. The orders checked into the mempool are counted
+// apart from the delivered ones, and the check state is at the last committed height, so the count of
+// CheckTx is reset when a block is committed. Nil is returned for the other modes, which are not counted.
+func (kp *DexKeeper) newOrderCounter(ctx sdk.Context) *newOrderCounter {
+	if ctx.IsDeliverTx() {
+		return &kp.deliverNewOrders
+	} else if ctx.IsCheckTx() {
+		return &kp.checkNewOrders
+	}
+	return nil
+}
+
+func (kp *DexKeeper) recordNewOrder(ctx sdk.Context, sender sdk.AccAddress) {
+	if !sdk.IsUpgrade(upgrade.DexEnhancements) || kp.orderLimits.MaxNewOrdersPerBlock <= 0 {
+		return
+	}
+	if counter := kp.newOrderCounter(ctx); counter != nil {
+		counter.add(ctx.BlockHeader().Height, sender)
+	}
+}
+
+// countOpenOrders returns the number of the open orders of addr in each pair, including the untriggered stop orders
+func (kp *DexKeeper) countOpenOrders(addr sdk.AccAddress) map[string]int64 {
+	counts := make(map[string]int64)
+	for _, orderKeeper := range kp.OrderKeepers {
+		if orderKeeper.supportUpgradeVersion() {
+			for symbol, count := range orderKeeper.countOpenOrdersOfSender(addr) {
+				counts[symbol] += count
+			}
+		}
+	}
+	return counts
+}
+
+// validateOrderLimits checks the new order of the sender against the order limits. The open orders are the ones
+// delivered, so the orders still in the mempool are only bounded by the new order rate limit in CheckTx.
+func (kp *DexKeeper) validateOrderLimits(ctx sdk.Context, sender sdk.AccAddress, symbol string) error {
+	if !sdk.IsUpgrade(upgrade.DexEnhancements) {
+		return nil
+	}
+	limits := kp.orderLimits
+	if limits.MaxNewOrdersPerBlock > 0 {
+		if counter := kp.newOrderCounter(ctx); counter != nil && counter.count(ctx.BlockHeader().Height, sender) >= limits.MaxNewOrdersPerBlock {
+			return fmt.Errorf("the number of new orders in a block is limited to %d", limits.MaxNewOrdersPerBlock)
+		}
+	}
+	if limits.MaxOpenOrdersPerPair <= 0 && limits.MaxOpenOrders <= 0 {
+		return nil
+	}
+	counts := kp.countOpenOrders(sender)
+	if limits.MaxOpenOrdersPerPair > 0 && counts[symbol] >= limits.MaxOpenOrdersPerPair {
+		return fmt.Errorf("the number of open orders in %s is limited to %d", symbol, limits.MaxOpenOrdersPerPair)
+	}
+	if limits.MaxOpenOrders > 0 {
+		var total int64
+		for _, count := range counts {
+			total += count
+		}
+		if total >= limits.MaxOpenOrders {
+			return fmt.Errorf("the number of open orders is limited to %d", limits.MaxOpenOrders)
+		}
+	}
+	return nil
+}
+
+// GetOrderLimits returns the order limits along with the open orders of addr in each pair
+func (kp *DexKeeper) GetOrderLimits(addr sdk.AccAddress) store.OrderLimits {
+	limits := store.OrderLimits{
+		MaxOpenOrdersPerPair: kp.orderLimits.MaxOpenOrdersPerPair,
+		MaxOpenOrders:        kp.orderLimits.MaxOpenOrders,
+		MaxNewOrdersPerBlock: kp.orderLimits.MaxNewOrdersPerBlock,
+		OpenOrdersPerPair:    make([]store.PairOpenOrders, 0),
+	}
+	for symbol, count := range kp.countOpenOrders(addr) {
+		limits.OpenOrders += count
+		limits.OpenOrdersPerPair = append(limits.OpenOrdersPerPair, store.PairOpenOrders{Symbol: symbol, Count: count})
+	}
+	sort.Slice(limits.OpenOrdersPerPair, func(i, j int) bool {
+		return limits.OpenOrdersPerPair[i].Symbol < limits.OpenOrdersPerPair[j].Symbol
+	})
+	return limits
+}
@@ -0,0 +1,66 @@
+package order
+
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	bnclog "github.com/bnb-chain/node/common/log"
+	"github.com/bnb-chain/node/common/types"
+	"github.com/bnb-chain/node/common/utils"
+	dexUtils "github.com/bnb-chain/node/plugins/dex/utils"
+)
+
+// the minimum notional of the existing pairs is set to the value of 0.001 native token when the limits are introduced
+const DefaultMinNotionalNative int64 = 1e5
+
+// SetOrderSizeLimits updates the order size limits of the pair, the nil limits are unchanged
+func (kp *DexKeeper) SetOrderSizeLimits(ctx sdk.Context, symbol string, minNotional, maxQuantity *int64) error {
+	symbol = strings.ToUpper(symbol)
+	if _, ok := kp.engines[symbol]; !ok {
+		return fmt.Errorf("trading pair %s does not exist", symbol)
+	}
+	baseAsset, quoteAsset, err := dexUtils.TradingPair2Assets(symbol)
+	if err != nil {
+		return err
+	}
+	pair, err := kp.PairMapper.GetTradingPair(ctx, baseAsset, quoteAsset)
+	if err != nil {
+		return err
+	}
+	if minNotional != nil {
+		pair.MinNotional = utils.Fixed8(*minNotional)
+	}
+	if maxQuantity != nil {
+		pair.MaxQuantity = utils.Fixed8(*maxQuantity)
+	}
+	return kp.PairMapper.AddTradingPair(ctx, pair)
+}
+
+// DefaultMinNotional returns the value of DefaultMinNotionalNative in the quote asset at the last trade price,
+// or 0 if the quote asset can not be priced against the native token
+func (kp *DexKeeper) DefaultMinNotional(quoteAsset string) int64 {
+	if quoteAsset == types.NativeTokenSymbol {
+		return DefaultMinNotionalNative
+	}
+	notional, ok := kp.FeeManager.calcNotional(types.NativeTokenSymbol, DefaultMinNotionalNative, quoteAsset, kp.engines)
+	if !ok || !notional.IsInt64() {
+		return 0
+	}
+	return notional.Int64()
+}
+
+// InitOrderSizeLimits sets the default minimum notional of the existing pairs whose limits are not set yet. The
+// maximum quantity is left unlimited unless it is set by governance.
+func (kp *DexKeeper) InitOrderSizeLimits(ctx sdk.Context) {
+	for _, pair := range kp.PairMapper.ListAllTradingPairs(ctx) {
+		if pair.MinNotional != 0 {
+			continue
+		}
+		pair.MinNotional = utils.Fixed8(kp.DefaultMinNotional(pair.QuoteAssetSymbol))
+		if err := kp.PairMapper.AddTradingPair(ctx, pair); err != nil {
+			bnclog.With("module", "dex").Error("failed to init order size limits", "symbol", pair.GetSymbol(), "err", err.Error())
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package order
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	dbm "github.com/tendermint/tendermint/libs/db"
+
+	"github.com/bnb-chain/node/common/utils"
+	me "github.com/bnb-chain/node/plugins/dex/matcheng"
+	"github.com/bnb-chain/node/plugins/dex/store"
+	"github.com/bnb-chain/node/wire"
+)
+
+// NumRecentTrades is the number of the latest trades kept for every pair
+const NumRecentTrades = 500
+
+var recentTradesSnapshotKey = []byte("recenttrades")
+
+type recentTradesOfPair struct {
+	Symbol string
+	Trades []store.RecentTrade
+}
+
+type recentTradesSnapshot struct {
+	Height int64
+	Pairs  []recentTradesOfPair
+}
+
+// RecentTradesStore keeps the latest trades of every pair in memory. The trades are saved into a database out of
+// the consensus state at breathe blocks, and the ones after the breathe block are rebuilt along with the order book.
+type RecentTradesStore struct {
+	mtx    sync.Mutex
+	trades map[string]*utils.FixedSizeRing // symbol -> latest NumRecentTrades trades
+	loadDB func() dbm.DB
+	cdc    *wire.Codec
+}
+
+func NewRecentTradesStore(loadDB func() dbm.DB, cdc *wire.Codec) *RecentTradesStore {
+	return &RecentTradesStore{
+		trades: make(map[string]*utils.FixedSizeRing),
+		loadDB: loadDB,
+		cdc:    cdc,
+	}
+}
+
+func makerSide(tickType int8) string {
+	switch tickType {
+	case me.SellTaker:
+		return "BUY"
+	case me.BuyTaker:
+		return "SELL"
+	default:
+		return ""
+	}
+}
+
+func (rs *RecentTradesStore) addTrades(symbol string, height, timestamp int64, trades []me.Trade) {
+	if len(trades) == 0 {
+		return
+	}
+	rs.mtx.Lock()
+	defer rs.mtx.Unlock()
+	ring, ok := rs.trades[symbol]
+	if !ok {
+		ring = utils.NewFixedSizedRing(NumRecentTrades)
+		rs.trades[symbol] = ring
+	}
+	for _, t := range trades {
+		ring.Push(store.RecentTrade{
+			Price:         utils.Fixed8(t.LastPx),
+			Quantity:      utils.Fixed8(t.LastQty),
+			BuyerOrderId:  t.Bid,
+			SellerOrderId: t.Sid,
+			MakerSide:     makerSide(t.TickType),
+			Height:        height,
+			Time:          timestamp,
+		})
+	}
+}
+
+func (rs *RecentTradesStore) removePair(symbol string) {
+	rs.mtx.Lock()
+	defer rs.mtx.Unlock()
+	delete(rs.trades, symbol)
+}
+
+// getTrades returns the latest trades of the pair, in the order they were concluded
+func (rs *RecentTradesStore) getTrades(symbol string, limit int) []store.RecentTrade {
+	rs.mtx.Lock()
+	defer rs.mtx.Unlock()
+	ring, ok := rs.trades[symbol]
+	if !ok {
+		return []store.RecentTrade{}
+	}
+	elements := ring.Elements()
+	if len(elements) > limit {
+		elements = elements[len(elements)-limit:]
+	}
+	trades := make([]store.RecentTrade, len(elements))
+	for i, e := range elements {
+		trades[i] = e.(store.RecentTrade)
+	}
+	return trades
+}
+
+func (rs *RecentTradesStore) snapshot(height int64) {
+	rs.mtx.Lock()
+	defer rs.mtx.Unlock()
+	symbols := make([]string, 0, len(rs.trades))
+	for symbol := range rs.trades {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	snapshot := recentTradesSnapshot{Height: height}
+	for _, symbol := range symbols {
+		elements := rs.trades[symbol].Elements()
+		trades := make([]store.RecentTrade, len(elements))
+		for i, e := range elements {
+			trades[i] = e.(store.RecentTrade)
+		}
+		snapshot.Pairs = append(snapshot.Pairs, recentTradesOfPair{symbol, trades})
+	}
+	db := rs.loadDB()
+	defer db.Close()
+	db.SetSync(recentTradesSnapshotKey, rs.cdc.MustMarshalBinaryBare(snapshot))
+}
+
+// load reloads the trades saved at a breathe block. The trades after the height are dropped,
+// as they are added again when the blocks after the height are replayed.
+func (rs *RecentTradesStore) load(height int64) {
+	rs.mtx.Lock()
+	defer rs.mtx.Unlock()
+	db := rs.loadDB()
+	defer db.Close()
+	bz := db.Get(recentTradesSnapshotKey)
+	if bz == nil {
+		return
+	}
+	var snapshot recentTradesSnapshot
+	rs.cdc.MustUnmarshalBinaryBare(bz, &snapshot)
+	for _, pair := range snapshot.Pairs {
+		ring := utils.NewFixedSizedRing(NumRecentTrades)
+		for _, t := range pair.Trades {
+			if t.Height <= height {
+				ring.Push(t)
+			}
+		}
+		if !ring.IsEmpty() {
+			rs.trades[pair.Symbol] = ring
+		}
+	}
+}
+
+// SetRecentTradesStore enables the recent trades, which are saved into the db at breathe blocks.
+// The db is only opened when the trades are saved or loaded.
+func (kp *DexKeeper) SetRecentTradesStore(loadDB func() dbm.DB) {
+	kp.recentTrades = NewRecentTradesStore(loadDB, kp.cdc)
+}
+
+// SnapshotRecentTrades saves the recent trades of all the pairs at the breathe block
+func (kp *DexKeeper) SnapshotRecentTrades(height int64) {
+	if kp.recentTrades != nil {
+		kp.recentTrades.snapshot(height)
+	}
+}
+
+// GetRecentTrades returns at most limit latest trades of the pair
+func (kp *DexKeeper) GetRecentTrades(pair string, limit int) ([]store.RecentTrade, error) {
+	if kp.recentTrades == nil {
+		return nil, fmt.Errorf("recent trades are not maintained by this node")
+	}
+	pair = strings.ToUpper(pair)
+	if _, ok := kp.engines[pair]; !ok {
+		return nil, fmt.Errorf("trading pair %s does not exist", pair)
+	}
+	return kp.recentTrades.getTrades(pair, limit), nil
+}
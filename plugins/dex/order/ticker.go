@@ -0,0 +1,202 @@
+package order
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	tmstore "github.com/tendermint/tendermint/store"
+
+	"github.com/bnb-chain/node/common/utils"
+	me "github.com/bnb-chain/node/plugins/dex/matcheng"
+	"github.com/bnb-chain/node/plugins/dex/store"
+	dexUtils "github.com/bnb-chain/node/plugins/dex/utils"
+)
+
+const (
+	tickerWindowSeconds = 24 * 60 * 60
+	tickerBucketSeconds = 60
+)
+
+// tickerBucket is the statistics of the trades of a pair in a minute
+type tickerBucket struct {
+	openTime    int64
+	open        int64
+	high        int64
+	low         int64
+	volume      int64
+	quoteVolume int64
+	count       int64
+}
+
+// TickerStore keeps the trades of the latest 24 hours of every pair in buckets of a minute, in memory only.
+// The buckets are rebuilt by replaying the blocks when the node restarts.
+type TickerStore struct {
+	mtx      sync.Mutex
+	buckets  map[string][]tickerBucket // symbol -> buckets in the ascending order of the open time
+	lastTime int64                     // the time of the last matched block in unix seconds
+}
+
+func NewTickerStore() *TickerStore {
+	return &TickerStore{
+		buckets: make(map[string][]tickerBucket),
+	}
+}
+
+// addTrades adds the trades of the pair concluded at the timestamp in nanoseconds
+func (ts *TickerStore) addTrades(symbol string, timestamp int64, trades []me.Trade) {
+	if len(trades) == 0 {
+		return
+	}
+	ts.mtx.Lock()
+	defer ts.mtx.Unlock()
+	seconds := timestamp / 1e9
+	openTime := seconds - seconds%tickerBucketSeconds
+	buckets := ts.buckets[symbol]
+	// drop the buckets out of the window
+	expired := 0
+	for expired < len(buckets) && buckets[expired].openTime+tickerBucketSeconds <= seconds-tickerWindowSeconds {
+		expired++
+	}
+	buckets = buckets[expired:]
+	if len(buckets) == 0 || buckets[len(buckets)-1].openTime != openTime {
+		buckets = append(buckets, tickerBucket{openTime: openTime, open: trades[0].LastPx, high: trades[0].LastPx,
+			low: trades[0].LastPx})
+	}
+	bucket := &buckets[len(buckets)-1]
+	for _, t := range trades {
+		if t.LastPx > bucket.high {
+			bucket.high = t.LastPx
+		}
+		if t.LastPx < bucket.low {
+			bucket.low = t.LastPx
+		}
+		bucket.volume += t.LastQty
+		bucket.quoteVolume += dexUtils.CalBigNotionalInt64(t.LastPx, t.LastQty)
+		bucket.count++
+	}
+	ts.buckets[symbol] = buckets
+}
+
+func (ts *TickerStore) setLastTime(timestamp int64) {
+	ts.mtx.Lock()
+	defer ts.mtx.Unlock()
+	ts.lastTime = timestamp / 1e9
+}
+
+func (ts *TickerStore) removePair(symbol string) {
+	ts.mtx.Lock()
+	defer ts.mtx.Unlock()
+	delete(ts.buckets, symbol)
+}
+
+// getTicker aggregates the buckets in the 24 hours before the last matched block
+func (ts *TickerStore) getTicker(symbol string) store.Ticker {
+	ts.mtx.Lock()
+	defer ts.mtx.Unlock()
+	ticker := store.Ticker{
+		Symbol:    symbol,
+		OpenTime:  ts.lastTime - tickerWindowSeconds,
+		CloseTime: ts.lastTime,
+	}
+	for _, bucket := range ts.buckets[symbol] {
+		if bucket.openTime < ticker.OpenTime {
+			continue
+		}
+		if ticker.Count == 0 {
+			ticker.OpenPrice = utils.Fixed8(bucket.open)
+			ticker.HighPrice = utils.Fixed8(bucket.high)
+			ticker.LowPrice = utils.Fixed8(bucket.low)
+		}
+		if utils.Fixed8(bucket.high) > ticker.HighPrice {
+			ticker.HighPrice = utils.Fixed8(bucket.high)
+		}
+		if utils.Fixed8(bucket.low) < ticker.LowPrice {
+			ticker.LowPrice = utils.Fixed8(bucket.low)
+		}
+		ticker.Volume += utils.Fixed8(bucket.volume)
+		ticker.QuoteVolume += utils.Fixed8(bucket.quoteVolume)
+		ticker.Count += bucket.count
+	}
+	return ticker
+}
+
+// GetTicker returns the 24 hours statistics of the pair, along with the last price and the best bid and ask
+func (kp *DexKeeper) GetTicker(pair string) (store.Ticker, error) {
+	pair = strings.ToUpper(pair)
+	eng, ok := kp.engines[pair]
+	if !ok {
+		return store.Ticker{}, fmt.Errorf("trading pair %s does not exist", pair)
+	}
+	ticker := kp.tickers.getTicker(pair)
+	ticker.LastPrice = utils.Fixed8(eng.LastTradePrice)
+	levels, _ := kp.GetOrderBookLevels(pair, 1)
+	ticker.BidPrice, ticker.BidQuantity = levels[0].BuyPrice, levels[0].BuyQty
+	ticker.AskPrice, ticker.AskQuantity = levels[0].SellPrice, levels[0].SellQty
+	return ticker, nil
+}
+
+// GetTickers returns the 24 hours statistics of all the pairs, ordered by the symbol
+func (kp *DexKeeper) GetTickers() []store.Ticker {
+	symbols := make([]string, 0, len(kp.engines))
+	for symbol := range kp.engines {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	tickers := make([]store.Ticker, 0, len(symbols))
+	for _, symbol := range symbols {
+		if ticker, err := kp.GetTicker(symbol); err == nil {
+			tickers = append(tickers, ticker)
+		}
+	}
+	return tickers
+}
+
+// initTickers rebuilds the trades in the day before the last breathe block, by replaying the blocks since the breathe block
+// before it with a separate keeper. The trades after the last breathe block are rebuilt when the order book is recovered.
+// The statistics are not a part of the state, so a failure of the replay is logged rather than stopping the node.
+func (kp *DexKeeper) initTickers(ctx sdk.Context, blockInterval, daysBack int, blockStore *tmstore.BlockStore,
+	stateDB dbm.DB, lastHeight int64, txDecoder sdk.TxDecoder) {
+	if lastHeight == 0 {
+		return
+	}
+	breatheHeight := kp.GetLastBreatheBlockHeight(ctx, lastHeight, blockStore.LoadBlock(lastHeight).Time, blockInterval, daysBack)
+	if breatheHeight == 0 {
+		return
+	}
+	breatheBlock := blockStore.LoadBlock(breatheHeight)
+	if breatheBlock == nil {
+		kp.logger.Error("Failed to load the breathe block to rebuild tickers", "height", breatheHeight)
+		return
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			kp.logger.Error("Failed to rebuild tickers, the statistics before the breathe block are dropped", "err", r)
+		}
+	}()
+
+	// the breathe block of the day before is located from a block of that day
+	prevDay := breatheBlock.Time.AddDate(0, 0, -1)
+	prevHeight := kp.GetLastBreatheBlockHeight(ctx, breatheHeight-1, prevDay, blockInterval, daysBack)
+	if blockStore.LoadBlock(prevHeight+1) == nil {
+		kp.logger.Info("Blocks to rebuild tickers are not available", "height", prevHeight+1)
+		return
+	}
+
+	replayer := NewDexKeeper(kp.storeKey, kp.am, kp.PairMapper, kp.codespace, kp.poolSize, kp.cdc, false)
+	replayer.selfTradePrevention = kp.selfTradePrevention
+	replayer.orderBookTypes = kp.orderBookTypes
+	replayer.circuitBreakerTrips = copyCircuitBreakerTrips(kp.circuitBreakerTrips)
+	if _, err := replayer.LoadOrderBookSnapshot(ctx, breatheHeight-1, prevDay, blockInterval, daysBack); err != nil {
+		panic(err)
+	}
+	kp.logger.Info("Rebuilding tickers", "fromHeight", prevHeight, "toHeight", breatheHeight)
+	if err := replayer.ReplayOrdersFromBlock(ctx, blockStore, stateDB, breatheHeight, prevHeight, txDecoder); err != nil {
+		panic(err)
+	}
+	// the replayer keeps its own tickers, which are taken only if the replay succeeds
+	kp.tickers = replayer.tickers
+}
@@ -0,0 +1,110 @@
+package order
+
+import (
+	"math"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	me "github.com/bnb-chain/node/plugins/dex/matcheng"
+)
+
+const (
+	tradingVolumeKeyPrefix = "volume_"
+	// the volume of an account decides its fee tier, which is summed over the trailing days
+	TradingVolumeDays = 30
+
+	secondsPerDay int64 = 24 * 60 * 60
+)
+
+// TradingVolume is the daily notional volume in the native token traded by an account,
+// Daily[day % TradingVolumeDays] is the volume of the day, and LastDay is the last day traded.
+type TradingVolume struct {
+	LastDay int64   `json:"last_day"`
+	Daily   []int64 `json:"daily"`
+}
+
+func genTradingVolumeKey(addr sdk.AccAddress) []byte {
+	return append([]byte(tradingVolumeKeyPrefix), addr.Bytes()...)
+}
+
+func dayOfTime(ctx sdk.Context) int64 {
+	return ctx.BlockHeader().Time.Unix() / secondsPerDay
+}
+
+// roll clears the days out of the trailing window ending at the day
+func (v *TradingVolume) roll(day int64) {
+	if len(v.Daily) != TradingVolumeDays {
+		v.Daily = make([]int64, TradingVolumeDays)
+	}
+	for d := v.LastDay + 1; d <= day && d <= v.LastDay+TradingVolumeDays; d++ {
+		v.Daily[d%TradingVolumeDays] = 0
+	}
+	if day > v.LastDay {
+		v.LastDay = day
+	}
+}
+
+func (v *TradingVolume) total() int64 {
+	var total int64
+	for _, volume := range v.Daily {
+		if total > math.MaxInt64-volume {
+			return math.MaxInt64
+		}
+		total += volume
+	}
+	return total
+}
+
+func (kp *DexKeeper) getTradingVolume(ctx sdk.Context, addr sdk.AccAddress) *TradingVolume {
+	var volume TradingVolume
+	bz := ctx.KVStore(kp.storeKey).Get(genTradingVolumeKey(addr))
+	if bz != nil {
+		kp.cdc.MustUnmarshalBinaryBare(bz, &volume)
+	}
+	volume.roll(dayOfTime(ctx))
+	return &volume
+}
+
+// GetTradingVolume returns the notional volume in the native token traded by the account in the trailing days
+func (kp *DexKeeper) GetTradingVolume(ctx sdk.Context, addr sdk.AccAddress) int64 {
+	return kp.getTradingVolume(ctx, addr).total()
+}
+
+func (kp *DexKeeper) addTradingVolume(ctx sdk.Context, addr sdk.AccAddress, volume *TradingVolume, amount int64) {
+	if amount <= 0 {
+		return
+	}
+	day := volume.LastDay % TradingVolumeDays
+	if volume.Daily[day] > math.MaxInt64-amount {
+		volume.Daily[day] = math.MaxInt64
+	} else {
+		volume.Daily[day] += amount
+	}
+	ctx.KVStore(kp.storeKey).Set(genTradingVolumeKey(addr), kp.cdc.MustMarshalBinaryBare(*volume))
+}
+
+func (kp *DexKeeper) resetTradeFeeTiers() {
+	if kp.FeeManager.HasFeeTiers() {
+		kp.tradeFeeTiers = make(map[string]int)
+	} else {
+		kp.tradeFeeTiers = nil
+	}
+}
+
+// SerializeTradeFeeForPub serializes the fee charged to the buyer or the seller of a trade of the last match, along with
+// the fee tier applied if the fee tiers are configured and the rebate paid if it is the maker
+func (kp *DexKeeper) SerializeTradeFeeForPub(trade *me.Trade, isBuyer bool) string {
+	fee, orderId := trade.SellerFee, trade.Sid
+	if isBuyer {
+		fee, orderId = trade.BuyerFee, trade.Bid
+	}
+	// nilness check is for before Galileo upgrade the trade fee is nil
+	if fee == nil {
+		return ""
+	}
+	var rebate sdk.Coins
+	if isMaker(trade, isBuyer) {
+		rebate = kp.tradeRebates[genTradeKey(trade)]
+	}
+	return kp.FeeManager.SerializeTradeFeeForPub(*fee, kp.tradeFeeTiers[orderId], rebate)
+}
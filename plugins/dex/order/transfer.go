@@ -22,6 +22,9 @@ const (
 	eventFullyCancel
 	eventPartiallyCancel
 	eventCancelForMatchFailure
+	eventFOKExpire
+	eventAmend
+	eventSelfTradePrevented
 )
 
 // Transfer represents a transfer between trade currencies
@@ -37,24 +40,35 @@ type Transfer struct {
 	Fee        sdk.Fee
 	Trade      *me.Trade
 	Symbol     string
+	feeTier    int
 }
 
 func (tran Transfer) FeeFree() bool {
 	return tran.eventType == eventPartiallyExpire ||
 		tran.eventType == eventIOCPartiallyExpire ||
 		tran.eventType == eventPartiallyCancel ||
-		tran.eventType == eventCancelForMatchFailure
+		tran.eventType == eventCancelForMatchFailure ||
+		tran.eventType == eventSelfTradePrevented
 }
 
 func (tran Transfer) IsExpire() bool {
 	return tran.eventType == eventIOCFullyExpire ||
+		tran.eventType == eventFOKExpire ||
 		tran.eventType == eventIOCPartiallyExpire ||
 		tran.eventType == eventPartiallyExpire ||
 		tran.eventType == eventFullyExpire
 }
 
 func (tran Transfer) IsExpiredWithFee() bool {
-	return tran.eventType == eventFullyExpire || tran.eventType == eventIOCFullyExpire
+	return tran.eventType == eventFullyExpire || tran.eventType == eventIOCFullyExpire || tran.eventType == eventFOKExpire
+}
+
+func (tran Transfer) IsFOKExpire() bool {
+	return tran.eventType == eventFOKExpire
+}
+
+func (tran Transfer) IsSelfTradePrevented() bool {
+	return tran.eventType == eventSelfTradePrevented
 }
 
 func (tran Transfer) IsNativeIn() bool {
@@ -69,6 +83,21 @@ func (tran *Transfer) IsBuyer() bool {
 	return tran.Oid == tran.Trade.Bid
 }
 
+// isMaker returns true if the order of the transfer was resting in the order book when the trade happened
+func (tran *Transfer) isMaker() bool {
+	if tran.Trade == nil {
+		return false
+	}
+	return isMaker(tran.Trade, tran.IsBuyer())
+}
+
+func isMaker(trade *me.Trade, isBuyer bool) bool {
+	if isBuyer {
+		return trade.TickType == int8(me.SellTaker)
+	}
+	return trade.TickType == int8(me.BuyTaker)
+}
+
 func (tran *Transfer) String() string {
 	return fmt.Sprintf("Transfer[eventType:%v, oid:%v, inAsset:%v, inQty:%v, outAsset:%v, outQty:%v, unlock:%v, fee:%v]",
 		tran.eventType, tran.Oid, tran.inAsset, tran.in, tran.outAsset, tran.out, tran.unlock, tran.Fee)
@@ -112,7 +141,9 @@ func TransferFromTrade(trade *me.Trade, symbol string, orderMap map[string]*Orde
 
 func TransferFromExpired(ord me.OrderPart, ordMsg OrderInfo) Transfer {
 	var tranEventType transferEventType
-	if ord.CumQty != 0 {
+	if ordMsg.TimeInForce == TimeInForce.FOK {
+		tranEventType = eventFOKExpire // FOK is never partially filled
+	} else if ord.CumQty != 0 {
 		if ordMsg.TimeInForce == TimeInForce.IOC {
 			tranEventType = eventIOCPartiallyExpire // IOC partially filled
 		} else {
@@ -144,6 +175,12 @@ func TransferFromCanceled(ord me.OrderPart, ordMsg OrderInfo, isMatchFailure boo
 	return transferFromOrderRemoved(ord, ordMsg, tranEventType)
 }
 
+// TransferFromSelfTradePrevented releases the quantity of the order which is canceled or decremented
+// by the self-trade prevention, ord only needs to carry the released quantity.
+func TransferFromSelfTradePrevented(ord me.OrderPart, ordMsg OrderInfo) Transfer {
+	return transferFromOrderRemoved(ord, ordMsg, eventSelfTradePrevented)
+}
+
 func transferFromOrderRemoved(ord me.OrderPart, ordMsg OrderInfo, tranEventType transferEventType) Transfer {
 	//here is a trick to use the same currency as in and out ccy to simulate cancel
 	qty := ord.LeavesQty()
@@ -11,15 +11,21 @@ import (
 type ChangeType uint8
 
 const (
-	Ack            ChangeType = iota // new order tx
-	Canceled                         // cancel order tx
-	Expired                          // expired for gte order
-	IocNoFill                        // ioc order is not filled expire
-	IocExpire                        // ioc order is partial filled expire
-	PartialFill                      // order is partial filled, derived from trade
-	FullyFill                        // order is fully filled, derived from trade
-	FailedBlocking                   // order tx is failed blocking, we only publish essential message
-	FailedMatching                   // order failed matching
+	Ack                ChangeType = iota // new order tx
+	Canceled                             // cancel order tx
+	Expired                              // expired for gte order
+	IocNoFill                            // ioc order is not filled expire
+	IocExpire                            // ioc order is partial filled expire
+	PartialFill                          // order is partial filled, derived from trade
+	FullyFill                            // order is fully filled, derived from trade
+	FailedBlocking                       // order tx is failed blocking, we only publish essential message
+	FailedMatching                       // order failed matching
+	StopPlaced                           // stop order tx, the order is parked until triggered
+	StopTriggered                        // stop order is triggered and enters the order book as a limit order
+	PostOnlyRejected                     // post-only order is rejected as it would be executed as a taker
+	FokNoFill                            // fok order cannot be fully filled and is killed
+	Amended                              // amend order tx, the price and/or quantity of the order is changed
+	SelfTradePrevented                   // order is canceled as it would trade with another order of the same sender
 )
 
 // True for should not remove order in these status from OrderInfoForPub
@@ -28,7 +34,10 @@ func (tpe ChangeType) IsOpen() bool {
 	// FailedBlocking tx doesn't effect OrderInfoForPub, should not be put into closedToPublish
 	return tpe == Ack ||
 		tpe == PartialFill ||
-		tpe == FailedBlocking
+		tpe == FailedBlocking ||
+		tpe == StopPlaced ||
+		tpe == StopTriggered ||
+		tpe == Amended
 }
 
 func (tpe ChangeType) String() string {
@@ -51,6 +60,18 @@ func (tpe ChangeType) String() string {
 		return "FailedBlocking"
 	case FailedMatching:
 		return "FailedMatching"
+	case StopPlaced:
+		return "StopPlaced"
+	case StopTriggered:
+		return "StopTriggered"
+	case PostOnlyRejected:
+		return "PostOnlyRejected"
+	case FokNoFill:
+		return "FokNoFill"
+	case Amended:
+		return "Amended"
+	case SelfTradePrevented:
+		return "SelfTradePrevented"
 	default:
 		return "Unknown"
 	}
@@ -75,7 +96,7 @@ type OrderChange struct {
 	Id             string
 	Tpe            ChangeType
 	SingleFee      string
-	MsgForFailedTx interface{} // pointer to NewOrderMsg or CancelOrderMsg
+	MsgForFailedTx interface{} // pointer to NewOrderMsg, CancelOrderMsg or AmendOrderMsg; for Amended, the OrderInfo before amendment
 }
 
 func (oc OrderChange) String() string {
@@ -92,6 +113,10 @@ func (oc OrderChange) failedBlockingMsg() *OrderInfo {
 		return &OrderInfo{
 			NewOrderMsg: NewOrderMsg{Sender: msg.Sender, Id: msg.RefId, Symbol: msg.Symbol},
 		}
+	case AmendOrderMsg:
+		return &OrderInfo{
+			NewOrderMsg: NewOrderMsg{Sender: msg.Sender, Id: msg.RefId, Symbol: msg.Symbol},
+		}
 	default:
 		return nil
 	}
@@ -2,6 +2,7 @@ package dex
 
 import (
 	"encoding/json"
+	"sort"
 	"strings"
 	"time"
 
@@ -11,6 +12,9 @@ import (
 	"github.com/bnb-chain/node/app/pub"
 	bnclog "github.com/bnb-chain/node/common/log"
 	app "github.com/bnb-chain/node/common/types"
+	"github.com/bnb-chain/node/common/upgrade"
+	"github.com/bnb-chain/node/plugins/dex/list"
+	"github.com/bnb-chain/node/plugins/dex/types"
 	"github.com/bnb-chain/node/plugins/dex/utils"
 	"github.com/bnb-chain/node/plugins/tokens"
 )
@@ -35,16 +39,41 @@ func InitPlugin(
 	//dex mini handler
 	dexMiniHandler := createQueryHandler(dexKeeper, DexMiniAbciQueryPrefix)
 	appp.RegisterQueryHandler(DexMiniAbciQueryPrefix, dexMiniHandler)
+	RegisterUpgradeBeginBlocker(dexKeeper)
+}
+
+func RegisterUpgradeBeginBlocker(dexKeeper *DexKeeper) {
+	// the existing pairs have no order size limits before the upgrade
+	upgrade.Mgr.RegisterBeginBlocker(upgrade.OrderSizeLimits, func(ctx sdk.Context) {
+		dexKeeper.InitOrderSizeLimits(ctx)
+	})
 }
 
 func createQueryHandler(keeper *DexKeeper, abciQueryPrefix string) app.AbciQueryHandler {
 	return createAbciQueryHandler(keeper, abciQueryPrefix)
 }
 
+// EndBlock expires the orders whose good-till height or time is reached, which runs at every block after the match.
+func EndBlock(ctx sdk.Context, dexKeeper *DexKeeper) {
+	if !sdk.IsUpgrade(upgrade.DexEnhancements) {
+		return
+	}
+	if dexKeeper.ShouldPublishOrder() {
+		pub.ExpireGoodTillOrdersForPublish(dexKeeper, ctx)
+	} else {
+		dexKeeper.ExpireGoodTillOrders(ctx, nil)
+	}
+}
+
 // EndBreatheBlock processes the breathe block lifecycle event.
 func EndBreatheBlock(ctx sdk.Context, dexKeeper *DexKeeper, govKeeper gov.Keeper, height int64, blockTime time.Time) {
 	logger := bnclog.With("module", "dex")
 
+	if sdk.IsUpgrade(upgrade.DexEnhancements) {
+		logger.Info("Update trading statuses", "blockHeight", height)
+		updateTradingStatuses(ctx, govKeeper, dexKeeper, blockTime)
+	}
+
 	logger.Info("Delist trading pairs", "blockHeight", height)
 	delistTradingPairs(ctx, govKeeper, dexKeeper, blockTime)
 
@@ -60,10 +89,14 @@ func EndBreatheBlock(ctx sdk.Context, dexKeeper *DexKeeper, govKeeper gov.Keeper
 
 	logger.Info("Mark BreathBlock", "blockHeight", height)
 	dexKeeper.MarkBreatheBlock(ctx, height, blockTime)
+	logger.Info("Prune circuit breaker trips", "blockHeight", height)
+	dexKeeper.PruneCircuitBreakerTrips(ctx, height)
 	logger.Info("Save Orderbook snapshot", "blockHeight", height)
 	if _, err := dexKeeper.SnapShotOrderBook(ctx, height); err != nil {
 		logger.Error("Failed to snapshot order book", "blockHeight", height, "err", err)
 	}
+	logger.Info("Save recent trades", "blockHeight", height)
+	dexKeeper.SnapshotRecentTrades(height)
 }
 
 func delistTradingPairs(ctx sdk.Context, govKeeper gov.Keeper, dexKeeper *DexKeeper, blockTime time.Time) {
@@ -132,6 +165,94 @@ func getSymbolsToDelist(ctx sdk.Context, govKeeper gov.Keeper, blockTime time.Ti
 	return symbols
 }
 
+type tradingStatusChange struct {
+	proposalId  int64
+	passedTime  time.Time
+	symbol      string
+	status      *types.TradingStatus
+	minNotional *int64
+	maxQuantity *int64
+}
+
+func updateTradingStatuses(ctx sdk.Context, govKeeper gov.Keeper, dexKeeper *DexKeeper, blockTime time.Time) {
+	logger := bnclog.With("module", "dex")
+
+	changes := make([]tradingStatusChange, 0)
+	periodToSearch := getPeriodToSearch(ctx, govKeeper)
+
+	govKeeper.Iterate(ctx, nil, nil, gov.StatusPassed, -1, true, func(proposal gov.Proposal) bool {
+		// we do not need to search for all proposals
+		if proposal.GetSubmitTime().Add(periodToSearch).Before(blockTime) {
+			return true
+		}
+
+		if list.IsTradingStatusProposal(proposal) {
+			var statusParam types.TradingStatusParams
+			err := json.Unmarshal([]byte(proposal.GetDescription()), &statusParam)
+			if err != nil {
+				logger.Error("illegal trading status params in proposal", "params", proposal.GetDescription())
+				return false
+			}
+
+			if statusParam.IsExecuted {
+				return false
+			}
+
+			var status *types.TradingStatus
+			if statusParam.Status != "" {
+				parsed, err := types.ParseTradingStatus(statusParam.Status)
+				if err != nil {
+					logger.Error("illegal trading status in proposal", "params", proposal.GetDescription())
+					return false
+				}
+				status = &parsed
+			}
+			symbol := utils.Assets2TradingPair(strings.ToUpper(statusParam.BaseAssetSymbol), strings.ToUpper(statusParam.QuoteAssetSymbol))
+			changes = append(changes, tradingStatusChange{
+				proposalId:  proposal.GetProposalID(),
+				passedTime:  proposal.GetVotingStartTime().Add(proposal.GetVotingPeriod()),
+				symbol:      symbol,
+				status:      status,
+				minNotional: statusParam.MinNotional,
+				maxQuantity: statusParam.MaxQuantity,
+			})
+			// update proposal executed status
+			statusParam.IsExecuted = true
+			bz, err := json.Marshal(statusParam)
+			if err != nil {
+				logger.Error("marshal trading status params error", "err", err.Error())
+				return false
+			}
+			proposal.SetDescription(string(bz))
+			govKeeper.SetProposal(ctx, proposal)
+		}
+		return false
+	})
+
+	// the proposals are iterated from the latest one, they are applied in the sequence they passed,
+	// so that the latest passed one takes effect if there are several proposals of the same pair
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].passedTime.Equal(changes[j].passedTime) {
+			return changes[i].proposalId < changes[j].proposalId
+		}
+		return changes[i].passedTime.Before(changes[j].passedTime)
+	})
+	for _, change := range changes {
+		if change.status != nil {
+			logger.Info("Update trading status", "symbol", change.symbol, "status", *change.status, "proposalId", change.proposalId)
+			if err := dexKeeper.SetTradingStatus(ctx, change.symbol, *change.status, change.proposalId); err != nil {
+				logger.Error("can not update trading status", "symbol", change.symbol, "err", err.Error())
+			}
+		}
+		if change.minNotional != nil || change.maxQuantity != nil {
+			logger.Info("Update order size limits", "symbol", change.symbol, "proposalId", change.proposalId)
+			if err := dexKeeper.SetOrderSizeLimits(ctx, change.symbol, change.minNotional, change.maxQuantity); err != nil {
+				logger.Error("can not update order size limits", "symbol", change.symbol, "err", err.Error())
+			}
+		}
+	}
+}
+
 func getPeriodToSearch(ctx sdk.Context, govKeeper gov.Keeper) time.Duration {
 	depositParams := govKeeper.GetDepositParams(ctx)
 	govMaxPeriod := depositParams.MaxDepositPeriod + gov.MaxVotingPeriod
@@ -16,6 +16,8 @@ func Routes(dexKeeper *DexKeeper, tokenMapper tokens.Mapper, govKeeper gov.Keepe
 	orderHandler := order.NewHandler(dexKeeper)
 	routes[order.RouteNewOrder] = orderHandler
 	routes[order.RouteCancelOrder] = orderHandler
+	routes[order.RouteAmendOrder] = orderHandler
+	routes[order.RouteCancelAllOrders] = orderHandler
 	routes[types.ListRoute] = list.NewHandler(dexKeeper, tokenMapper, govKeeper)
 	return routes
 }
@@ -69,3 +69,131 @@ func GetOpenOrders(cdc *wire.Codec, ctx context.CLIContext, pair string, addr st
 		return openOrders, err
 	}
 }
+
+// GetAllOpenOrders queries the open orders of an address in all the pairs
+func GetAllOpenOrders(cdc *wire.Codec, ctx context.CLIContext, addr string) ([]OpenOrder, error) {
+	bz, err := ctx.Query(fmt.Sprintf("dex/allopenorders/%s", addr), nil)
+	if err != nil {
+		return nil, err
+	}
+	if bz == nil {
+		return []OpenOrder{}, nil
+	}
+	return DecodeOpenOrders(cdc, &bz)
+}
+
+// GetIndicativePrice queries where the next auction of the pair would conclude
+func GetIndicativePrice(cdc *wire.Codec, ctx context.CLIContext, pair string) (*IndicativePrice, error) {
+	bz, err := ctx.Query(fmt.Sprintf("dex/indicative/%s", pair), nil)
+	if err != nil {
+		return nil, err
+	}
+	var price IndicativePrice
+	if err := cdc.UnmarshalBinaryLengthPrefixed(bz, &price); err != nil {
+		return nil, err
+	}
+	return &price, nil
+}
+
+// GetOrderBookL3 queries the resting orders of the price levels in [offset, offset+limit) of the pair
+func GetOrderBookL3(cdc *wire.Codec, ctx context.CLIContext, pair string, offset, limit int) (*OrderBookL3, error) {
+	bz, err := ctx.Query(fmt.Sprintf("dex/orderbookl3/%s/%d/%d", pair, offset, limit), nil)
+	if err != nil {
+		return nil, err
+	}
+	var book OrderBookL3
+	if err := cdc.UnmarshalBinaryLengthPrefixed(bz, &book); err != nil {
+		return nil, err
+	}
+	return &book, nil
+}
+
+// GetKlines queries the latest klines of the pair in the interval
+func GetKlines(cdc *wire.Codec, ctx context.CLIContext, pair, interval string, limit int) ([]Kline, error) {
+	bz, err := ctx.Query(fmt.Sprintf("dex/klines/%s/%s/%d", pair, interval, limit), nil)
+	if err != nil {
+		return nil, err
+	}
+	var klines []Kline
+	if err := cdc.UnmarshalBinaryLengthPrefixed(bz, &klines); err != nil {
+		return nil, err
+	}
+	return klines, nil
+}
+
+// GetTickers queries the 24 hours statistics of the pair, or of all the pairs if the pair is empty
+func GetTickers(cdc *wire.Codec, ctx context.CLIContext, pair string) ([]Ticker, error) {
+	path := "dex/ticker"
+	if pair != "" {
+		path = fmt.Sprintf("%s/%s", path, pair)
+	}
+	bz, err := ctx.Query(path, nil)
+	if err != nil {
+		return nil, err
+	}
+	var tickers []Ticker
+	if err := cdc.UnmarshalBinaryLengthPrefixed(bz, &tickers); err != nil {
+		return nil, err
+	}
+	return tickers, nil
+}
+
+// GetRecentTrades queries the latest trades of the pair
+func GetRecentTrades(cdc *wire.Codec, ctx context.CLIContext, pair string, limit int) ([]RecentTrade, error) {
+	bz, err := ctx.Query(fmt.Sprintf("dex/trades/%s/%d", pair, limit), nil)
+	if err != nil {
+		return nil, err
+	}
+	var trades []RecentTrade
+	if err := cdc.UnmarshalBinaryLengthPrefixed(bz, &trades); err != nil {
+		return nil, err
+	}
+	return trades, nil
+}
+
+// GetClosedOrders queries the closed orders of an address kept in the order history of the node
+func GetClosedOrders(cdc *wire.Codec, ctx context.CLIContext, params ClosedOrdersQueryParams) (*ClosedOrders, error) {
+	data, err := cdc.MarshalJSON(params)
+	if err != nil {
+		return nil, err
+	}
+	bz, err := ctx.QueryWithData("dex/closedorders", data)
+	if err != nil {
+		return nil, err
+	}
+	var orders ClosedOrders
+	if err := cdc.UnmarshalBinaryLengthPrefixed(bz, &orders); err != nil {
+		return nil, err
+	}
+	return &orders, nil
+}
+
+// GetFeeEstimate queries the fees which would be charged for a hypothetical order
+func GetFeeEstimate(cdc *wire.Codec, ctx context.CLIContext, params FeeEstimateQueryParams) (*FeeEstimate, error) {
+	data, err := cdc.MarshalJSON(params)
+	if err != nil {
+		return nil, err
+	}
+	bz, err := ctx.QueryWithData("dex/feeestimate", data)
+	if err != nil {
+		return nil, err
+	}
+	var estimate FeeEstimate
+	if err := cdc.UnmarshalBinaryLengthPrefixed(bz, &estimate); err != nil {
+		return nil, err
+	}
+	return &estimate, nil
+}
+
+// GetOrderLimits queries the order limits of an address along with its open orders
+func GetOrderLimits(cdc *wire.Codec, ctx context.CLIContext, addr string) (*OrderLimits, error) {
+	bz, err := ctx.Query(fmt.Sprintf("dex/orderlimits/%s", addr), nil)
+	if err != nil {
+		return nil, err
+	}
+	var limits OrderLimits
+	if err := cdc.UnmarshalBinaryLengthPrefixed(bz, &limits); err != nil {
+		return nil, err
+	}
+	return &limits, nil
+}
@@ -19,6 +19,157 @@ type OrderBookLevel struct {
 	SellPrice utils.Fixed8 `json:"sellPrice"`
 }
 
+// OrderBookL3 represents the resting orders of an order book at the block height, price level by price level.
+type OrderBookL3 struct {
+	Height int64              `json:"height"`
+	Buys   []OrderBookL3Level `json:"buys"`
+	Sells  []OrderBookL3Level `json:"sells"`
+}
+
+// OrderBookL3Level represents the resting orders of a price level in time priority.
+type OrderBookL3Level struct {
+	Price  utils.Fixed8       `json:"price"`
+	Orders []OrderBookL3Order `json:"orders"`
+}
+
+type OrderBookL3Order struct {
+	Id            string       `json:"id"`
+	Quantity      utils.Fixed8 `json:"quantity"` // the remaining quantity shown to the public
+	CreatedHeight int64        `json:"createdHeight"`
+}
+
+// IndicativePrice is where the next auction of a pair would conclude if the order book was matched at the height.
+type IndicativePrice struct {
+	Height      int64        `json:"height"`
+	Price       utils.Fixed8 `json:"price"`       // 0 if the order book is not crossed
+	Quantity    utils.Fixed8 `json:"quantity"`    // the executable volume
+	Surplus     utils.Fixed8 `json:"surplus"`     // the quantity left unfilled at the price
+	SurplusSide string       `json:"surplusSide"` // BUY or SELL, empty if there is no surplus
+}
+
+// Kline is the candlestick of a pair in an interval, the times are in unix seconds.
+type Kline struct {
+	OpenTime    int64        `json:"openTime"`
+	CloseTime   int64        `json:"closeTime"`
+	Open        utils.Fixed8 `json:"open"`
+	High        utils.Fixed8 `json:"high"`
+	Low         utils.Fixed8 `json:"low"`
+	Close       utils.Fixed8 `json:"close"`
+	Volume      utils.Fixed8 `json:"volume"`
+	QuoteVolume utils.Fixed8 `json:"quoteVolume"`
+	NumOfTrades int64        `json:"numOfTrades"`
+}
+
+// Ticker is the statistics of the trades of a pair in the 24 hours before CloseTime, the times are in unix seconds.
+type Ticker struct {
+	Symbol      string       `json:"symbol"`
+	OpenTime    int64        `json:"openTime"`
+	CloseTime   int64        `json:"closeTime"`
+	LastPrice   utils.Fixed8 `json:"lastPrice"`
+	OpenPrice   utils.Fixed8 `json:"openPrice"` // the open, high and low prices are 0 if there is no trade in the 24 hours
+	HighPrice   utils.Fixed8 `json:"highPrice"`
+	LowPrice    utils.Fixed8 `json:"lowPrice"`
+	Volume      utils.Fixed8 `json:"volume"`
+	QuoteVolume utils.Fixed8 `json:"quoteVolume"`
+	Count       int64        `json:"count"`
+	BidPrice    utils.Fixed8 `json:"bidPrice"`
+	BidQuantity utils.Fixed8 `json:"bidQuantity"`
+	AskPrice    utils.Fixed8 `json:"askPrice"`
+	AskQuantity utils.Fixed8 `json:"askQuantity"`
+}
+
+// RecentTrade is a trade of a pair concluded in a recent block
+type RecentTrade struct {
+	Price         utils.Fixed8 `json:"price"`
+	Quantity      utils.Fixed8 `json:"quantity"`
+	BuyerOrderId  string       `json:"buyerOrderId"`
+	SellerOrderId string       `json:"sellerOrderId"`
+	MakerSide     string       `json:"makerSide"` // BUY or SELL, empty if both orders were resting in the auction
+	Height        int64        `json:"height"`
+	Time          int64        `json:"time"` // unix nanoseconds
+}
+
+// ClosedOrder is an order which is filled, canceled, expired or rejected, kept in the order history of the node
+type ClosedOrder struct {
+	Id               string       `json:"id"`
+	Symbol           string       `json:"symbol"`
+	Owner            string       `json:"owner"`
+	Side             int8         `json:"side"`
+	OrderType        int8         `json:"orderType"`
+	TimeInForce      int8         `json:"timeInForce"`
+	Price            utils.Fixed8 `json:"price"`
+	Quantity         utils.Fixed8 `json:"quantity"`
+	CumQty           utils.Fixed8 `json:"cumQty"`
+	Status           string       `json:"status"`
+	CreatedHeight    int64        `json:"createdHeight"`
+	CreatedTimestamp int64        `json:"createdTimestamp"`
+	ClosedHeight     int64        `json:"closedHeight"`
+	ClosedTimestamp  int64        `json:"closedTimestamp"`
+	TxHash           string       `json:"txHash"`
+}
+
+// ClosedOrders is a page of the closed orders of an address, along with the number of all the matched orders
+type ClosedOrders struct {
+	Total  int           `json:"total"`
+	Orders []ClosedOrder `json:"orders"`
+}
+
+// ClosedOrdersQueryParams filters the closed orders of an address. The empty symbol and status, and the zero heights
+// match all the orders.
+type ClosedOrdersQueryParams struct {
+	Address    string `json:"address"`
+	Symbol     string `json:"symbol"`
+	Status     string `json:"status"`
+	FromHeight int64  `json:"fromHeight"`
+	ToHeight   int64  `json:"toHeight"`
+	Offset     int    `json:"offset"`
+	Limit      int    `json:"limit"`
+}
+
+// FeeEstimateQueryParams is a hypothetical order of the sender, the price and quantity are in the raw Fixed8 values
+type FeeEstimateQueryParams struct {
+	Address  string `json:"address"`
+	Symbol   string `json:"symbol"`
+	Side     int8   `json:"side"`
+	Price    int64  `json:"price"`
+	Quantity int64  `json:"quantity"`
+}
+
+// FeeEstimate is the fees which would be charged for an order at the current prices and balances of the sender.
+// The trade fee is the one charged to the taker when the order is fully filled.
+type FeeEstimate struct {
+	Trade     FeeCandidates `json:"trade"`
+	Cancel    FeeCandidates `json:"cancel"`
+	Expire    FeeCandidates `json:"expire"`
+	IOCExpire FeeCandidates `json:"iocExpire"`
+}
+
+// FeeCandidates is a fee in each asset it may be paid in, i.e. the native token and the asset the fee falls back to,
+// along with the one charged by the balances of the sender
+type FeeCandidates struct {
+	Charged    FeeAmount   `json:"charged"`
+	Candidates []FeeAmount `json:"candidates"`
+}
+
+type FeeAmount struct {
+	Asset  string       `json:"asset"`
+	Amount utils.Fixed8 `json:"amount"`
+}
+
+// OrderLimits is the limits of the orders an account can place, 0 for no limit, along with the open orders of the account
+type OrderLimits struct {
+	MaxOpenOrdersPerPair int64            `json:"maxOpenOrdersPerPair"`
+	MaxOpenOrders        int64            `json:"maxOpenOrders"`
+	MaxNewOrdersPerBlock int64            `json:"maxNewOrdersPerBlock"`
+	OpenOrders           int64            `json:"openOrders"`
+	OpenOrdersPerPair    []PairOpenOrders `json:"openOrdersPerPair"`
+}
+
+type PairOpenOrders struct {
+	Symbol string `json:"symbol"`
+	Count  int64  `json:"count"`
+}
+
 type OpenOrder struct {
 	Id                   string       `json:"id"`
 	Symbol               string       `json:"symbol"`
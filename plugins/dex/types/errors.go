@@ -18,6 +18,10 @@ const (
 	CodeFailLocateOrderToCancel sdk.CodeType = 405
 	CodeDuplicatedOrder         sdk.CodeType = 406
 	CodeInvalidProposal         sdk.CodeType = 407
+	CodeFailAmendOrder          sdk.CodeType = 408
+	CodeFailLocateOrderToAmend  sdk.CodeType = 409
+	CodeTradingPairNotTrading   sdk.CodeType = 410
+	CodeOrderLimitExceeded      sdk.CodeType = 411
 )
 
 // ErrIncorrectDexOperation - Error returned upon an incorrect guess
@@ -36,3 +40,11 @@ func ErrInvalidTradeSymbol(err string) sdk.Error {
 func ErrInvalidProposal(err string) sdk.Error {
 	return sdk.NewError(DefaultCodespace, CodeInvalidProposal, fmt.Sprintf("Invalid proposal: %s", err))
 }
+
+func ErrTradingPairNotTrading(symbol string, status TradingStatus) sdk.Error {
+	return sdk.NewError(DefaultCodespace, CodeTradingPairNotTrading, fmt.Sprintf("Trading pair %s is %s", symbol, status))
+}
+
+func ErrOrderLimitExceeded(err string) sdk.Error {
+	return sdk.NewError(DefaultCodespace, CodeOrderLimitExceeded, fmt.Sprintf("Order limit exceeded: %s", err))
+}
@@ -1,16 +1,90 @@
 package types
 
 import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
 	ctuils "github.com/bnb-chain/node/common/utils"
 	"github.com/bnb-chain/node/plugins/dex/utils"
 )
 
+type TradingStatus int8
+
+const (
+	TradingStatusTrading    TradingStatus = iota // orders can be placed, amended and canceled, and are matched
+	TradingStatusCancelOnly                      // orders can only be canceled, and are not matched
+	TradingStatusHalted                          // orders can neither be placed nor canceled, and are not matched
+)
+
+func (status TradingStatus) String() string {
+	switch status {
+	case TradingStatusTrading:
+		return "trading"
+	case TradingStatusCancelOnly:
+		return "cancel_only"
+	case TradingStatusHalted:
+		return "halted"
+	default:
+		return "unknown"
+	}
+}
+
+func (status TradingStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(status.String())
+}
+
+func (status *TradingStatus) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+	parsed, err := ParseTradingStatus(name)
+	if err != nil {
+		return err
+	}
+	*status = parsed
+	return nil
+}
+
+func ParseTradingStatus(name string) (TradingStatus, error) {
+	switch name {
+	case "trading":
+		return TradingStatusTrading, nil
+	case "cancel_only":
+		return TradingStatusCancelOnly, nil
+	case "halted":
+		return TradingStatusHalted, nil
+	default:
+		return TradingStatusTrading, fmt.Errorf("invalid trading status %s", name)
+	}
+}
+
 type TradingPair struct {
 	BaseAssetSymbol  string        `json:"base_asset_symbol"`
 	QuoteAssetSymbol string        `json:"quote_asset_symbol"`
 	ListPrice        ctuils.Fixed8 `json:"list_price"`
 	TickSize         ctuils.Fixed8 `json:"tick_size"`
 	LotSize          ctuils.Fixed8 `json:"lot_size"`
+	Status           TradingStatus `json:"status"`
+	MinNotional      ctuils.Fixed8 `json:"min_notional"` // minimum notional of an order in the quote asset, 0 for no limit
+	MaxQuantity      ctuils.Fixed8 `json:"max_quantity"` // maximum quantity of an order, 0 for no limit
+}
+
+// TradingStatusParamsType tells the trading status proposals apart from the other parameter change proposals
+const TradingStatusParamsType = "dex/TradingStatus"
+
+// TradingStatusParams is the description of a proposal changing the trading status or the order size limits of a pair,
+// the fields left empty are unchanged
+type TradingStatusParams struct {
+	Type             string `json:"type"`                   // must be TradingStatusParamsType
+	BaseAssetSymbol  string `json:"base_asset_symbol"`      // base asset symbol
+	QuoteAssetSymbol string `json:"quote_asset_symbol"`     // quote asset symbol
+	Status           string `json:"status,omitempty"`       // trading status to change to
+	MinNotional      *int64 `json:"min_notional,omitempty"` // minimum notional of an order to change to, 0 for no limit
+	MaxQuantity      *int64 `json:"max_quantity,omitempty"` // maximum quantity of an order to change to, 0 for no limit
+	Justification    string `json:"justification"`          // justification
+	IsExecuted       bool   `json:"is_executed"`            // is this proposal executed
 }
 
 // NOTE: only for test use
@@ -30,6 +104,17 @@ func NewTradingPairWithLotSize(baseAsset, quoteAsset string, listPrice, lotSize
 	}
 }
 
+// ValidateOrderSize checks the order of the price and quantity against the order size limits of the pair
+func (pair *TradingPair) ValidateOrderSize(price, quantity int64) error {
+	if pair.MaxQuantity > 0 && quantity > pair.MaxQuantity.ToInt64() {
+		return fmt.Errorf("quantity(%v) is greater than the maximum quantity(%v) of %s", quantity, pair.MaxQuantity.ToInt64(), pair.GetSymbol())
+	}
+	if pair.MinNotional > 0 && utils.CalBigNotional(price, quantity).Cmp(big.NewInt(pair.MinNotional.ToInt64())) < 0 {
+		return fmt.Errorf("notional of the order is less than the minimum notional(%v) of %s", pair.MinNotional.ToInt64(), pair.GetSymbol())
+	}
+	return nil
+}
+
 func (pair *TradingPair) GetSymbol() string {
 	return utils.Assets2TradingPair(pair.BaseAssetSymbol, pair.QuoteAssetSymbol)
 }
@@ -0,0 +1,29 @@
+package types
+
+import (
+	"github.com/cosmos/cosmos-sdk/pubsub"
+)
+
+const MarketStatusTopic = pubsub.Topic("market-status")
+
+// MarketStatusEvent is published when the trading status of a pair is changed by a proposal
+type MarketStatusEvent struct {
+	Symbol     string
+	Status     TradingStatus
+	ProposalId int64
+}
+
+func (event MarketStatusEvent) GetTopic() pubsub.Topic {
+	return MarketStatusTopic
+}
+
+// CircuitBreakerEvent is published when the matching of a pair is suspended or resumed by the circuit breaker
+type CircuitBreakerEvent struct {
+	Symbol       string
+	Suspended    bool
+	ResumeHeight int64
+}
+
+func (event CircuitBreakerEvent) GetTopic() pubsub.Topic {
+	return MarketStatusTopic
+}
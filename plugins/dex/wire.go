@@ -13,6 +13,8 @@ func RegisterWire(cdc *wire.Codec) {
 
 	cdc.RegisterConcrete(order.NewOrderMsg{}, "dex/NewOrder", nil)
 	cdc.RegisterConcrete(order.CancelOrderMsg{}, "dex/CancelOrder", nil)
+	cdc.RegisterConcrete(order.AmendOrderMsg{}, "dex/AmendOrder", nil)
+	cdc.RegisterConcrete(order.CancelAllOrdersMsg{}, "dex/CancelAllOrders", nil)
 
 	cdc.RegisterConcrete(types.ListMsg{}, "dex/ListMsg", nil)
 	cdc.RegisterConcrete(types.TradingPair{}, "dex/TradingPair", nil)
@@ -20,6 +22,9 @@ func RegisterWire(cdc *wire.Codec) {
 	cdc.RegisterConcrete(types.ListMiniMsg{}, "dex/ListMiniMsg", nil)
 
 	cdc.RegisterConcrete(order.FeeConfig{}, "dex/FeeConfig", nil)
+	cdc.RegisterConcrete(&order.SelfTradePreventionParam{}, "dex/SelfTradePreventionParam", nil)
+	cdc.RegisterConcrete(&order.CircuitBreakerParam{}, "dex/CircuitBreakerParam", nil)
+	cdc.RegisterConcrete(&order.OrderLimitsParam{}, "dex/OrderLimitsParam", nil)
 	cdc.RegisterConcrete(order.OrderBookSnapshot{}, "dex/OrderBookSnapshot", nil)
 	cdc.RegisterConcrete(order.ActiveOrders{}, "dex/ActiveOrders", nil)
 	cdc.RegisterConcrete(store.RecentPrice{}, "dex/RecentPrice", nil)